@@ -0,0 +1,209 @@
+// Package client is a client of the server built on the respparser package. It keeps a pool
+// of connections, reconnects broken ones and offers typed helpers of the supported commands.
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+const (
+	DefaultPoolSize    = 10
+	DefaultDialTimeout = 5 * time.Second
+
+	retryBackoff    = 8 * time.Millisecond
+	maxRetryBackoff = 512 * time.Millisecond
+)
+
+// ErrNil is returned by typed helpers when the server replies with a null, e.g. GET of a missing key
+var ErrNil = errors.New("nil reply")
+
+// ErrClosed is returned for commands issued after the client was closed
+var ErrClosed = errors.New("client is closed")
+
+// Error is an error reply of the server, Code is its first word, e.g. ERR or WRONGTYPE
+type Error struct {
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return e.Code
+	}
+	return e.Code + " " + e.Message
+}
+
+func newError(reply respparser.SimpleError) *Error {
+	code, message, _ := strings.Cut(reply.Value, " ")
+	return &Error{Code: code, Message: message}
+}
+
+type Options struct {
+	Network string // tcp when empty, unix for unix sockets
+	Addr    string // host:port or path of the unix socket
+
+	Username   string // default user when empty
+	Password   string // no authentication when empty
+	ClientName string
+
+	// Protocol is the RESP version negotiated with HELLO, RESP2 when zero. Push messages
+	// are sent to OnPush, they can be received with RESP3 only.
+	Protocol respparser.Protocol
+	OnPush   func(push respparser.Push)
+
+	PoolSize    int           // maximum number of open connections, DefaultPoolSize when zero
+	DialTimeout time.Duration // DefaultDialTimeout when zero
+	// MaxRetries is the number of times commands are sent again after a connection failure, no retries
+	// when zero. A retried command may be executed twice, e.g. RPUSH or XADD with *, so retries suit
+	// clients sending only commands safe to repeat.
+	MaxRetries int
+}
+
+// Client is safe for concurrent use, each command takes a connection of the pool for its round trip
+type Client struct {
+	opts Options
+
+	slots chan struct{} // a slot is taken for every open connection
+	idle  chan *conn
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func New(opts Options) *Client {
+	if opts.Network == "" {
+		opts.Network = "tcp"
+	}
+	if opts.Protocol == 0 {
+		opts.Protocol = respparser.Resp2
+	}
+	if opts.PoolSize <= 0 {
+		opts.PoolSize = DefaultPoolSize
+	}
+	if opts.DialTimeout <= 0 {
+		opts.DialTimeout = DefaultDialTimeout
+	}
+
+	return &Client{
+		opts:  opts,
+		slots: make(chan struct{}, opts.PoolSize),
+		idle:  make(chan *conn, opts.PoolSize),
+	}
+}
+
+// Close closes idle connections, connections in use are closed once their command completes
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return ErrClosed
+	}
+	c.closed = true
+
+	for {
+		select {
+		case cn := <-c.idle:
+			cn.close()
+			<-c.slots
+		default:
+			return nil
+		}
+	}
+}
+
+// Do sends the command and returns its reply, error replies are returned as *Error
+func (c *Client) Do(ctx context.Context, args ...string) (respparser.RespData, error) {
+	replies, err := c.roundTrip(ctx, [][]string{args})
+	if err != nil {
+		return nil, err
+	}
+	if replyErr, ok := replies[0].(respparser.SimpleError); ok {
+		return nil, newError(replyErr)
+	}
+	return replies[0], nil
+}
+
+// roundTrip sends the commands on a single connection and reads their replies. With MaxRetries set, commands
+// are sent again on a new connection when the connection breaks before any reply was read, e.g. the server
+// closed an idle connection. A command may be executed twice if the connection broke after it was executed.
+func (c *Client) roundTrip(ctx context.Context, cmds [][]string) ([]respparser.RespData, error) {
+	for attempt := 0; ; attempt++ {
+		cn, err := c.get(ctx)
+		if err == nil {
+			var replies []respparser.RespData
+			replies, err = cn.roundTrip(ctx, cmds, c.opts.OnPush)
+			c.put(cn, err != nil)
+			if err == nil {
+				return replies, nil
+			} else if len(replies) > 0 {
+				return nil, err
+			}
+		}
+
+		if !c.retryable(ctx, err) || attempt >= c.opts.MaxRetries {
+			return nil, err
+		}
+		select {
+		case <-time.After(min(retryBackoff<<attempt, maxRetryBackoff)):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// retryable reports whether the error is a connection failure the command can be sent again after
+func (c *Client) retryable(ctx context.Context, err error) bool {
+	var replyErr *Error
+	return ctx.Err() == nil &&
+		!errors.Is(err, ErrClosed) &&
+		!errors.Is(err, respparser.ErrProtocol) &&
+		!errors.As(err, &replyErr)
+}
+
+// get takes an idle connection or opens a new one when the pool isn't full
+func (c *Client) get(ctx context.Context) (*conn, error) {
+	c.mu.Lock()
+	closed := c.closed
+	c.mu.Unlock()
+	if closed {
+		return nil, ErrClosed
+	}
+
+	select {
+	case cn := <-c.idle:
+		return cn, nil
+	default:
+	}
+
+	select {
+	case cn := <-c.idle:
+		return cn, nil
+	case c.slots <- struct{}{}:
+		cn, err := dial(ctx, c.opts)
+		if err != nil {
+			<-c.slots
+			return nil, err
+		}
+		return cn, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// put returns the connection to the pool, broken connections are closed
+func (c *Client) put(cn *conn, broken bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if broken || c.closed {
+		cn.close()
+		<-c.slots
+		return
+	}
+	c.idle <- cn
+}
@@ -0,0 +1,269 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/command"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/config"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/server"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+// startTestServer serves on a random local port in the test process
+func startTestServer(t *testing.T) string {
+	for _, nv := range [][2]string{{"bind", "127.0.0.1"}, {"port", "0"}} {
+		if err := config.Load(nv[0], nv[1]); err != nil {
+			t.Fatalf("ERROR can't configure server: %s", err.Error())
+		}
+	}
+
+	s := server.New()
+	if err := s.Listen(); err != nil {
+		t.Fatalf("ERROR can't start server: %s", err.Error())
+	}
+
+	exitStatus := make(chan int, 1)
+	go func() { exitStatus <- s.Serve() }()
+	t.Cleanup(func() {
+		s.Shutdown(command.ShutdownOptions{})
+		<-exitStatus
+	})
+	return s.Addrs()[0].String()
+}
+
+func newTestClient(t *testing.T, opts Options) *Client {
+	c := New(opts)
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestTypedCommands(t *testing.T) {
+	addr := startTestServer(t)
+	ctx := context.Background()
+
+	for _, protocol := range []respparser.Protocol{respparser.Resp2, respparser.Resp3} {
+		t.Run("RESP"+strconv.Itoa(int(protocol)), func(t *testing.T) {
+			c := newTestClient(t, Options{Addr: addr, Protocol: protocol})
+			prefix := "client-" + strconv.Itoa(int(protocol)) + ":"
+
+			if err := c.Ping(ctx); err != nil {
+				t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+			}
+			if _, err := c.Get(ctx, prefix+"missing"); !errors.Is(err, ErrNil) {
+				t.Errorf("ERROR got %v, want %v", err, ErrNil)
+			}
+
+			if err := c.Set(ctx, prefix+"key", "value", time.Minute); err != nil {
+				t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+			}
+			if value, err := c.Get(ctx, prefix+"key"); err != nil || value != "value" {
+				t.Errorf("ERROR got %q, %v, want value", value, err)
+			}
+			if valueType, _ := c.Type(ctx, prefix+"key"); valueType != "string" {
+				t.Errorf("ERROR got %q, want string", valueType)
+			}
+
+			if length, err := c.RPush(ctx, prefix+"list", "a", "b", "c"); err != nil || length != 3 {
+				t.Errorf("ERROR got %d, %v, want 3", length, err)
+			}
+			if values, _ := c.LRange(ctx, prefix+"list", 1, -1); !slices.Equal(values, []string{"b", "c"}) {
+				t.Errorf("ERROR got %q, want [b c]", values)
+			}
+
+			fields := map[string]string{"temperature": "36", "humidity": "95"}
+			id, err := c.XAdd(ctx, prefix+"stream", "1-1", fields)
+			if err != nil || id != "1-1" {
+				t.Fatalf("ERROR got %q, %v, want 1-1", id, err)
+			}
+			messages, err := c.XRange(ctx, prefix+"stream", "-", "+")
+			if err != nil || len(messages) != 1 || messages[0].ID != "1-1" || !maps.Equal(messages[0].Values, fields) {
+				t.Errorf("ERROR got %v, %v, want entry 1-1 with %v", messages, err, fields)
+			}
+			streams, err := c.XRead(ctx, []string{prefix + "stream"}, []string{"0-0"})
+			if err != nil || len(streams) != 1 || streams[0].Stream != prefix+"stream" || len(streams[0].Messages) != 1 {
+				t.Errorf("ERROR got %v, %v, want single stream entry", streams, err)
+			}
+
+			if params, err := c.ConfigGet(ctx, "bind"); err != nil || params["bind"] != "127.0.0.1" {
+				t.Errorf("ERROR got %v, %v, want bind 127.0.0.1", params, err)
+			}
+
+			var replyErr *Error
+			if _, err := c.Do(ctx, "NOSUCHCOMMAND"); !errors.As(err, &replyErr) || replyErr.Code != "ERR" {
+				t.Errorf("ERROR got %v, want ERR reply", err)
+			}
+		})
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	addr := startTestServer(t)
+	c := newTestClient(t, Options{Addr: addr, PoolSize: 1})
+
+	p := c.Pipeline()
+	for n := range 100 {
+		p.Queue("SET", "pipeline:"+strconv.Itoa(n), strconv.Itoa(n))
+	}
+	p.Queue("GET", "pipeline:42")
+	p.Queue("LRANGE", "pipeline:list", "x", "y")
+
+	replies, err := p.Exec(context.Background())
+	if err != nil {
+		t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+	}
+	if len(replies) != 102 || p.Len() != 0 {
+		t.Fatalf("ERROR got %d replies, want 102", len(replies))
+	}
+	if replies[0].String() != "OK" || replies[100].String() != "42" {
+		t.Errorf("ERROR got %v and %v, want OK and 42", replies[0], replies[100])
+	}
+	if replies[101].Type() != respparser.TypeSimpleError {
+		t.Errorf("ERROR got %v, want error reply", replies[101])
+	}
+}
+
+func TestBlockingReadCancel(t *testing.T) {
+	addr := startTestServer(t)
+	c := newTestClient(t, Options{Addr: addr})
+
+	if _, err := c.XReadBlock(context.Background(), 50*time.Millisecond, []string{"blocked-stream"}, []string{"$"}); !errors.Is(err, ErrNil) {
+		t.Errorf("ERROR got %v, want %v", err, ErrNil)
+	}
+
+	result := make(chan []XStream, 1)
+	go func() {
+		streams, _ := c.XReadBlock(context.Background(), 5*time.Second, []string{"blocked-stream"}, []string{"$"})
+		result <- streams
+	}()
+	time.Sleep(100 * time.Millisecond)
+	c.XAdd(context.Background(), "blocked-stream", "*", map[string]string{"field": "value"})
+
+	if streams := <-result; len(streams) != 1 || streams[0].Messages[0].Values["field"] != "value" {
+		t.Errorf("ERROR got %v, want the added entry", streams)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	if _, err := c.XReadBlock(ctx, 0, []string{"blocked-stream"}, []string{"$"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("ERROR got %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestReconnect(t *testing.T) {
+	addr := startTestServer(t)
+	ctx := context.Background()
+	c := newTestClient(t, Options{Addr: addr, PoolSize: 1, ClientName: "reconnecting", MaxRetries: 1})
+	admin := newTestClient(t, Options{Addr: addr})
+
+	id, err := c.Do(ctx, "CLIENT", "ID")
+	if err != nil {
+		t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+	}
+	if _, err := admin.Do(ctx, "CLIENT", "KILL", "ID", id.String()); err != nil {
+		t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+	}
+
+	// the pooled connection is closed by the server, the command is sent again on a new one
+	newId, err := c.Do(ctx, "CLIENT", "ID")
+	if err != nil || newId.String() == id.String() {
+		t.Fatalf("ERROR got %v, %v, want id of a new connection", newId, err)
+	}
+	if name, err := c.Do(ctx, "CLIENT", "GETNAME"); err != nil || name.String() != "reconnecting" {
+		t.Errorf("ERROR got %v, %v, want name set by handshake", name, err)
+	}
+}
+
+func TestNoRetriesByDefault(t *testing.T) {
+	addr := startTestServer(t)
+	ctx := context.Background()
+	c := newTestClient(t, Options{Addr: addr, PoolSize: 1})
+	admin := newTestClient(t, Options{Addr: addr})
+	admin.Do(ctx, "DEL", "no-retries:list")
+
+	id, err := c.Do(ctx, "CLIENT", "ID")
+	if err != nil {
+		t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+	}
+	if _, err := admin.Do(ctx, "CLIENT", "KILL", "ID", id.String()); err != nil {
+		t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+	}
+
+	// the command isn't sent again, RPUSH mustn't be executed twice
+	if _, err := c.RPush(ctx, "no-retries:list", "a"); err == nil {
+		t.Fatalf("ERROR error expected for the killed connection")
+	}
+	if length, err := c.RPush(ctx, "no-retries:list", "b"); err != nil || length != 1 {
+		t.Errorf("ERROR got %d, %v, want 1 on a new connection", length, err)
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	if err := config.Set([][2]string{{"requirepass", "secret"}}); err != nil {
+		t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+	}
+	defer config.Set([][2]string{{"requirepass", ""}})
+	addr := startTestServer(t)
+
+	for _, protocol := range []respparser.Protocol{respparser.Resp2, respparser.Resp3} {
+		var replyErr *Error
+		if err := newTestClient(t, Options{Addr: addr, Protocol: protocol}).Ping(context.Background()); !errors.As(err, &replyErr) || replyErr.Code != "NOAUTH" {
+			t.Errorf("ERROR got %v, want NOAUTH", err)
+		}
+		if err := newTestClient(t, Options{Addr: addr, Protocol: protocol, Password: "wrong"}).Ping(context.Background()); !errors.As(err, &replyErr) || replyErr.Code != "WRONGPASS" {
+			t.Errorf("ERROR got %v, want WRONGPASS", err)
+		}
+		if err := newTestClient(t, Options{Addr: addr, Protocol: protocol, Password: "secret"}).Ping(context.Background()); err != nil {
+			t.Errorf("ERROR result expected, but err got: %s", err.Error())
+		}
+	}
+}
+
+func TestPushMessages(t *testing.T) {
+	// remark: a fake server, push messages are sent before the reply they precede
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ERROR can't listen: %s", err.Error())
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		buf := make([]byte, 1024)
+		conn.Read(buf)
+		conn.Write([]byte("%1\r\n$5\r\nproto\r\n:3\r\n"))
+		conn.Read(buf)
+		conn.Write([]byte(">3\r\n$7\r\nmessage\r\n$4\r\nnews\r\n$5\r\nhello\r\n+PONG\r\n"))
+		conn.Read(buf)
+	}()
+
+	pushes := []respparser.Push{}
+	c := newTestClient(t, Options{
+		Addr:     listener.Addr().String(),
+		Protocol: respparser.Resp3,
+		OnPush:   func(push respparser.Push) { pushes = append(pushes, push) },
+	})
+	if reply, err := c.Do(context.Background(), "PING"); err != nil || reply.String() != "PONG" {
+		t.Fatalf("ERROR got %v, %v, want PONG", reply, err)
+	}
+	if len(pushes) != 1 || pushes[0].Items[2].String() != "hello" {
+		t.Errorf("ERROR got %v, want single push message", pushes)
+	}
+}
+
+func TestClosedClient(t *testing.T) {
+	c := New(Options{Addr: "127.0.0.1:1"})
+	c.Close()
+	if err := c.Ping(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Errorf("ERROR got %v, want %v", err, ErrClosed)
+	}
+}
@@ -0,0 +1,249 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+// XMessage is a stream entry
+type XMessage struct {
+	ID     string
+	Values map[string]string
+}
+
+// XStream holds entries of a single stream read by XREAD
+type XStream struct {
+	Stream   string
+	Messages []XMessage
+}
+
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.Do(ctx, "PING")
+	return err
+}
+
+func (c *Client) Echo(ctx context.Context, message string) (string, error) {
+	return c.doString(ctx, "ECHO", message)
+}
+
+// Get returns ErrNil when the key doesn't exist
+func (c *Client) Get(ctx context.Context, key string) (string, error) {
+	return c.doString(ctx, "GET", key)
+}
+
+// Set sets the value, the key expires after expiration unless it's zero
+func (c *Client) Set(ctx context.Context, key string, value string, expiration time.Duration) error {
+	args := []string{"SET", key, value}
+	if expiration > 0 {
+		args = append(args, "PX", strconv.FormatInt(expiration.Milliseconds(), 10))
+	}
+	_, err := c.Do(ctx, args...)
+	return err
+}
+
+// Type returns type of the value stored at key, none when the key doesn't exist
+func (c *Client) Type(ctx context.Context, key string) (string, error) {
+	return c.doString(ctx, "TYPE", key)
+}
+
+// XAdd appends the entry and returns its ID, id is * to let the server generate it
+func (c *Client) XAdd(ctx context.Context, stream string, id string, values map[string]string) (string, error) {
+	args := []string{"XADD", stream, id}
+	for _, field := range slices.Sorted(maps.Keys(values)) {
+		args = append(args, field, values[field])
+	}
+	return c.doString(ctx, args...)
+}
+
+// XRange returns entries with IDs between start and end inclusive, - and + stand for the first and the last entry
+func (c *Client) XRange(ctx context.Context, stream string, start string, end string) ([]XMessage, error) {
+	reply, err := c.Do(ctx, "XRANGE", stream, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return toXMessages(reply)
+}
+
+// XRead returns entries with IDs greater than ids[n] of streams[n]
+func (c *Client) XRead(ctx context.Context, streams []string, ids []string) ([]XStream, error) {
+	return c.xread(ctx, nil, streams, ids)
+}
+
+// XReadBlock waits up to timeout for entries when there are none yet, zero timeout waits until the context
+// is done. ErrNil is returned when the timeout expires, $ as id waits for entries added after the call.
+func (c *Client) XReadBlock(ctx context.Context, timeout time.Duration, streams []string, ids []string) ([]XStream, error) {
+	return c.xread(ctx, []string{"BLOCK", strconv.FormatInt(timeout.Milliseconds(), 10)}, streams, ids)
+}
+
+func (c *Client) xread(ctx context.Context, options []string, streams []string, ids []string) ([]XStream, error) {
+	if len(streams) != len(ids) {
+		return nil, fmt.Errorf("got %d streams, but %d ids", len(streams), len(ids))
+	}
+	args := append([]string{"XREAD"}, options...)
+	args = append(append(append(args, "STREAMS"), streams...), ids...)
+
+	reply, err := c.Do(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	items, err := toItems(reply)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]XStream, len(items))
+	for n, item := range items {
+		pair, err := toItems(item)
+		if err != nil {
+			return nil, err
+		} else if len(pair) != 2 {
+			return nil, fmt.Errorf("unexpected XREAD reply %s", item.String())
+		}
+		messages, err := toXMessages(pair[1])
+		if err != nil {
+			return nil, err
+		}
+		result[n] = XStream{Stream: pair[0].String(), Messages: messages}
+	}
+	return result, nil
+}
+
+// RPush appends the values and returns length of the list
+func (c *Client) RPush(ctx context.Context, key string, values ...string) (int, error) {
+	return c.doInt(ctx, append([]string{"RPUSH", key}, values...)...)
+}
+
+// LRange returns elements between start and stop inclusive, negative indexes are counted from the tail
+func (c *Client) LRange(ctx context.Context, key string, start int, stop int) ([]string, error) {
+	reply, err := c.Do(ctx, "LRANGE", key, strconv.Itoa(start), strconv.Itoa(stop))
+	if err != nil {
+		return nil, err
+	}
+	return toStrings(reply)
+}
+
+// ConfigGet returns parameters matching the glob style pattern
+func (c *Client) ConfigGet(ctx context.Context, pattern string) (map[string]string, error) {
+	reply, err := c.Do(ctx, "CONFIG", "GET", pattern)
+	if err != nil {
+		return nil, err
+	}
+	return toStringMap(reply)
+}
+
+func (c *Client) ConfigSet(ctx context.Context, name string, value string) error {
+	_, err := c.Do(ctx, "CONFIG", "SET", name, value)
+	return err
+}
+
+func (c *Client) doString(ctx context.Context, args ...string) (string, error) {
+	reply, err := c.Do(ctx, args...)
+	if err != nil {
+		return "", err
+	}
+	if isNull(reply) {
+		return "", ErrNil
+	}
+	return reply.String(), nil
+}
+
+func (c *Client) doInt(ctx context.Context, args ...string) (int, error) {
+	reply, err := c.Do(ctx, args...)
+	if err != nil {
+		return 0, err
+	}
+	integer, ok := reply.(respparser.Integer)
+	if !ok {
+		return 0, fmt.Errorf("integer reply expected, but got %s", reply.DebugString())
+	}
+	return integer.Value, nil
+}
+
+func isNull(reply respparser.RespData) bool {
+	switch r := reply.(type) {
+	case respparser.Null:
+		return true
+	case respparser.BulkString:
+		return r.IsNull
+	case respparser.Array:
+		return r.IsNull
+	}
+	return false
+}
+
+// toItems returns items of an array or a set, ErrNil for a null reply
+func toItems(reply respparser.RespData) ([]respparser.RespData, error) {
+	if isNull(reply) {
+		return nil, ErrNil
+	}
+	switch r := reply.(type) {
+	case respparser.Array:
+		return r.Items, nil
+	case respparser.Set:
+		return r.Items, nil
+	}
+	return nil, fmt.Errorf("array reply expected, but got %s", reply.DebugString())
+}
+
+func toStrings(reply respparser.RespData) ([]string, error) {
+	items, err := toItems(reply)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]string, len(items))
+	for n, item := range items {
+		values[n] = item.String()
+	}
+	return values, nil
+}
+
+// toStringMap accepts a RESP3 map as well as a RESP2 flat array of keys and values
+func toStringMap(reply respparser.RespData) (map[string]string, error) {
+	if m, ok := reply.(respparser.Map); ok {
+		values := make(map[string]string, len(m.Items))
+		for _, item := range m.Items {
+			values[item.Key.String()] = item.Value.String()
+		}
+		return values, nil
+	}
+
+	items, err := toStrings(reply)
+	if err != nil {
+		return nil, err
+	} else if len(items)%2 != 0 {
+		return nil, fmt.Errorf("even number of items expected, but got %d", len(items))
+	}
+	values := make(map[string]string, len(items)/2)
+	for n := 0; n < len(items); n += 2 {
+		values[items[n]] = items[n+1]
+	}
+	return values, nil
+}
+
+func toXMessages(reply respparser.RespData) ([]XMessage, error) {
+	items, err := toItems(reply)
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]XMessage, len(items))
+	for n, item := range items {
+		entry, err := toItems(item)
+		if err != nil {
+			return nil, err
+		} else if len(entry) != 2 {
+			return nil, fmt.Errorf("unexpected stream entry %s", item.String())
+		}
+		values, err := toStringMap(entry[1])
+		if err != nil {
+			return nil, err
+		}
+		messages[n] = XMessage{ID: entry[0].String(), Values: values}
+	}
+	return messages, nil
+}
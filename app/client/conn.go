@@ -0,0 +1,150 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+// conn is a single connection, it's used by one round trip at a time
+type conn struct {
+	netConn net.Conn
+	decoder *respparser.Decoder
+	encoder *respparser.Encoder
+}
+
+// dial opens a connection and runs the handshake, i.e. protocol negotiation, authentication and client name
+func dial(ctx context.Context, opts Options) (*conn, error) {
+	dialer := net.Dialer{Timeout: opts.DialTimeout}
+	netConn, err := dialer.DialContext(ctx, opts.Network, opts.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	cn := &conn{
+		netConn: netConn,
+		decoder: respparser.NewReplyDecoder(bufio.NewReader(netConn)),
+		encoder: respparser.NewEncoder(bufio.NewWriter(netConn)),
+	}
+	if err := cn.handshake(ctx, opts); err != nil {
+		cn.close()
+		return nil, err
+	}
+	return cn, nil
+}
+
+func (cn *conn) handshake(ctx context.Context, opts Options) error {
+	cmds := [][]string{}
+	if opts.Protocol != respparser.Resp2 {
+		hello := []string{"HELLO", strconv.Itoa(int(opts.Protocol))}
+		if opts.Password != "" {
+			hello = append(hello, "AUTH", username(opts), opts.Password)
+		}
+		if opts.ClientName != "" {
+			hello = append(hello, "SETNAME", opts.ClientName)
+		}
+		cmds = append(cmds, hello)
+	} else {
+		if opts.Password != "" {
+			cmds = append(cmds, []string{"AUTH", username(opts), opts.Password})
+		}
+		if opts.ClientName != "" {
+			cmds = append(cmds, []string{"CLIENT", "SETNAME", opts.ClientName})
+		}
+	}
+	if len(cmds) == 0 {
+		return nil
+	}
+
+	replies, err := cn.roundTrip(ctx, cmds, opts.OnPush)
+	if err != nil {
+		return err
+	}
+	for _, reply := range replies {
+		if replyErr, ok := reply.(respparser.SimpleError); ok {
+			return newError(replyErr)
+		}
+	}
+	return nil
+}
+
+func username(opts Options) string {
+	if opts.Username == "" {
+		return "default"
+	}
+	return opts.Username
+}
+
+// roundTrip writes all commands at once and reads one reply per command, push messages
+// received in between are passed to onPush. Replies read before a failure are returned with the error,
+// the connection can't be used any more after a failure.
+func (cn *conn) roundTrip(ctx context.Context, cmds [][]string, onPush func(respparser.Push)) (replies []respparser.RespData, err error) {
+	deadline, _ := ctx.Deadline()
+	cn.netConn.SetDeadline(deadline)
+	// remark: a cancelled context interrupts blocked reads and writes by moving the deadline to the past
+	interrupted := make(chan struct{})
+	stop := context.AfterFunc(ctx, func() {
+		cn.netConn.SetDeadline(time.Unix(1, 0))
+		close(interrupted)
+	})
+	defer func() {
+		// remark: wait for the interruption to finish, so it can't affect the next round trip
+		if !stop() {
+			<-interrupted
+		}
+		if err != nil && ctx.Err() != nil {
+			err = ctx.Err()
+		}
+	}()
+
+	for _, args := range cmds {
+		request := respparser.Array{Items: make([]respparser.RespData, len(args))}
+		for n, arg := range args {
+			request.Items[n] = respparser.BulkString{Value: arg}
+		}
+		if err := cn.encoder.Encode(request); err != nil {
+			return nil, err
+		}
+	}
+	if err := cn.encoder.Flush(); err != nil {
+		return nil, err
+	}
+
+	replies = make([]respparser.RespData, 0, len(cmds))
+	for range cmds {
+		reply, err := cn.readReply(onPush)
+		if err != nil {
+			return replies, err
+		}
+		replies = append(replies, reply)
+	}
+	return replies, nil
+}
+
+// readReply skips push messages and returns the next reply without its attributes
+func (cn *conn) readReply(onPush func(respparser.Push)) (respparser.RespData, error) {
+	for {
+		reply, err := cn.decoder.Decode()
+		if err != nil {
+			return nil, err
+		}
+		switch r := reply.(type) {
+		case respparser.Push:
+			if onPush != nil {
+				onPush(r)
+			}
+		case respparser.Attribute:
+			return r.Value, nil
+		default:
+			return reply, nil
+		}
+	}
+}
+
+func (cn *conn) close() error {
+	return cn.netConn.Close()
+}
@@ -0,0 +1,39 @@
+package client
+
+import (
+	"context"
+
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+// Pipeline queues commands and sends them in a single round trip, it isn't safe for concurrent use
+type Pipeline struct {
+	client *Client
+	cmds   [][]string
+}
+
+func (c *Client) Pipeline() *Pipeline {
+	return &Pipeline{client: c}
+}
+
+// Queue adds the command to the pipeline
+func (p *Pipeline) Queue(args ...string) {
+	p.cmds = append(p.cmds, args)
+}
+
+// Len returns number of queued commands
+func (p *Pipeline) Len() int {
+	return len(p.cmds)
+}
+
+// Exec sends the queued commands and returns their replies in the same order, the pipeline is
+// empty afterwards. Error replies are returned as respparser.SimpleError items, the error is set
+// only when the replies couldn't be read.
+func (p *Pipeline) Exec(ctx context.Context) ([]respparser.RespData, error) {
+	cmds := p.cmds
+	p.cmds = nil
+	if len(cmds) == 0 {
+		return []respparser.RespData{}, nil
+	}
+	return p.client.roundTrip(ctx, cmds)
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+type outputMode int
+
+const (
+	outputPretty outputMode = iota // human readable, the default on a terminal
+	outputRaw                      // values as they are, one per line
+	outputCSV                      // comma separated quoted values
+)
+
+// formatReply formats the reply in the output mode, the result ends with a new line
+func formatReply(reply respparser.RespData, mode outputMode) string {
+	switch mode {
+	case outputRaw:
+		return formatRaw(reply) + "\n"
+	case outputCSV:
+		return formatCSV(reply) + "\n"
+	default:
+		return formatPretty(reply, "")
+	}
+}
+
+// repr returns the value double quoted with non printable bytes escaped
+func repr(value string) string {
+	quoted := utils.QuoteArg(value)
+	if quoted == value {
+		return `"` + value + `"`
+	}
+	return quoted
+}
+
+// aggregateItems returns items of aggregate types, map keys and values are interleaved
+func aggregateItems(reply respparser.RespData) ([]respparser.RespData, bool) {
+	switch r := reply.(type) {
+	case respparser.Array:
+		if r.IsNull {
+			return nil, false
+		}
+		return r.Items, true
+	case respparser.Set:
+		return r.Items, true
+	case respparser.Push:
+		return r.Items, true
+	case respparser.Map:
+		items := make([]respparser.RespData, 0, 2*len(r.Items))
+		for _, item := range r.Items {
+			items = append(items, item.Key, item.Value)
+		}
+		return items, true
+	}
+	return nil, false
+}
+
+func isNullArray(reply respparser.RespData) bool {
+	array, ok := reply.(respparser.Array)
+	return ok && array.IsNull
+}
+
+// formatPretty formats the reply the way redis-cli does on a terminal, items of aggregate
+// types are numbered and nested items are indented under their parent item
+func formatPretty(reply respparser.RespData, prefix string) string {
+	switch r := reply.(type) {
+	case respparser.SimpleError:
+		return "(error) " + r.Value + "\n"
+	case respparser.SimpleString:
+		return r.Value + "\n"
+	case respparser.Integer:
+		return "(integer) " + strconv.Itoa(r.Value) + "\n"
+	case respparser.Double:
+		return "(double) " + r.String() + "\n"
+	case respparser.BigNumber:
+		return "(big number) " + r.Value + "\n"
+	case respparser.Boolean:
+		if r.Value {
+			return "(true)\n"
+		}
+		return "(false)\n"
+	case respparser.Null:
+		return "(nil)\n"
+	case respparser.BulkString:
+		if r.IsNull {
+			return "(nil)\n"
+		}
+		return repr(r.Value) + "\n"
+	case respparser.VerbatimString:
+		return r.Value + "\n"
+	case respparser.Attribute:
+		return formatPretty(r.Value, prefix)
+	}
+
+	items, ok := aggregateItems(reply)
+	if !ok {
+		return "(nil)\n"
+	}
+	if len(items) == 0 {
+		switch reply.Type() {
+		case respparser.TypeSet:
+			return "(empty set)\n"
+		case respparser.TypeMap:
+			return "(empty hash)\n"
+		default:
+			return "(empty array)\n"
+		}
+	}
+
+	count := len(items)
+	separator := ")"
+	switch reply.Type() {
+	case respparser.TypeSet:
+		separator = "~"
+	case respparser.TypeMap:
+		count /= 2
+		separator = "#"
+	}
+	indexWidth := len(strconv.Itoa(count))
+	itemPrefix := prefix + strings.Repeat(" ", indexWidth+2)
+
+	var out strings.Builder
+	for n := 0; n < len(items); n++ {
+		index := n + 1
+		if reply.Type() == respparser.TypeMap {
+			index = n/2 + 1
+		}
+		// remark: the parent already wrote the prefix of the first item together with its own index
+		if n > 0 {
+			out.WriteString(prefix)
+		}
+		out.WriteString(strings.Repeat(" ", indexWidth-len(strconv.Itoa(index))))
+		out.WriteString(strconv.Itoa(index) + separator + " ")
+		out.WriteString(formatPretty(items[n], itemPrefix))
+
+		if reply.Type() == respparser.TypeMap {
+			n++
+			formatted := strings.TrimSuffix(out.String(), "\n")
+			out.Reset()
+			out.WriteString(formatted + " => ")
+			out.WriteString(formatPretty(items[n], itemPrefix))
+		}
+	}
+	return out.String()
+}
+
+// formatRaw writes values as they are, items of aggregate types are written on separate lines
+func formatRaw(reply respparser.RespData) string {
+	switch r := reply.(type) {
+	case respparser.BulkString:
+		return r.Value
+	case respparser.Boolean:
+		if r.Value {
+			return "1"
+		}
+		return "0"
+	case respparser.Attribute:
+		return formatRaw(r.Value)
+	}
+
+	items, ok := aggregateItems(reply)
+	if !ok {
+		if isNullArray(reply) {
+			return ""
+		}
+		return reply.String()
+	}
+	lines := make([]string, len(items))
+	for n, item := range items {
+		lines[n] = formatRaw(item)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatCSV quotes strings and separates items of aggregate types by commas
+func formatCSV(reply respparser.RespData) string {
+	switch r := reply.(type) {
+	case respparser.SimpleError:
+		return "ERROR," + repr(r.Value)
+	case respparser.SimpleString:
+		return repr(r.Value)
+	case respparser.BulkString:
+		if r.IsNull {
+			return "NULL"
+		}
+		return repr(r.Value)
+	case respparser.VerbatimString:
+		return repr(r.Value)
+	case respparser.Null:
+		return "NULL"
+	case respparser.Attribute:
+		return formatCSV(r.Value)
+	}
+
+	items, ok := aggregateItems(reply)
+	if !ok {
+		if isNullArray(reply) {
+			return "NULL"
+		}
+		return reply.String()
+	}
+	values := make([]string, len(items))
+	for n, item := range items {
+		values[n] = formatCSV(item)
+	}
+	return strings.Join(values, ",")
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+func TestFormatReply(t *testing.T) {
+	bulk := func(value string) respparser.BulkString { return respparser.BulkString{Value: value} }
+	entries := respparser.Array{Items: []respparser.RespData{
+		respparser.Array{Items: []respparser.RespData{bulk("1-1"), respparser.Array{Items: []respparser.RespData{bulk("temperature"), bulk("36")}}}},
+		respparser.Array{Items: []respparser.RespData{bulk("1-2"), respparser.Array{Items: []respparser.RespData{bulk("humidity"), bulk("95")}}}},
+	}}
+	tenItems := respparser.Array{}
+	for range 10 {
+		tenItems.Items = append(tenItems.Items, bulk("x"))
+	}
+	hello := respparser.Map{}
+	hello.Add("server", bulk("redis"))
+	hello.Add("modules", respparser.Array{})
+
+	var tests = []struct {
+		name  string
+		input respparser.RespData
+		mode  outputMode
+		want  string
+	}{
+		{
+			name:  "Simple types should be pretty printed",
+			input: respparser.Array{Items: []respparser.RespData{respparser.Integer{Value: 7}, respparser.BulkString{IsNull: true}, bulk("a\"b\n"), respparser.SimpleError{Value: "ERR x"}}},
+			mode:  outputPretty,
+			want:  "1) (integer) 7\n2) (nil)\n3) \"a\\\"b\\n\"\n4) (error) ERR x\n",
+		},
+		{
+			name:  "Nested arrays should be indented",
+			input: entries,
+			mode:  outputPretty,
+			want: "1) 1) \"1-1\"\n" +
+				"   2) 1) \"temperature\"\n" +
+				"      2) \"36\"\n" +
+				"2) 1) \"1-2\"\n" +
+				"   2) 1) \"humidity\"\n" +
+				"      2) \"95\"\n",
+		},
+		{
+			name:  "Indexes should be aligned",
+			input: tenItems,
+			mode:  outputPretty,
+			want:  " 1) \"x\"\n 2) \"x\"\n 3) \"x\"\n 4) \"x\"\n 5) \"x\"\n 6) \"x\"\n 7) \"x\"\n 8) \"x\"\n 9) \"x\"\n10) \"x\"\n",
+		},
+		{
+			name:  "Maps should be printed with keys and values",
+			input: hello,
+			mode:  outputPretty,
+			want:  "1# \"server\" => \"redis\"\n2# \"modules\" => (empty array)\n",
+		},
+		{
+			name:  "Empty array",
+			input: respparser.Array{},
+			mode:  outputPretty,
+			want:  "(empty array)\n",
+		},
+		{
+			name:  "Raw output should print values as they are",
+			input: entries,
+			mode:  outputRaw,
+			want:  "1-1\ntemperature\n36\n1-2\nhumidity\n95\n",
+		},
+		{
+			name:  "Raw null",
+			input: respparser.BulkString{IsNull: true},
+			mode:  outputRaw,
+			want:  "\n",
+		},
+		{
+			name:  "CSV output should quote strings",
+			input: respparser.Array{Items: []respparser.RespData{bulk("a,b"), respparser.Integer{Value: 1}, respparser.Null{}}},
+			mode:  outputCSV,
+			want:  "\"a,b\",1,NULL\n",
+		},
+		{
+			name:  "CSV error",
+			input: respparser.SimpleError{Value: "ERR x"},
+			mode:  outputCSV,
+			want:  "ERROR,\"ERR x\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if ans := formatReply(tt.input, tt.mode); ans != tt.want {
+				t.Errorf("ERROR got %q, want %q", ans, tt.want)
+			}
+		})
+	}
+}
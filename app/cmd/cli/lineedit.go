@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxHistoryLen is the number of lines kept in the history, the same as redis-cli keeps
+const maxHistoryLen = 100
+
+// errInterrupted is returned when the line is cancelled by Ctrl-C
+var errInterrupted = errors.New("interrupted")
+
+// lineEditor reads lines typed on a terminal in raw mode. It supports cursor movement, the usual
+// emacs style shortcuts and browsing the history with up and down arrows.
+type lineEditor struct {
+	in      *bufio.Reader
+	out     io.Writer
+	history []string
+}
+
+// editState is the line being edited
+type editState struct {
+	prompt string
+	line   []rune
+	pos    int
+	// remark: history index of the shown line, len(history) is the new line
+	historyIndex int
+	newLine      []rune
+}
+
+func newLineEditor(in io.Reader, out io.Writer) *lineEditor {
+	return &lineEditor{in: bufio.NewReader(in), out: out}
+}
+
+// addHistory appends the line unless it's the same as the last one, the oldest lines are dropped
+func (e *lineEditor) addHistory(line string) {
+	if line == "" || (len(e.history) > 0 && e.history[len(e.history)-1] == line) {
+		return
+	}
+	e.history = append(e.history, line)
+	if len(e.history) > maxHistoryLen {
+		e.history = e.history[len(e.history)-maxHistoryLen:]
+	}
+}
+
+// loadHistory reads the history file, a missing file means empty history
+func (e *lineEditor) loadHistory(path string) error {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		e.addHistory(line)
+	}
+	return nil
+}
+
+func (e *lineEditor) saveHistory(path string) error {
+	content := strings.Join(e.history, "\n") + "\n"
+	return os.WriteFile(path, []byte(content), 0600)
+}
+
+// readLine shows the prompt and returns the line once enter is pressed. io.EOF is returned
+// for Ctrl-D on an empty line and errInterrupted for Ctrl-C.
+func (e *lineEditor) readLine(prompt string) (string, error) {
+	s := &editState{prompt: prompt, historyIndex: len(e.history)}
+	e.refresh(s)
+
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+
+		switch r {
+		case '\r', '\n':
+			fmt.Fprint(e.out, "\r\n")
+			return string(s.line), nil
+		case 3: // Ctrl-C
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", errInterrupted
+		case 4: // Ctrl-D
+			if len(s.line) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			s.deleteAt(s.pos)
+		case 127, 8: // Backspace, Ctrl-H
+			if s.pos > 0 {
+				s.pos--
+				s.deleteAt(s.pos)
+			}
+		case 1: // Ctrl-A
+			s.pos = 0
+		case 5: // Ctrl-E
+			s.pos = len(s.line)
+		case 2: // Ctrl-B
+			s.pos = max(s.pos-1, 0)
+		case 6: // Ctrl-F
+			s.pos = min(s.pos+1, len(s.line))
+		case 16: // Ctrl-P
+			e.browseHistory(s, -1)
+		case 14: // Ctrl-N
+			e.browseHistory(s, 1)
+		case 11: // Ctrl-K
+			s.line = s.line[:s.pos]
+		case 21: // Ctrl-U
+			s.line = s.line[s.pos:]
+			s.pos = 0
+		case 23: // Ctrl-W
+			start := s.pos
+			for start > 0 && s.line[start-1] == ' ' {
+				start--
+			}
+			for start > 0 && s.line[start-1] != ' ' {
+				start--
+			}
+			s.line = append(s.line[:start], s.line[s.pos:]...)
+			s.pos = start
+		case 12: // Ctrl-L
+			fmt.Fprint(e.out, "\x1b[H\x1b[2J")
+		case 27: // escape sequences of arrows and other special keys
+			e.escapeSequence(s)
+		case utf8.RuneError, '\t':
+			// remark: invalid input and completion aren't supported
+		default:
+			if r >= ' ' {
+				s.line = append(s.line[:s.pos], append([]rune{r}, s.line[s.pos:]...)...)
+				s.pos++
+			}
+		}
+		e.refresh(s)
+	}
+}
+
+func (e *lineEditor) escapeSequence(s *editState) {
+	kind, _, err := e.in.ReadRune()
+	if err != nil || (kind != '[' && kind != 'O') {
+		return
+	}
+	key, _, err := e.in.ReadRune()
+	if err != nil {
+		return
+	}
+
+	// remark: keys like delete are sent as ESC [ <number> ~
+	if key >= '0' && key <= '9' {
+		if tilde, _, err := e.in.ReadRune(); err != nil || tilde != '~' {
+			return
+		}
+	}
+
+	switch key {
+	case 'A':
+		e.browseHistory(s, -1)
+	case 'B':
+		e.browseHistory(s, 1)
+	case 'C':
+		s.pos = min(s.pos+1, len(s.line))
+	case 'D':
+		s.pos = max(s.pos-1, 0)
+	case 'H', '1', '7':
+		s.pos = 0
+	case 'F', '4', '8':
+		s.pos = len(s.line)
+	case '3':
+		s.deleteAt(s.pos)
+	}
+}
+
+// browseHistory replaces the line by an older (step -1) or a newer (step 1) history line
+func (e *lineEditor) browseHistory(s *editState, step int) {
+	index := s.historyIndex + step
+	if index < 0 || index > len(e.history) {
+		return
+	}
+	if s.historyIndex == len(e.history) {
+		s.newLine = s.line
+	}
+
+	s.historyIndex = index
+	if index == len(e.history) {
+		s.line = s.newLine
+	} else {
+		s.line = []rune(e.history[index])
+	}
+	s.pos = len(s.line)
+}
+
+// refresh redraws the prompt with the line and moves the cursor to its position
+func (e *lineEditor) refresh(s *editState) {
+	column := utf8.RuneCountInString(s.prompt) + s.pos
+	fmt.Fprintf(e.out, "\r%s%s\x1b[0K\r", s.prompt, string(s.line))
+	if column > 0 {
+		fmt.Fprintf(e.out, "\x1b[%dC", column)
+	}
+}
+
+func (s *editState) deleteAt(pos int) {
+	if pos < len(s.line) {
+		s.line = append(s.line[:pos], s.line[pos+1:]...)
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/client"
+)
+
+func TestLineEditor(t *testing.T) {
+	var tests = []struct {
+		name    string
+		history []string
+		input   string
+		want    []string
+		wantErr error
+	}{
+		{
+			name:  "Typed line should be returned on enter",
+			input: "GET key\r",
+			want:  []string{"GET key"},
+		},
+		{
+			name:  "Backspace and cursor movement should edit the line",
+			input: "GT key\x1b[D\x1b[D\x1b[D\x1b[D\x1b[DE\x05x\x7f\r",
+			want:  []string{"GET key"},
+		},
+		{
+			name:    "Up arrow should recall the history",
+			history: []string{"SET a 1", "GET a"},
+			input:   "\x1b[A\r\x1b[A\x1b[A\x1b[A\r",
+			want:    []string{"GET a", "SET a 1"},
+		},
+		{
+			name:    "Down arrow should restore the new line",
+			history: []string{"GET a"},
+			input:   "PI\x1b[A\x1b[BNG\r",
+			want:    []string{"PING"},
+		},
+		{
+			name:  "Ctrl-W should delete the previous word",
+			input: "GET some key\x17other\r",
+			want:  []string{"GET some other"},
+		},
+		{
+			name:  "Ctrl-U should delete the line before the cursor",
+			input: "junk\x15PING\r",
+			want:  []string{"PING"},
+		},
+		{
+			name:    "Ctrl-D on an empty line should end the input",
+			input:   "\x04",
+			wantErr: io.EOF,
+		},
+		{
+			name:    "Ctrl-C should interrupt",
+			input:   "GET\x03",
+			wantErr: errInterrupted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newLineEditor(strings.NewReader(tt.input), io.Discard)
+			for _, line := range tt.history {
+				e.addHistory(line)
+			}
+
+			for _, want := range tt.want {
+				line, err := e.readLine("> ")
+				if err != nil {
+					t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+				}
+				if line != want {
+					t.Errorf("ERROR got %q, want %q", line, want)
+				}
+				e.addHistory(line)
+			}
+
+			if tt.wantErr != nil {
+				if _, err := e.readLine("> "); !errors.Is(err, tt.wantErr) {
+					t.Errorf("ERROR got %v, want %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestSensitiveCommand(t *testing.T) {
+	var tests = []struct {
+		input string
+		want  bool
+	}{
+		{input: "GET key", want: false},
+		{input: "auth secret", want: true},
+		{input: "3 AUTH user secret", want: true},
+		{input: "HELLO 3", want: false},
+		{input: "HELLO 3 AUTH default secret", want: true},
+		{input: "ACL SETUSER reader >pw", want: true},
+		{input: "CONFIG SET requirepass secret", want: true},
+		{input: "CONFIG SET port 6380", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if ans := sensitiveCommand(tt.input); ans != tt.want {
+				t.Errorf("ERROR got %t, want %t", ans, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubscribeRejected(t *testing.T) {
+	// remark: the address isn't reachable, the command mustn't be sent
+	out := strings.Builder{}
+	c := &cli{client: client.New(client.Options{Addr: "127.0.0.1:1"}), mode: outputPretty, out: &out}
+	defer c.client.Close()
+
+	for _, line := range []string{"subscribe news", "PSUBSCRIBE news.*"} {
+		out.Reset()
+		if !c.runLine(line, 0) {
+			t.Fatalf("ERROR the prompt should continue after %q", line)
+		}
+		if !strings.HasPrefix(out.String(), "(error) ERR ") || !strings.Contains(out.String(), "SUBSCRIBE isn't supported") {
+			t.Errorf("ERROR got %q, want error reply", out.String())
+		}
+	}
+}
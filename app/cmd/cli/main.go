@@ -0,0 +1,144 @@
+// Command cli is a command line client of the server in the style of redis-cli. Without a command
+// it starts an interactive prompt, otherwise the command is run and its reply is written to stdout.
+//
+//	cli [-h host] [-p port] [-s socket] [-a password] [--user name] [-3]
+//	    [--raw | --no-raw | --csv] [-r count] [-i interval] [--pipe] [command [arg ...]]
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/client"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+type options struct {
+	host     string
+	port     int
+	socket   string
+	user     string
+	password string
+	resp3    bool
+
+	raw   bool
+	noRaw bool
+	csv   bool
+
+	repeat   int
+	interval float64
+	pipe     bool
+}
+
+func main() {
+	opts := options{}
+	flags := flag.NewFlagSet("cli", flag.ExitOnError)
+	flags.StringVar(&opts.host, "h", "127.0.0.1", "Server hostname")
+	flags.IntVar(&opts.port, "p", 6379, "Server port")
+	flags.StringVar(&opts.socket, "s", "", "Server socket (overrides hostname and port)")
+	flags.StringVar(&opts.user, "user", "", "Used to send ACL style 'AUTH username pass', needs -a")
+	flags.StringVar(&opts.password, "a", "", "Password to use when connecting to the server")
+	flags.BoolVar(&opts.resp3, "3", false, "Start session in RESP3 protocol mode")
+	flags.BoolVar(&opts.raw, "raw", false, "Use raw formatting for replies (default when stdout is not a tty)")
+	flags.BoolVar(&opts.noRaw, "no-raw", false, "Force formatted output even when stdout is not a tty")
+	flags.BoolVar(&opts.csv, "csv", false, "Output in CSV format")
+	flags.IntVar(&opts.repeat, "r", 1, "Execute specified command N times, -1 runs it forever")
+	flags.Float64Var(&opts.interval, "i", 0, "When -r is used, waits interval seconds per command")
+	flags.BoolVar(&opts.pipe, "pipe", false, "Transfer raw RESP protocol from stdin to server")
+	flags.Parse(os.Args[1:])
+
+	network, addr := "tcp", net.JoinHostPort(opts.host, strconv.Itoa(opts.port))
+	if opts.socket != "" {
+		network, addr = "unix", opts.socket
+	}
+
+	if opts.pipe {
+		os.Exit(runPipe(network, addr, opts))
+	}
+
+	protocol := respparser.Resp2
+	if opts.resp3 {
+		protocol = respparser.Resp3
+	}
+	c := &cli{
+		client: client.New(client.Options{
+			Network:  network,
+			Addr:     addr,
+			Username: opts.user,
+			Password: opts.password,
+			Protocol: protocol,
+			// remark: a single connection, so commands like CLIENT SETNAME affect the following commands
+			PoolSize: 1,
+		}),
+		addr: addr,
+		mode: selectOutputMode(opts),
+		out:  os.Stdout,
+	}
+	defer c.client.Close()
+	interval := time.Duration(opts.interval * float64(time.Second))
+
+	if flags.NArg() > 0 {
+		if !c.repeat(flags.Args(), opts.repeat, interval) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := c.client.Ping(context.Background()); err != nil {
+		fmt.Printf("Could not connect to Redis at %s: %s\n", addr, err.Error())
+	}
+	c.interactive(interval)
+}
+
+// selectOutputMode returns the mode selected by flags, raw is the default when stdout isn't a terminal
+func selectOutputMode(opts options) outputMode {
+	switch {
+	case opts.csv:
+		return outputCSV
+	case opts.raw:
+		return outputRaw
+	case opts.noRaw:
+		return outputPretty
+	}
+	if info, err := os.Stdout.Stat(); err == nil && info.Mode()&os.ModeCharDevice == 0 {
+		return outputRaw
+	}
+	return outputPretty
+}
+
+// runPipe runs the mass insertion and returns the exit code
+func runPipe(network string, addr string, opts options) int {
+	conn, err := net.Dial(network, addr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not connect to Redis at %s: %s\n", addr, err.Error())
+		return 1
+	}
+	defer conn.Close()
+
+	if opts.password != "" {
+		user := opts.user
+		if user == "" {
+			user = "default"
+		}
+		if err := authenticate(conn, user, opts.password); err != nil {
+			fmt.Fprintf(os.Stderr, "AUTH failed: %s\n", err.Error())
+			return 1
+		}
+	}
+
+	result, err := pipe(conn, bufio.NewReader(os.Stdin), os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading replies from server: %s\n", err.Error())
+		return 1
+	}
+	if result.Errors > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+// pipeResult counts replies of the mass insertion, errors are included in replies
+type pipeResult struct {
+	Replies int
+	Errors  int
+}
+
+// pipe sends RESP commands read from in as fast as possible while replies are read at the same time.
+// An ECHO of a random marker is sent after the input, its reply tells all replies were received.
+// Error replies are written to out.
+func pipe(conn net.Conn, in io.Reader, out io.Writer) (pipeResult, error) {
+	markerBytes := make([]byte, 20)
+	rand.Read(markerBytes)
+	marker := hex.EncodeToString(markerBytes)
+
+	written := make(chan error, 1)
+	go func() {
+		w := bufio.NewWriter(conn)
+		if _, err := io.Copy(w, in); err != nil {
+			written <- err
+			return
+		}
+		fmt.Fprintln(out, "All data transferred. Waiting for the last reply...")
+
+		e := respparser.NewEncoder(w)
+		e.Encode(respparser.Array{Items: []respparser.RespData{
+			respparser.BulkString{Value: "ECHO"},
+			respparser.BulkString{Value: marker},
+		}})
+		written <- e.Flush()
+	}()
+
+	result := pipeResult{}
+	decoder := respparser.NewReplyDecoder(bufio.NewReader(conn))
+	for {
+		reply, err := decoder.Decode()
+		if err != nil {
+			return result, err
+		}
+		if bulk, ok := reply.(respparser.BulkString); ok && bulk.Value == marker {
+			break
+		}
+
+		result.Replies++
+		if replyErr, ok := reply.(respparser.SimpleError); ok {
+			result.Errors++
+			fmt.Fprintln(out, replyErr.Value)
+		}
+	}
+
+	if err := <-written; err != nil {
+		return result, err
+	}
+	fmt.Fprintln(out, "Last reply received from server.")
+	fmt.Fprintf(out, "errors: %d, replies: %d\n", result.Errors, result.Replies)
+	return result, nil
+}
+
+// authenticate sends AUTH before the piped commands, so its reply isn't counted
+func authenticate(conn net.Conn, user string, password string) error {
+	e := respparser.NewEncoder(conn)
+	e.Encode(respparser.Array{Items: []respparser.RespData{
+		respparser.BulkString{Value: "AUTH"},
+		respparser.BulkString{Value: user},
+		respparser.BulkString{Value: password},
+	}})
+	if err := e.Flush(); err != nil {
+		return err
+	}
+
+	// remark: nothing else was sent yet, so the decoder can't buffer replies of piped commands
+	reply, err := respparser.NewReplyDecoder(conn).Decode()
+	if err != nil {
+		return err
+	}
+	if replyErr, ok := reply.(respparser.SimpleError); ok {
+		return errors.New(replyErr.Value)
+	}
+	return nil
+}
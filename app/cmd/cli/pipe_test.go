@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/client"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/command"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/config"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/server"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+func TestPipe(t *testing.T) {
+	for _, nv := range [][2]string{{"bind", "127.0.0.1"}, {"port", "0"}} {
+		if err := config.Load(nv[0], nv[1]); err != nil {
+			t.Fatalf("ERROR can't configure server: %s", err.Error())
+		}
+	}
+	s := server.New()
+	if err := s.Listen(); err != nil {
+		t.Fatalf("ERROR can't start server: %s", err.Error())
+	}
+	exitStatus := make(chan int, 1)
+	go func() { exitStatus <- s.Serve() }()
+	defer func() {
+		s.Shutdown(command.ShutdownOptions{})
+		<-exitStatus
+	}()
+	addr := s.Addrs()[0].String()
+
+	input := bytes.Buffer{}
+	e := respparser.NewEncoder(&input)
+	for n := range 1000 {
+		e.Encode(respparser.Array{Items: []respparser.RespData{
+			respparser.BulkString{Value: "RPUSH"},
+			respparser.BulkString{Value: "pipe-list"},
+			respparser.BulkString{Value: strconv.Itoa(n)},
+		}})
+	}
+	e.Encode(respparser.Array{Items: []respparser.RespData{respparser.BulkString{Value: "NOSUCHCOMMAND"}}})
+	e.Flush()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("ERROR can't connect to server: %s", err.Error())
+	}
+	defer conn.Close()
+
+	result, err := pipe(conn, &input, io.Discard)
+	if err != nil {
+		t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+	}
+	if result.Replies != 1001 || result.Errors != 1 {
+		t.Errorf("ERROR got %+v, want 1001 replies and 1 error", result)
+	}
+
+	c := client.New(client.Options{Addr: addr})
+	defer c.Close()
+	if values, _ := c.LRange(context.Background(), "pipe-list", -1, -1); len(values) != 1 || values[0] != "999" {
+		t.Errorf("ERROR got %q, want [999]", values)
+	}
+}
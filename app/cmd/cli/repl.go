@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/client"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+// cli runs commands and writes their replies in the output mode
+type cli struct {
+	client *client.Client
+	addr   string // shown in the prompt and in connection errors
+	mode   outputMode
+	out    io.Writer
+}
+
+// subscribeCommands switch the connection to the subscribed mode, published messages are pushed then
+// and aren't replies to commands, so the client can't read them
+var subscribeCommands = map[string]bool{"SUBSCRIBE": true, "PSUBSCRIBE": true}
+
+// run sends the command and writes its reply, false is returned when the server couldn't be reached
+func (c *cli) run(args []string) bool {
+	if name := strings.ToUpper(args[0]); subscribeCommands[name] {
+		reply := respparser.SimpleError{Value: fmt.Sprintf("ERR %s isn't supported by cli, published messages can't be shown", name)}
+		fmt.Fprint(c.out, formatReply(reply, c.mode))
+		return true
+	}
+
+	reply, err := c.client.Do(context.Background(), args...)
+	var replyErr *client.Error
+	if errors.As(err, &replyErr) {
+		reply = respparser.SimpleError{Value: replyErr.Error()}
+	} else if err != nil {
+		fmt.Fprintf(c.out, "Could not connect to Redis at %s: %s\n", c.addr, err.Error())
+		return false
+	}
+	fmt.Fprint(c.out, formatReply(reply, c.mode))
+	return true
+}
+
+// repeat runs the command count times (forever when negative) and waits for interval between runs
+func (c *cli) repeat(args []string, count int, interval time.Duration) bool {
+	for n := 0; count < 0 || n < count; n++ {
+		if n > 0 && interval > 0 {
+			time.Sleep(interval)
+		}
+		if !c.run(args) {
+			return false
+		}
+	}
+	return true
+}
+
+// prompt is shown before each line of the interactive mode
+func (c *cli) prompt() string {
+	if strings.Contains(c.addr, "/") {
+		return "redis " + c.addr + "> "
+	}
+	return c.addr + "> "
+}
+
+// runLine runs a line typed in the interactive mode. A number before the command repeats it,
+// e.g. "5 INCR counter". False is returned when the user asked to quit.
+func (c *cli) runLine(line string, interval time.Duration) bool {
+	args, err := utils.SplitArgs(line)
+	if err != nil {
+		fmt.Fprintln(c.out, "Invalid argument(s)")
+		return true
+	} else if len(args) == 0 {
+		return true
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "quit", "exit":
+		return false
+	case "clear":
+		fmt.Fprint(c.out, "\x1b[H\x1b[2J")
+		return true
+	}
+
+	count := 1
+	if n, err := strconv.Atoi(args[0]); err == nil && len(args) > 1 {
+		count = n
+		args = args[1:]
+	}
+	c.repeat(args, count, interval)
+	return true
+}
+
+// interactive reads commands from stdin until EOF. On a terminal lines can be edited and the history
+// is kept in the history file, otherwise lines are read as they are without a prompt.
+func (c *cli) interactive(interval time.Duration) {
+	stdin := int(os.Stdin.Fd())
+	if !isTerminal(stdin) {
+		scanner := bufio.NewScanner(os.Stdin)
+		scanner.Buffer(make([]byte, 64*1024), respparser.MaxLineLen)
+		for scanner.Scan() {
+			if !c.runLine(scanner.Text(), interval) {
+				break
+			}
+		}
+		return
+	}
+
+	editor := newLineEditor(os.Stdin, os.Stdout)
+	historyFile := historyPath()
+	if historyFile != "" {
+		if err := editor.loadHistory(historyFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Can't load history: %s\n", err.Error())
+		}
+	}
+
+	for {
+		state, err := makeRaw(stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Can't switch terminal to raw mode: %s\n", err.Error())
+			return
+		}
+		line, err := editor.readLine(c.prompt())
+		restoreTerminal(stdin, state)
+		if errors.Is(err, errInterrupted) || errors.Is(err, io.EOF) {
+			return
+		} else if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			return
+		}
+
+		if !sensitiveCommand(line) {
+			editor.addHistory(strings.TrimSpace(line))
+			if historyFile != "" {
+				editor.saveHistory(historyFile)
+			}
+		}
+		if !c.runLine(line, interval) {
+			return
+		}
+	}
+}
+
+// historyPath returns the history file, REDISCLI_HISTFILE overrides the default ~/.rediscli_history.
+// An empty path means the history isn't saved.
+func historyPath() string {
+	if path, found := os.LookupEnv("REDISCLI_HISTFILE"); found {
+		if path == "/dev/null" {
+			return ""
+		}
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".rediscli_history")
+}
+
+// sensitiveCommand reports whether the line may contain a password, such lines aren't saved to the history
+func sensitiveCommand(line string) bool {
+	args, err := utils.SplitArgs(line)
+	if err != nil || len(args) == 0 {
+		return false
+	}
+	// remark: a leading repeat count is skipped
+	if _, err := strconv.Atoi(args[0]); err == nil && len(args) > 1 {
+		args = args[1:]
+	}
+
+	name := strings.ToUpper(args[0])
+	switch {
+	case name == "AUTH":
+		return true
+	case name == "HELLO":
+		return slices.ContainsFunc(args, func(arg string) bool { return strings.EqualFold(arg, "AUTH") })
+	case name == "ACL" && len(args) > 1 && strings.EqualFold(args[1], "SETUSER"):
+		return true
+	case name == "CONFIG" && len(args) > 2 && strings.EqualFold(args[1], "SET"):
+		for _, arg := range args[2:] {
+			if strings.EqualFold(arg, "requirepass") || strings.EqualFold(arg, "masterauth") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+//go:build linux
+
+package main
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// terminalState is the terminal mode to restore after raw mode
+type terminalState struct {
+	termios syscall.Termios
+}
+
+func isTerminal(fd int) bool {
+	var termios syscall.Termios
+	return ioctlTermios(fd, syscall.TCGETS, &termios) == nil
+}
+
+// makeRaw disables line buffering, echo and signals of the terminal, so single key presses
+// can be read. Output processing is kept, new lines are still written as CRLF.
+func makeRaw(fd int) (*terminalState, error) {
+	var termios syscall.Termios
+	if err := ioctlTermios(fd, syscall.TCGETS, &termios); err != nil {
+		return nil, err
+	}
+	state := &terminalState{termios: termios}
+
+	termios.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP | syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	termios.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	termios.Cflag &^= syscall.CSIZE | syscall.PARENB
+	termios.Cflag |= syscall.CS8
+	termios.Cc[syscall.VMIN] = 1
+	termios.Cc[syscall.VTIME] = 0
+	if err := ioctlTermios(fd, syscall.TCSETS, &termios); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func restoreTerminal(fd int, state *terminalState) error {
+	return ioctlTermios(fd, syscall.TCSETS, &state.termios)
+}
+
+func ioctlTermios(fd int, request uintptr, termios *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), request, uintptr(unsafe.Pointer(termios)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+//go:build !linux
+
+package main
+
+import "errors"
+
+// terminalState is the terminal mode to restore after raw mode
+type terminalState struct{}
+
+var errRawModeUnsupported = errors.New("raw terminal mode isn't supported on this platform")
+
+// isTerminal reports false, so lines are read without editing on other platforms
+func isTerminal(fd int) bool {
+	return false
+}
+
+func makeRaw(fd int) (*terminalState, error) {
+	return nil, errRawModeUnsupported
+}
+
+func restoreTerminal(fd int, state *terminalState) error {
+	return errRawModeUnsupported
+}
@@ -0,0 +1,153 @@
+package acl
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+)
+
+const DefaultUserName = "default"
+
+var ErrDefaultUserRemoval = errors.New("The 'default' user cannot be removed")
+
+type userRegistry struct {
+	mu    sync.RWMutex
+	users map[string]*User
+}
+
+var registry = userRegistry{
+	users: map[string]*User{DefaultUserName: newDefaultUser()},
+}
+
+// newDefaultUser has access to everything without a password
+func newDefaultUser() *User {
+	u := newUser(DefaultUserName)
+	for _, rule := range []string{"on", "nopass", "allkeys", "allcommands"} {
+		u.applyRule(rule)
+	}
+	return u
+}
+
+// GetUser returns a snapshot of the user, users are never modified in place
+func GetUser(name string) (*User, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	u, found := registry.users[name]
+	return u, found
+}
+
+// Users returns all users ordered by name
+func Users() []*User {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	users := make([]*User, 0, len(registry.users))
+	for _, u := range registry.users {
+		users = append(users, u)
+	}
+	slices.SortFunc(users, func(a, b *User) int { return strings.Compare(a.Name, b.Name) })
+	return users
+}
+
+// SetUser creates or modifies the user, the rules are applied all or nothing
+func SetUser(name string, rules []string) error {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	u, err := applyRules(registry.users[name], name, rules)
+	if err != nil {
+		return err
+	}
+	registry.users[name] = u
+	utils.Log(fmt.Sprintf("(acl) User %s set: %s", name, u.Describe()))
+	return nil
+}
+
+func applyRules(existing *User, name string, rules []string) (*User, error) {
+	var u *User
+	if existing != nil {
+		u = existing.clone()
+	} else {
+		u = newUser(name)
+	}
+
+	for _, rule := range rules {
+		if err := u.applyRule(rule); err != nil {
+			return nil, &RuleError{Rule: rule, Err: err}
+		}
+	}
+	return u, nil
+}
+
+// DelUser removes users and returns the number of removed ones
+func DelUser(names []string) (int, error) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if slices.Contains(names, DefaultUserName) {
+		return 0, ErrDefaultUserRemoval
+	}
+
+	deleted := 0
+	for _, name := range names {
+		if _, found := registry.users[name]; found {
+			delete(registry.users, name)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// Authenticate reports whether the user is enabled and the password matches
+func Authenticate(name string, password string) bool {
+	u, found := GetUser(name)
+	return found && u.Enabled && u.CheckPassword(password)
+}
+
+// DefaultUserNoPass reports whether new connections are authenticated automatically
+func DefaultUserNoPass() bool {
+	u, found := GetUser(DefaultUserName)
+	return found && u.Enabled && u.NoPass
+}
+
+// SetRequirePass sets the password of the default user, empty password means nopass
+func SetRequirePass(password string) error {
+	if password == "" {
+		return SetUser(DefaultUserName, []string{"nopass"})
+	}
+	return SetUser(DefaultUserName, []string{"resetpass", ">" + password})
+}
+
+// DeniedError is returned when the user lacks permissions to run the command
+type DeniedError struct {
+	Reason string // command or key
+	Object string // command name or key
+	User   string
+}
+
+func (e *DeniedError) Error() string {
+	if e.Reason == LogReasonKey {
+		return "No permissions to access a key"
+	}
+	return fmt.Sprintf("User %s has no permissions to run the '%s' command", e.User, e.Object)
+}
+
+// Check verifies the user may run the command with the keys
+func Check(userName string, commandName string, categories []string, keys []string) error {
+	u, found := GetUser(userName)
+	if !found || !u.CanRun(commandName, categories) {
+		return &DeniedError{Reason: LogReasonCommand, Object: commandName, User: userName}
+	}
+
+	for _, key := range keys {
+		if !u.CanAccessKey(key) {
+			return &DeniedError{Reason: LogReasonKey, Object: key, User: userName}
+		}
+	}
+	return nil
+}
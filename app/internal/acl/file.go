@@ -0,0 +1,94 @@
+package acl
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+)
+
+// LoadFile replaces all users by users defined in the ACL file. Every line has
+// the ACL LIST format: user <name> <rules...>. Nothing is changed when the file is invalid.
+func LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("Error loading ACL file %s: %s", path, err.Error())
+	}
+	defer f.Close()
+
+	users := map[string]*User{}
+	scanner := bufio.NewScanner(f)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		args, err := utils.SplitArgs(line)
+		if err != nil {
+			return fmt.Errorf("%s:%d: unbalanced quotes in acl line", path, lineNum)
+		}
+		if len(args) < 2 || args[0] != "user" {
+			return fmt.Errorf("%s:%d: should start with user keyword", path, lineNum)
+		}
+
+		name := args[1]
+		if _, duplicate := users[name]; duplicate {
+			return fmt.Errorf("%s:%d: duplicate user '%s' found", path, lineNum, name)
+		}
+
+		u, err := applyRules(nil, name, args[2:])
+		if err != nil {
+			return fmt.Errorf("%s:%d: %s", path, lineNum, err.Error())
+		}
+		users[name] = u
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	// remark: the default user always exists
+	if _, found := users[DefaultUserName]; !found {
+		users[DefaultUserName] = newDefaultUser()
+	}
+
+	registry.mu.Lock()
+	registry.users = users
+	registry.mu.Unlock()
+
+	utils.LogNotice(fmt.Sprintf("(acl) Loaded %d users from %s", len(users), path))
+	return nil
+}
+
+// SaveFile writes all users to the ACL file
+func SaveFile(path string) error {
+	var content strings.Builder
+	for _, u := range Users() {
+		content.WriteString(u.Describe())
+		content.WriteString("\n")
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".redis-acl-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(content.String()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return errors.Join(fmt.Errorf("Error saving ACL file %s", path), err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package acl
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	LogReasonCommand = "command"
+	LogReasonKey     = "key"
+	LogReasonAuth    = "auth"
+)
+
+// logGroupingWindow merges repeated denials into one entry
+const logGroupingWindow = 60 * time.Second
+
+type LogEntry struct {
+	ID         int64
+	Count      int
+	Reason     string
+	Context    string
+	Object     string
+	Username   string
+	ClientInfo string
+	Created    time.Time
+	Updated    time.Time
+}
+
+var aclLog = struct {
+	mu      sync.Mutex
+	entries []*LogEntry // newest first
+	nextID  int64
+	maxLen  int
+}{
+	maxLen: 128,
+}
+
+// SetLogMaxLen limits number of kept ACL LOG entries
+func SetLogMaxLen(maxLen int) {
+	aclLog.mu.Lock()
+	defer aclLog.mu.Unlock()
+
+	aclLog.maxLen = maxLen
+	if len(aclLog.entries) > maxLen {
+		aclLog.entries = aclLog.entries[:maxLen]
+	}
+}
+
+// AddLogEntry records a denied command, key access or failed authentication
+func AddLogEntry(reason string, object string, username string, clientInfo string) {
+	aclLog.mu.Lock()
+	defer aclLog.mu.Unlock()
+
+	now := time.Now()
+	for _, e := range aclLog.entries {
+		if e.Reason == reason && e.Object == object && e.Username == username && now.Sub(e.Updated) < logGroupingWindow {
+			e.Count++
+			e.Updated = now
+			e.ClientInfo = clientInfo
+			return
+		}
+	}
+
+	entry := &LogEntry{
+		ID:         aclLog.nextID,
+		Count:      1,
+		Reason:     reason,
+		Context:    "toplevel",
+		Object:     object,
+		Username:   username,
+		ClientInfo: clientInfo,
+		Created:    now,
+		Updated:    now,
+	}
+	aclLog.nextID++
+
+	aclLog.entries = append([]*LogEntry{entry}, aclLog.entries...)
+	if len(aclLog.entries) > aclLog.maxLen {
+		aclLog.entries = aclLog.entries[:aclLog.maxLen]
+	}
+}
+
+// LogEntries returns copies of up to count newest entries
+func LogEntries(count int) []LogEntry {
+	aclLog.mu.Lock()
+	defer aclLog.mu.Unlock()
+
+	count = min(count, len(aclLog.entries))
+	entries := make([]LogEntry, count)
+	for n := range count {
+		entries[n] = *aclLog.entries[n]
+	}
+	return entries
+}
+
+func ResetLog() {
+	aclLog.mu.Lock()
+	defer aclLog.mu.Unlock()
+	aclLog.entries = nil
+}
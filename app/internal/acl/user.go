@@ -0,0 +1,275 @@
+package acl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+)
+
+// Categories lists ACL categories commands can belong to
+var Categories = []string{
+	"keyspace", "read", "write", "set", "sortedset", "list", "hash", "string", "bitmap",
+	"hyperloglog", "geo", "stream", "pubsub", "admin", "fast", "slow", "blocking",
+	"dangerous", "connection", "transaction", "scripting",
+}
+
+// commandRule allows or denies a command, a command category or all commands
+type commandRule struct {
+	allow    bool
+	command  string // lower cased command name, optionally with a subcommand (client|kill)
+	category string // category name without '@', "all" matches every command
+}
+
+func (r commandRule) String() string {
+	sign := "-"
+	if r.allow {
+		sign = "+"
+	}
+	if r.category != "" {
+		return sign + "@" + r.category
+	}
+	return sign + r.command
+}
+
+func (r commandRule) matches(commandName string, categories []string) bool {
+	if r.category == "all" {
+		return true
+	}
+	if r.category != "" {
+		return slices.Contains(categories, r.category)
+	}
+	// remark: a rule for a container command applies to all its subcommands
+	return r.command == commandName || strings.HasPrefix(commandName, r.command+"|")
+}
+
+type User struct {
+	Name        string
+	Enabled     bool
+	NoPass      bool
+	Passwords   map[string]bool // SHA256 hex digests
+	KeyPatterns []string
+	rules       []commandRule
+}
+
+func newUser(name string) *User {
+	return &User{
+		Name:      name,
+		Passwords: map[string]bool{},
+	}
+}
+
+func (u *User) clone() *User {
+	clone := *u
+	clone.Passwords = maps.Clone(u.Passwords)
+	clone.KeyPatterns = slices.Clone(u.KeyPatterns)
+	clone.rules = slices.Clone(u.rules)
+	return &clone
+}
+
+func HashPassword(password string) string {
+	sum := sha256.Sum256([]byte(password))
+	return hex.EncodeToString(sum[:])
+}
+
+// knownCommand is used to validate +command and -command rules
+var knownCommand = func(name string) bool { return true }
+
+// SetCommandValidator registers the function reporting whether a command exists
+func SetCommandValidator(validator func(name string) bool) {
+	knownCommand = validator
+}
+
+var errSyntax = errors.New("Syntax error")
+
+// applyRule modifies the user according to a single ACL SETUSER rule
+func (u *User) applyRule(rule string) error {
+	lowerRule := strings.ToLower(rule)
+
+	switch lowerRule {
+	case "on":
+		u.Enabled = true
+	case "off":
+		u.Enabled = false
+	case "nopass":
+		u.NoPass = true
+		u.Passwords = map[string]bool{}
+	case "resetpass":
+		u.NoPass = false
+		u.Passwords = map[string]bool{}
+	case "allkeys":
+		u.KeyPatterns = []string{"*"}
+	case "resetkeys":
+		u.KeyPatterns = nil
+	case "allcommands":
+		u.rules = []commandRule{{allow: true, category: "all"}}
+	case "nocommands":
+		u.rules = nil
+	case "reset":
+		for _, r := range []string{"resetpass", "resetkeys", "off", "nocommands"} {
+			u.applyRule(r)
+		}
+	default:
+		return u.applyPrefixedRule(rule)
+	}
+	return nil
+}
+
+func (u *User) applyPrefixedRule(rule string) error {
+	if len(rule) < 2 {
+		return errSyntax
+	}
+	value := rule[1:]
+
+	switch rule[0] {
+	case '>':
+		u.Passwords[HashPassword(value)] = true
+		u.NoPass = false
+	case '<':
+		hash := HashPassword(value)
+		if !u.Passwords[hash] {
+			return errors.New("no such password")
+		}
+		delete(u.Passwords, hash)
+	case '#':
+		hash := strings.ToLower(value)
+		if _, err := hex.DecodeString(hash); err != nil || len(hash) != sha256.Size*2 {
+			return errors.New("The password hash must be exactly 64 characters and contain only lowercase hexadecimal characters")
+		}
+		u.Passwords[hash] = true
+		u.NoPass = false
+	case '!':
+		hash := strings.ToLower(value)
+		if !u.Passwords[hash] {
+			return errors.New("no such password")
+		}
+		delete(u.Passwords, hash)
+	case '~':
+		if !slices.Contains(u.KeyPatterns, "*") {
+			u.KeyPatterns = append(u.KeyPatterns, value)
+		}
+	case '+', '-':
+		return u.applyCommandRule(rule[0] == '+', strings.ToLower(value))
+	default:
+		return errSyntax
+	}
+	return nil
+}
+
+func (u *User) applyCommandRule(allow bool, value string) error {
+	rule := commandRule{allow: allow}
+
+	if strings.HasPrefix(value, "@") {
+		rule.category = value[1:]
+		if rule.category == "all" {
+			// remark: +@all and -@all override every previous rule
+			u.rules = nil
+			if allow {
+				u.rules = []commandRule{rule}
+			}
+			return nil
+		}
+		if !slices.Contains(Categories, rule.category) {
+			return errors.New("Unknown command or category name in ACL")
+		}
+	} else {
+		rule.command = value
+		if !knownCommand(strings.SplitN(value, "|", 2)[0]) {
+			return errors.New("Unknown command or category name in ACL")
+		}
+	}
+
+	u.rules = append(u.rules, rule)
+	return nil
+}
+
+// CanRun reports whether the user may run the command, the last matching rule wins
+func (u *User) CanRun(commandName string, categories []string) bool {
+	allowed := false
+	for _, r := range u.rules {
+		if r.matches(commandName, categories) {
+			allowed = r.allow
+		}
+	}
+	return allowed
+}
+
+func (u *User) CanAccessKey(key string) bool {
+	for _, pattern := range u.KeyPatterns {
+		if utils.GlobMatch(pattern, key) {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *User) CheckPassword(password string) bool {
+	return u.NoPass || u.Passwords[HashPassword(password)]
+}
+
+// Flags returns user flags as listed by ACL GETUSER
+func (u *User) Flags() []string {
+	flags := []string{"off"}
+	if u.Enabled {
+		flags[0] = "on"
+	}
+	if u.NoPass {
+		flags = append(flags, "nopass")
+	}
+	return flags
+}
+
+func (u *User) PasswordHashes() []string {
+	return slices.Sorted(maps.Keys(u.Passwords))
+}
+
+// CommandRules describes command permissions, e.g. "+@all -config"
+func (u *User) CommandRules() string {
+	rules := make([]string, 0, len(u.rules)+1)
+	if len(u.rules) == 0 || u.rules[0].category != "all" {
+		rules = append(rules, "-@all")
+	}
+	for _, r := range u.rules {
+		rules = append(rules, r.String())
+	}
+	return strings.Join(rules, " ")
+}
+
+func (u *User) KeyRules() string {
+	rules := make([]string, len(u.KeyPatterns))
+	for n, pattern := range u.KeyPatterns {
+		rules[n] = "~" + pattern
+	}
+	return strings.Join(rules, " ")
+}
+
+// Describe returns the user in the ACL file format used by ACL LIST and ACL SAVE
+func (u *User) Describe() string {
+	parts := []string{"user", u.Name}
+	parts = append(parts, u.Flags()...)
+	for _, hash := range u.PasswordHashes() {
+		parts = append(parts, "#"+hash)
+	}
+	if keys := u.KeyRules(); keys != "" {
+		parts = append(parts, keys)
+	} else {
+		parts = append(parts, "resetkeys")
+	}
+	parts = append(parts, u.CommandRules())
+	return strings.Join(parts, " ")
+}
+
+// RuleError describes the first invalid rule of ACL SETUSER
+type RuleError struct {
+	Rule string
+	Err  error
+}
+
+func (e *RuleError) Error() string {
+	return fmt.Sprintf("Error in ACL SETUSER modifier '%s': %s", e.Rule, e.Err.Error())
+}
@@ -0,0 +1,108 @@
+package acl
+
+import (
+	"testing"
+)
+
+func TestUserPermissions(t *testing.T) {
+	var tests = []struct {
+		name       string
+		rules      []string
+		command    string
+		categories []string
+		key        string
+		wantRun    bool
+		wantKey    bool
+	}{
+		{
+			name:    "New user has no permissions",
+			rules:   []string{"on"},
+			command: "get",
+			key:     "a",
+		},
+		{
+			name:       "Category rule should allow commands of the category",
+			rules:      []string{"on", "+@read", "~*"},
+			command:    "get",
+			categories: []string{"read", "string", "fast"},
+			key:        "a",
+			wantRun:    true,
+			wantKey:    true,
+		},
+		{
+			name:       "Later command rule should override category rule",
+			rules:      []string{"on", "+@all", "-get", "~cache:*"},
+			command:    "get",
+			categories: []string{"read", "string", "fast"},
+			key:        "cache:1",
+			wantKey:    true,
+		},
+		{
+			name:       "Container command rule should apply to subcommands",
+			rules:      []string{"on", "+client", "-client|kill"},
+			command:    "client|list",
+			categories: []string{"admin"},
+			key:        "other",
+			wantRun:    true,
+		},
+		{
+			name:       "Subcommand rule should deny the subcommand",
+			rules:      []string{"on", "+client", "-client|kill"},
+			command:    "client|kill",
+			categories: []string{"admin"},
+		},
+		{
+			name:       "Reset should drop all permissions",
+			rules:      []string{"allcommands", "allkeys", "reset"},
+			command:    "ping",
+			categories: []string{"fast"},
+			key:        "a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := applyRules(nil, "test", tt.rules)
+			if err != nil {
+				t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+			}
+			if got := u.CanRun(tt.command, tt.categories); got != tt.wantRun {
+				t.Errorf("ERROR CanRun got %v, want %v", got, tt.wantRun)
+			}
+			if tt.key != "" {
+				if got := u.CanAccessKey(tt.key); got != tt.wantKey {
+					t.Errorf("ERROR CanAccessKey got %v, want %v", got, tt.wantKey)
+				}
+			}
+		})
+	}
+}
+
+func TestUserPasswords(t *testing.T) {
+	u, err := applyRules(nil, "test", []string{"on", ">first", ">second", "<first"})
+	if err != nil {
+		t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+	}
+	if u.CheckPassword("first") {
+		t.Errorf("ERROR removed password should be rejected")
+	}
+	if !u.CheckPassword("second") {
+		t.Errorf("ERROR password should be accepted")
+	}
+
+	u, err = applyRules(u, "test", []string{"nopass"})
+	if err != nil {
+		t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+	}
+	if !u.CheckPassword("anything") || len(u.Passwords) != 0 {
+		t.Errorf("ERROR nopass user should accept any password")
+	}
+}
+
+func TestInvalidRule(t *testing.T) {
+	for _, rules := range [][]string{{"on", "+@nonexisting"}, {"bogus"}, {"#nothex"}} {
+		if _, err := applyRules(nil, "test", rules); err == nil {
+			t.Errorf("ERROR error expected for rules %q", rules)
+		}
+	}
+}
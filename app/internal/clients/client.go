@@ -0,0 +1,334 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+type ReplyMode int
+
+const (
+	ReplyOn ReplyMode = iota
+	ReplyOff
+)
+
+// Causes of a blocked command being woken up by CLIENT UNBLOCK
+var (
+	ErrUnblockedTimeout = errors.New("client unblocked via CLIENT UNBLOCK TIMEOUT")
+	ErrUnblockedError   = errors.New("client unblocked via CLIENT UNBLOCK")
+	ErrClientKilled     = errors.New("client killed")
+)
+
+// DefaultUser is the user every connection starts with
+const DefaultUser = "default"
+
+type Client struct {
+	ID        int64
+	Addr      string
+	LocalAddr string
+	CreatedAt time.Time
+
+	conn net.Conn
+
+	mu              sync.Mutex
+	name            string
+	user            string
+	authenticated   bool
+	protocol        respparser.Protocol
+	lastInteraction time.Time
+	lastCommand     string
+	replyMode       ReplyMode
+	skipCurrent     bool // reply of the running command is skipped
+	skipNext        bool // reply of the next command is skipped
+	closeAfterReply bool
+	subscriptions   int                     // subscribed Pub/Sub channels
+	psubscriptions  int                     // subscribed Pub/Sub patterns
+	unblock         context.CancelCauseFunc // set while the client is blocked
+}
+
+type clientRegistry struct {
+	mu      sync.RWMutex
+	clients map[int64]*Client
+	nextID  atomic.Int64
+}
+
+var registry = clientRegistry{
+	clients: map[int64]*Client{},
+}
+
+// New registers a client for the connection
+func New(conn net.Conn) *Client {
+	now := time.Now()
+	c := &Client{
+		ID:              registry.nextID.Add(1),
+		Addr:            addrString(conn.RemoteAddr()),
+		LocalAddr:       addrString(conn.LocalAddr()),
+		CreatedAt:       now,
+		conn:            conn,
+		user:            DefaultUser,
+		protocol:        respparser.Resp2,
+		lastInteraction: now,
+		lastCommand:     "NULL",
+	}
+
+	registry.mu.Lock()
+	registry.clients[c.ID] = c
+	registry.mu.Unlock()
+	return c
+}
+
+func addrString(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+	if addr.Network() == "unix" {
+		// remark: same as Redis, unix socket clients are listed by the socket path
+		return addr.String() + ":0"
+	}
+	return addr.String()
+}
+
+// Remove unregisters the client once its connection is closed
+func Remove(c *Client) {
+	registry.mu.Lock()
+	delete(registry.clients, c.ID)
+	registry.mu.Unlock()
+}
+
+func Get(id int64) (*Client, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	c, found := registry.clients[id]
+	return c, found
+}
+
+// List returns all connected clients ordered by ID
+func List() []*Client {
+	registry.mu.RLock()
+	clients := make([]*Client, 0, len(registry.clients))
+	for _, c := range registry.clients {
+		clients = append(clients, c)
+	}
+	registry.mu.RUnlock()
+
+	slices.SortFunc(clients, func(a, b *Client) int { return int(a.ID - b.ID) })
+	return clients
+}
+
+func (c *Client) Name() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.name
+}
+
+func (c *Client) SetName(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.name = name
+}
+
+func (c *Client) User() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.user
+}
+
+func (c *Client) SetUser(user string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.user = user
+}
+
+// Protocol returns the RESP version negotiated by HELLO
+func (c *Client) Protocol() respparser.Protocol {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.protocol
+}
+
+func (c *Client) SetProtocol(protocol respparser.Protocol) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.protocol = protocol
+}
+
+// Authenticate switches the client to the user and marks it as authenticated
+func (c *Client) Authenticate(user string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.user = user
+	c.authenticated = true
+}
+
+func (c *Client) Authenticated() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.authenticated
+}
+
+// SetSubscriptions records the number of subscribed Pub/Sub channels and patterns
+func (c *Client) SetSubscriptions(channels int, patterns int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.subscriptions = channels
+	c.psubscriptions = patterns
+}
+
+// IsSubscriber reports whether the client has subscribed any Pub/Sub channel or pattern
+func (c *Client) IsSubscriber() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.subscriptions+c.psubscriptions > 0
+}
+
+// Reset restores the connection defaults as RESET does, the client is left unauthenticated
+func (c *Client) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.name = ""
+	c.user = DefaultUser
+	c.authenticated = false
+	c.protocol = respparser.Resp2
+	c.replyMode = ReplyOn
+	c.skipNext = false
+}
+
+// Touch records a command received from the client
+func (c *Client) Touch(commandName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastInteraction = time.Now()
+	c.lastCommand = commandName
+}
+
+func (c *Client) SetReplyMode(mode ReplyMode) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.replyMode = mode
+}
+
+// SkipNextReply suppresses the reply of the next command (CLIENT REPLY SKIP)
+func (c *Client) SkipNextReply() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.replyMode == ReplyOn {
+		c.skipNext = true
+	}
+}
+
+// ReplyAllowed is called once per processed command and reports whether its reply is sent
+func (c *Client) ReplyAllowed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	allowed := c.replyMode == ReplyOn && !c.skipCurrent
+	c.skipCurrent = c.skipNext
+	c.skipNext = false
+	return allowed
+}
+
+// CloseAfterReply reports whether the connection must be closed once the pending replies are written
+func (c *Client) CloseAfterReply() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closeAfterReply
+}
+
+// Kill closes the client connection and wakes the client up if it's blocked.
+// When killed by itself, the connection is closed after the reply is written.
+func (c *Client) Kill(self bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if self {
+		c.closeAfterReply = true
+		return
+	}
+	if c.unblock != nil {
+		c.unblock(ErrClientKilled)
+	}
+	c.conn.Close()
+}
+
+// Block returns a context the blocking command waits on, it's cancelled by CLIENT UNBLOCK
+// or CLIENT KILL. The returned cancel function must be called once the command is unblocked.
+func (c *Client) Block(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+
+	c.mu.Lock()
+	c.unblock = cancel
+	c.mu.Unlock()
+
+	return ctx, func() {
+		c.mu.Lock()
+		c.unblock = nil
+		c.mu.Unlock()
+		cancel(nil)
+	}
+}
+
+func (c *Client) IsBlocked() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.unblock != nil
+}
+
+// Unblock wakes up the blocked client, it reports false when the client isn't blocked
+func (c *Client) Unblock(cause error) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.unblock == nil {
+		return false
+	}
+	c.unblock(cause)
+	return true
+}
+
+// Info returns the client description used by CLIENT LIST and CLIENT INFO
+func (c *Client) Info() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	flags := ""
+	if c.unblock != nil {
+		flags += "b"
+	}
+	if c.closeAfterReply {
+		flags += "c"
+	}
+	if c.subscriptions+c.psubscriptions > 0 {
+		flags += "P"
+	}
+	if flags == "" {
+		flags = "N"
+	}
+
+	fields := []string{
+		fmt.Sprintf("id=%d", c.ID),
+		fmt.Sprintf("addr=%s", c.Addr),
+		fmt.Sprintf("laddr=%s", c.LocalAddr),
+		fmt.Sprintf("name=%s", c.name),
+		fmt.Sprintf("age=%d", int(now.Sub(c.CreatedAt).Seconds())),
+		fmt.Sprintf("idle=%d", int(now.Sub(c.lastInteraction).Seconds())),
+		fmt.Sprintf("flags=%s", flags),
+		"db=0",
+		fmt.Sprintf("sub=%d", c.subscriptions),
+		fmt.Sprintf("psub=%d", c.psubscriptions),
+		fmt.Sprintf("cmd=%s", c.lastCommand),
+		fmt.Sprintf("user=%s", c.user),
+		fmt.Sprintf("resp=%d", c.protocol),
+	}
+	return strings.Join(fields, " ")
+}
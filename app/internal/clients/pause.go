@@ -0,0 +1,69 @@
+package clients
+
+import (
+	"sync"
+	"time"
+)
+
+type PauseMode int
+
+const (
+	PauseWrite PauseMode = iota
+	PauseAll
+)
+
+var pause = struct {
+	mu     sync.Mutex
+	until  time.Time
+	mode   PauseMode
+	resume chan struct{} // closed when the pause is over
+}{
+	resume: make(chan struct{}),
+}
+
+// Pause suspends processing of client commands for the duration (CLIENT PAUSE)
+func Pause(duration time.Duration, mode PauseMode) {
+	pause.mu.Lock()
+	defer pause.mu.Unlock()
+
+	now := time.Now()
+	// remark: a running pause can be extended or made stricter, but not relaxed
+	if !pause.until.After(now) || mode == PauseAll {
+		pause.mode = mode
+	}
+	if until := now.Add(duration); until.After(pause.until) {
+		pause.until = until
+	}
+}
+
+// Unpause resumes all paused clients (CLIENT UNPAUSE)
+func Unpause() {
+	pause.mu.Lock()
+	defer pause.mu.Unlock()
+
+	pause.until = time.Time{}
+	close(pause.resume)
+	pause.resume = make(chan struct{})
+}
+
+// WaitIfPaused blocks while commands are paused. In PauseWrite mode only write commands wait.
+func WaitIfPaused(isWrite bool) {
+	for {
+		pause.mu.Lock()
+		remaining := time.Until(pause.until)
+		affected := pause.mode == PauseAll || isWrite
+		resume := pause.resume
+		pause.mu.Unlock()
+
+		if remaining <= 0 || !affected {
+			return
+		}
+
+		timer := time.NewTimer(remaining)
+		select {
+		case <-resume:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
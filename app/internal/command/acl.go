@@ -0,0 +1,193 @@
+package command
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/acl"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/clients"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/config"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+type AclCommand struct {
+	Subcommand string
+	Args       []string
+}
+
+// defaultAclLogCount is the number of entries returned by ACL LOG without count
+const defaultAclLogCount = 10
+
+func (c AclCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	utils.Log(fmt.Sprintf("(AclCommand) Processing ACL %s", c.Subcommand))
+
+	switch c.Subcommand {
+	case "SETUSER":
+		if err := acl.SetUser(c.Args[0], c.Args[1:]); err != nil {
+			return respparser.SimpleError{}, toError(err)
+		}
+		return okResponse, nil
+
+	case "GETUSER":
+		u, found := acl.GetUser(c.Args[0])
+		if !found {
+			return respparser.Array{IsNull: true}, nil
+		}
+		return describeAclUser(u), nil
+
+	case "DELUSER":
+		deleted, err := acl.DelUser(c.Args)
+		if err != nil {
+			return respparser.SimpleError{}, toError(err)
+		}
+		killUserClients(c.Args, cmdCtx.Client)
+		return respparser.Integer{Value: deleted}, nil
+
+	case "LIST":
+		result := respparser.Array{}
+		for _, u := range acl.Users() {
+			result.Items = append(result.Items, respparser.BulkString{Value: u.Describe()})
+		}
+		return result, nil
+
+	case "USERS":
+		result := respparser.Array{}
+		for _, u := range acl.Users() {
+			result.Items = append(result.Items, respparser.BulkString{Value: u.Name})
+		}
+		return result, nil
+
+	case "WHOAMI":
+		return respparser.BulkString{Value: cmdCtx.Client.User()}, nil
+
+	case "CAT":
+		return aclCategories(c.Args)
+
+	case "LOG":
+		return aclLog(c.Args)
+
+	case "LOAD", "SAVE":
+		path := config.GetString("aclfile")
+		if path == "" {
+			return respparser.SimpleError{}, Errorf(CodeErr, "This Redis instance is not configured to use an ACL file. You may want to specify users via the ACL SETUSER command and then issue a CONFIG REWRITE (assuming you have a Redis configuration file set) in order to store users in the Redis configuration.")
+		}
+		if c.Subcommand == "LOAD" {
+			if err := acl.LoadFile(path); err != nil {
+				return respparser.SimpleError{}, toError(err)
+			}
+			return okResponse, nil
+		}
+		if err := acl.SaveFile(path); err != nil {
+			utils.LogWarning(fmt.Sprintf("(AclCommand) ACL SAVE failed: %s", err.Error()))
+			return respparser.SimpleError{}, Errorf(CodeErr, "There was an error trying to save the ACLs. Please check the server logs for more information")
+		}
+		return okResponse, nil
+
+	default:
+		return respparser.SimpleError{}, errUnknownSubcommand("ACL", c.Subcommand)
+	}
+}
+
+func describeAclUser(u *acl.User) respparser.Map {
+	flags := respparser.Set{}
+	for _, flag := range u.Flags() {
+		flags.Items = append(flags.Items, respparser.BulkString{Value: flag})
+	}
+	passwords := respparser.Array{}
+	for _, hash := range u.PasswordHashes() {
+		passwords.Items = append(passwords.Items, respparser.BulkString{Value: hash})
+	}
+
+	description := respparser.Map{}
+	description.Add("flags", flags)
+	description.Add("passwords", passwords)
+	description.Add("commands", respparser.BulkString{Value: u.CommandRules()})
+	description.Add("keys", respparser.BulkString{Value: u.KeyRules()})
+	return description
+}
+
+// killUserClients disconnects clients authenticated as one of the deleted users
+func killUserClients(names []string, self *clients.Client) {
+	for _, c := range clients.List() {
+		if slices.Contains(names, c.User()) {
+			c.Kill(c == self)
+		}
+	}
+}
+
+// aclCategories lists all categories, or commands of the given category
+func aclCategories(args []string) (respparser.RespData, error) {
+	result := respparser.Array{}
+	if len(args) == 0 {
+		for _, category := range acl.Categories {
+			result.Items = append(result.Items, respparser.BulkString{Value: category})
+		}
+		return result, nil
+	}
+
+	category := strings.ToLower(args[0])
+	if !slices.Contains(acl.Categories, category) {
+		return respparser.SimpleError{}, Errorf(CodeErr, "Unknown category '%s'", args[0])
+	}
+	names := []string{}
+	for name, spec := range commandSpecs {
+		if slices.Contains(spec.aclCategories(), category) {
+			names = append(names, strings.ToLower(name))
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		result.Items = append(result.Items, respparser.BulkString{Value: name})
+	}
+	return result, nil
+}
+
+func aclLog(args []string) (respparser.RespData, error) {
+	count := defaultAclLogCount
+	if len(args) == 1 {
+		if strings.ToUpper(args[0]) == "RESET" {
+			acl.ResetLog()
+			return okResponse, nil
+		}
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 0 {
+			return respparser.SimpleError{}, Errorf(CodeErr, "value is out of range, must be positive")
+		}
+		count = n
+	}
+
+	now := time.Now()
+	result := respparser.Array{}
+	for _, e := range acl.LogEntries(count) {
+		entry := respparser.Map{}
+		entry.Add("count", respparser.Integer{Value: e.Count})
+		entry.Add("reason", respparser.BulkString{Value: e.Reason})
+		entry.Add("context", respparser.BulkString{Value: e.Context})
+		entry.Add("object", respparser.BulkString{Value: e.Object})
+		entry.Add("username", respparser.BulkString{Value: e.Username})
+		entry.Add("age-seconds", respparser.Double{Value: now.Sub(e.Created).Seconds()})
+		entry.Add("client-info", respparser.BulkString{Value: e.ClientInfo})
+		entry.Add("entry-id", respparser.Integer{Value: int(e.ID)})
+		entry.Add("timestamp-created", respparser.Integer{Value: int(e.Created.UnixMilli())})
+		entry.Add("timestamp-last-updated", respparser.Integer{Value: int(e.Updated.UnixMilli())})
+		result.Items = append(result.Items, entry)
+	}
+	return result, nil
+}
+
+func parseAclCommand(command *Command) (AclCommand, error) {
+	aclCommand := AclCommand{
+		Subcommand: strings.ToUpper(command.CommandValues[0]),
+		Args:       command.CommandValues[1:],
+	}
+
+	// remark: the minimal number of arguments is checked by the registry
+	if (aclCommand.Subcommand == "CAT" || aclCommand.Subcommand == "LOG") && len(aclCommand.Args) > 1 {
+		return AclCommand{}, errWrongArgs("acl|" + aclCommand.Subcommand)
+	}
+	return aclCommand, nil
+}
@@ -0,0 +1,40 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/acl"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+type AuthCommand struct {
+	Username string
+	Password string
+}
+
+var errWrongPass = Errorf(CodeWrongPass, "invalid username-password pair or user is disabled.")
+
+func (c AuthCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	utils.Log(fmt.Sprintf("(AuthCommand) Authenticating user %s", c.Username))
+
+	if !acl.Authenticate(c.Username, c.Password) {
+		acl.AddLogEntry(acl.LogReasonAuth, "AUTH", c.Username, cmdCtx.Client.Info())
+		return respparser.SimpleError{}, errWrongPass
+	}
+
+	cmdCtx.Client.Authenticate(c.Username)
+	return okResponse, nil
+}
+
+func parseAuthCommand(command *Command) (AuthCommand, error) {
+	switch len(command.CommandValues) {
+	case 1:
+		// remark: the legacy form authenticates the default user
+		return AuthCommand{Username: acl.DefaultUserName, Password: command.CommandValues[0]}, nil
+	case 2:
+		return AuthCommand{Username: command.CommandValues[0], Password: command.CommandValues[1]}, nil
+	default:
+		return AuthCommand{}, errSyntax
+	}
+}
@@ -0,0 +1,282 @@
+package command
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/clients"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+type ClientCommand struct {
+	Subcommand string
+	Args       []string
+}
+
+// clientKillFilter holds filters of CLIENT KILL, all set filters must match
+type clientKillFilter struct {
+	id     int64
+	addr   string
+	laddr  string
+	user   string
+	skipMe bool
+	maxAge int64
+}
+
+func (f clientKillFilter) matches(c *clients.Client, self *clients.Client) bool {
+	if f.skipMe && c == self {
+		return false
+	}
+	if f.id != 0 && c.ID != f.id {
+		return false
+	}
+	if f.addr != "" && c.Addr != f.addr {
+		return false
+	}
+	if f.laddr != "" && c.LocalAddr != f.laddr {
+		return false
+	}
+	if f.user != "" && c.User() != f.user {
+		return false
+	}
+	if f.maxAge != 0 && int64(time.Since(c.CreatedAt).Seconds()) < f.maxAge {
+		return false
+	}
+	return true
+}
+
+var errNoSuchClient = Errorf(CodeErr, "No such client")
+
+// validateClientName allows printable characters without spaces only
+func validateClientName(name string) error {
+	for i := 0; i < len(name); i++ {
+		if name[i] < '!' || name[i] > '~' {
+			return Errorf(CodeErr, "Client names cannot contain spaces, newlines or special characters.")
+		}
+	}
+	return nil
+}
+
+func (c ClientCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	utils.Log(fmt.Sprintf("(ClientCommand) Processing CLIENT %s", c.Subcommand))
+	self := cmdCtx.Client
+
+	switch c.Subcommand {
+	case "ID":
+		return respparser.Integer{Value: int(self.ID)}, nil
+
+	case "INFO":
+		return respparser.BulkString{Value: self.Info() + "\n"}, nil
+
+	case "LIST":
+		return c.list()
+
+	case "SETNAME":
+		if err := validateClientName(c.Args[0]); err != nil {
+			return respparser.SimpleError{}, err
+		}
+		self.SetName(c.Args[0])
+		return okResponse, nil
+
+	case "GETNAME":
+		name := self.Name()
+		return respparser.BulkString{Value: name, IsNull: name == ""}, nil
+
+	case "KILL":
+		return c.kill(self)
+
+	case "PAUSE":
+		timeoutMillis, err := strconv.ParseInt(c.Args[0], 10, 64)
+		if err != nil || timeoutMillis < 0 {
+			return respparser.SimpleError{}, Errorf(CodeErr, "timeout is not an integer or out of range")
+		}
+
+		mode := clients.PauseAll
+		if len(c.Args) == 2 {
+			switch strings.ToUpper(c.Args[1]) {
+			case "WRITE":
+				mode = clients.PauseWrite
+			case "ALL":
+			default:
+				return respparser.SimpleError{}, errSyntax
+			}
+		}
+		clients.Pause(time.Duration(timeoutMillis)*time.Millisecond, mode)
+		return okResponse, nil
+
+	case "UNPAUSE":
+		clients.Unpause()
+		return okResponse, nil
+
+	case "UNBLOCK":
+		id, err := strconv.ParseInt(c.Args[0], 10, 64)
+		if err != nil {
+			return respparser.SimpleError{}, errNotInteger
+		}
+
+		cause := clients.ErrUnblockedTimeout
+		if len(c.Args) == 2 {
+			switch strings.ToUpper(c.Args[1]) {
+			case "TIMEOUT":
+			case "ERROR":
+				cause = clients.ErrUnblockedError
+			default:
+				return respparser.SimpleError{}, Errorf(CodeErr, "CLIENT UNBLOCK reason should be TIMEOUT or ERROR")
+			}
+		}
+
+		target, found := clients.Get(id)
+		if found && target.Unblock(cause) {
+			return respparser.Integer{Value: 1}, nil
+		}
+		return respparser.Integer{Value: 0}, nil
+
+	case "REPLY":
+		switch strings.ToUpper(c.Args[0]) {
+		case "ON":
+			self.SetReplyMode(clients.ReplyOn)
+			return okResponse, nil
+		case "OFF":
+			self.SetReplyMode(clients.ReplyOff)
+			return NoReply{}, nil
+		case "SKIP":
+			self.SkipNextReply()
+			return NoReply{}, nil
+		default:
+			return respparser.SimpleError{}, errSyntax
+		}
+
+	default:
+		return respparser.SimpleError{}, errUnknownSubcommand("CLIENT", c.Subcommand)
+	}
+}
+
+func (c ClientCommand) list() (respparser.RespData, error) {
+	connected := clients.List()
+
+	if len(c.Args) > 0 {
+		filterType := strings.ToUpper(c.Args[0])
+		switch {
+		case filterType == "TYPE" && len(c.Args) == 2:
+			switch clientType := strings.ToLower(c.Args[1]); clientType {
+			case "normal", "pubsub":
+				filtered := []*clients.Client{}
+				for _, cl := range connected {
+					if cl.IsSubscriber() == (clientType == "pubsub") {
+						filtered = append(filtered, cl)
+					}
+				}
+				connected = filtered
+			case "master", "replica", "slave":
+				// remark: there are no such clients yet
+				connected = nil
+			default:
+				return respparser.SimpleError{}, Errorf(CodeErr, "Unknown client type '%s'", c.Args[1])
+			}
+		case filterType == "ID" && len(c.Args) > 1:
+			ids := map[int64]bool{}
+			for _, arg := range c.Args[1:] {
+				id, err := strconv.ParseInt(arg, 10, 64)
+				if err != nil || id <= 0 {
+					return respparser.SimpleError{}, Errorf(CodeErr, "Invalid client ID")
+				}
+				ids[id] = true
+			}
+			filtered := []*clients.Client{}
+			for _, cl := range connected {
+				if ids[cl.ID] {
+					filtered = append(filtered, cl)
+				}
+			}
+			connected = filtered
+		default:
+			return respparser.SimpleError{}, errSyntax
+		}
+	}
+
+	var list strings.Builder
+	for _, cl := range connected {
+		list.WriteString(cl.Info())
+		list.WriteString("\n")
+	}
+	return respparser.BulkString{Value: list.String()}, nil
+}
+
+func (c ClientCommand) kill(self *clients.Client) (respparser.RespData, error) {
+	// old form: CLIENT KILL addr:port
+	if len(c.Args) == 1 {
+		for _, cl := range clients.List() {
+			if cl.Addr == c.Args[0] {
+				cl.Kill(cl == self)
+				return okResponse, nil
+			}
+		}
+		return respparser.SimpleError{}, errNoSuchClient
+	}
+
+	if len(c.Args)%2 != 0 {
+		return respparser.SimpleError{}, errSyntax
+	}
+
+	filter := clientKillFilter{skipMe: true}
+	for i := 0; i < len(c.Args); i += 2 {
+		value := c.Args[i+1]
+		switch strings.ToUpper(c.Args[i]) {
+		case "ID":
+			id, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || id <= 0 {
+				return respparser.SimpleError{}, Errorf(CodeErr, "client-id should be greater than 0")
+			}
+			filter.id = id
+		case "ADDR":
+			filter.addr = value
+		case "LADDR":
+			filter.laddr = value
+		case "USER":
+			filter.user = value
+		case "SKIPME":
+			switch strings.ToLower(value) {
+			case "yes":
+				filter.skipMe = true
+			case "no":
+				filter.skipMe = false
+			default:
+				return respparser.SimpleError{}, errSyntax
+			}
+		case "MAXAGE":
+			maxAge, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || maxAge <= 0 {
+				return respparser.SimpleError{}, errSyntax
+			}
+			filter.maxAge = maxAge
+		default:
+			return respparser.SimpleError{}, errSyntax
+		}
+	}
+
+	killed := 0
+	for _, cl := range clients.List() {
+		if filter.matches(cl, self) {
+			utils.LogNotice(fmt.Sprintf("(ClientCommand) Killing client id=%d addr=%s", cl.ID, cl.Addr))
+			cl.Kill(cl == self)
+			killed++
+		}
+	}
+	return respparser.Integer{Value: killed}, nil
+}
+
+func parseClientCommand(command *Command) (ClientCommand, error) {
+	clientCommand := ClientCommand{
+		Subcommand: strings.ToUpper(command.CommandValues[0]),
+		Args:       command.CommandValues[1:],
+	}
+
+	// remark: the minimal number of arguments is checked by the registry
+	if (clientCommand.Subcommand == "PAUSE" || clientCommand.Subcommand == "UNBLOCK") && len(clientCommand.Args) > 2 {
+		return ClientCommand{}, errWrongArgs("client|" + clientCommand.Subcommand)
+	}
+	return clientCommand, nil
+}
@@ -2,9 +2,15 @@ package command
 
 import (
 	"fmt"
+	"sync/atomic"
+	"time"
 
-	"github.com/codecrafters-io/redis-starter-go/app/internal/respparser"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/acl"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/clients"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/pubsub"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/stats"
 	"github.com/codecrafters-io/redis-starter-go/app/internal/store"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
 )
 
 type Command struct {
@@ -12,8 +18,41 @@ type Command struct {
 	CommandValues []string
 }
 
+// CommandContext holds state of the connection the command was received on
 type CommandContext struct {
-	KeyStore *store.KeyStore
+	Client *clients.Client
+
+	transaction          *transaction // commands queued after MULTI, nil outside of transactions
+	executingTransaction bool         // set while EXEC runs queued commands, blocking commands don't block then
+	watchedKeys          []watchedKey
+	watchedKeyTouched    atomic.Bool // set by other connections modifying a watched key
+	touchedKeys          []string    // keys modified by the running command
+	subscriber           *pubsub.Subscriber
+}
+
+// NewCommandContext creates context of a new connection, the connection is authenticated
+// as the default user right away unless the default user requires a password
+func NewCommandContext(c *clients.Client) *CommandContext {
+	if acl.DefaultUserNoPass() {
+		c.Authenticate(acl.DefaultUserName)
+	}
+	// remark: a subscriber not reading its messages is disconnected, the same as Redis does
+	// once the pubsub output buffer limit is reached
+	subscriber := pubsub.NewSubscriber(func() { c.Kill(false) })
+	return &CommandContext{Client: c, subscriber: subscriber}
+}
+
+// Messages returns messages published to channels the connection subscribed
+func (c *CommandContext) Messages() <-chan pubsub.Message {
+	return c.subscriber.Messages()
+}
+
+// Release frees state of the closed connection
+func (c *CommandContext) Release() {
+	unwatchAllKeys(c)
+	if c.subscriber != nil {
+		pubsub.Close(c.subscriber)
+	}
 }
 
 type CommandResponse struct {
@@ -24,7 +63,10 @@ var okResponse = respparser.SimpleString{
 	Value: "OK",
 }
 
-type PingCommand struct{}
+type PingCommand struct {
+	Message    string
+	HasMessage bool
+}
 
 type EchoCommand struct {
 	Message string
@@ -35,9 +77,13 @@ type GetCommand struct {
 }
 
 type SetCommand struct {
-	Key                    string
-	Value                  string
-	RecordExpirationMillis int
+	Key       string
+	Value     string
+	Condition store.SetCondition
+	Get       bool      // reply the old value instead of OK
+	KeepTtl   bool      // keep the expiry of the old value
+	TtlMillis int64     // EX and PX, counted from the time the command is processed
+	ExpireAt  time.Time // EXAT and PXAT
 }
 
 type TypeCommand struct {
@@ -82,9 +128,27 @@ type XReadStream struct {
 	//keysIds map[string]EntryId
 }
 
+// NoReply is returned by commands that must not send any reply to the client
+type NoReply struct{}
+
+func (n NoReply) Type() respparser.RespDataType { return 0 }
+func (n NoReply) String() string                { return "" }
+func (n NoReply) DebugString() string           { return "No reply" }
+
+// Replies are sent to the client one by one, e.g. SUBSCRIBE replies a confirmation per channel
+type Replies struct {
+	Items []respparser.RespData
+}
+
+func (r Replies) Type() respparser.RespDataType { return 0 }
+func (r Replies) String() string                { return respparser.Array{Items: r.Items}.String() }
+func (r Replies) DebugString() string           { return respparser.Array{Items: r.Items}.DebugString() }
+
+// ErrorResponse replies the error, errors without a code are replied with the generic ERR code
 func ErrorResponse(e error) CommandResponse {
+	stats.TotalErrorReplies.Add(1)
 	errContent := respparser.SimpleError{
-		Value: e.Error(),
+		Value: toError(e).Error(),
 	}
 	return CommandResponse{Value: errContent}
 }
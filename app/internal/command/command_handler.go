@@ -3,36 +3,48 @@ package command
 // TODO split by each command
 
 import (
-	"errors"
 	"fmt"
 	"time"
 
-	"github.com/codecrafters-io/redis-starter-go/app/internal/respparser"
 	"github.com/codecrafters-io/redis-starter-go/app/internal/store"
 	"github.com/codecrafters-io/redis-starter-go/app/internal/streamstore"
 	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
 )
 
 type CommandHandler[T any] interface {
-	Process() (respparser.RespData, error)
+	Process(cmdCtx *CommandContext) (respparser.RespData, error)
 }
 
-func (c EchoCommand) Process() (respparser.RespData, error) {
+func (c EchoCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
 	resp := respparser.BulkString{
 		Value: c.Message,
 	}
 	return resp, nil
 }
 
-func (c PingCommand) Process() (respparser.RespData, error) {
+func (c PingCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	// remark: subscribed RESP2 clients get the reply in the form of pushed messages
+	if cmdCtx.inSubscriberMode() {
+		return respparser.Array{Items: []respparser.RespData{
+			respparser.BulkString{Value: "pong"},
+			respparser.BulkString{Value: c.Message},
+		}}, nil
+	}
+	if c.HasMessage {
+		return respparser.BulkString{Value: c.Message}, nil
+	}
 	pong := respparser.SimpleString{
 		Value: "PONG",
 	}
 	return pong, nil
 }
 
-func (c GetCommand) Process() (respparser.RespData, error) {
-	get, found := store.Get(c.Key)
+func (c GetCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	get, found, err := store.Get(c.Key)
+	if err != nil {
+		return respparser.SimpleError{}, err
+	}
 	var resp respparser.BulkString
 
 	if found {
@@ -48,135 +60,124 @@ func (c GetCommand) Process() (respparser.RespData, error) {
 	return resp, nil
 }
 
-func (c SetCommand) Process() (respparser.RespData, error) {
+func (c SetCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
 	keyStoreValue := store.KeyStoreValue{
+		Key:              c.Key,
+		Value:            c.Value,
 		InsertedDatetime: time.Now(),
 	}
 
-	expires := keyStoreValue.InsertedDatetime.Add(time.Duration(c.RecordExpirationMillis) * time.Millisecond)
-
-	keyStoreValue.Key = c.Key
-	keyStoreValue.Value = c.Value
-	keyStoreValue.Expire = &expires
-
-	store.Append(keyStoreValue)
-	return okResponse, nil
-}
-
-func (c TypeCommand) Process() (respparser.RespData, error) {
-
-	_, foundInKv := store.Get(c.Key)
-	if foundInKv {
-		resp := respparser.SimpleString{
-			Value: "string",
-		}
-		return resp, nil
+	// remark: without an expiry option the key never expires
+	if c.TtlMillis > 0 {
+		expires := time.UnixMilli(keyStoreValue.InsertedDatetime.UnixMilli() + c.TtlMillis)
+		keyStoreValue.Expire = &expires
+	} else if !c.ExpireAt.IsZero() {
+		expires := c.ExpireAt
+		keyStoreValue.Expire = &expires
 	}
 
-	_, foundInStream := streamstore.GetTopItem(c.Key)
-	if foundInStream {
-		resp := respparser.SimpleString{
-			Value: "stream",
-		}
-		return resp, nil
+	old, found, stored, err := store.Set(keyStoreValue, c.Condition, c.KeepTtl, c.Get)
+	if err != nil {
+		return respparser.SimpleError{}, err
+	}
+	if stored {
+		cmdCtx.touch(c.Key)
+	}
+	if c.Get {
+		return respparser.BulkString{Value: old.Value, IsNull: !found}, nil
 	}
+	if !stored {
+		return respparser.BulkString{IsNull: true}, nil
+	}
+	return okResponse, nil
+}
 
+func (c TypeCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
 	resp := respparser.SimpleString{
-		Value: "none",
+		Value: store.TypeOf(c.Key),
 	}
 	return resp, nil
 }
 
-func (c XAddCommand) Process() (respparser.RespData, error) {
-	millisecondsTime := c.EntryId.MillisecondsTime
-	sequenceNumber := c.EntryId.SequenceNumber
-
-	topItem, topItemFound := streamstore.GetTopItem(c.StreamKey)
+func (c XAddCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	// remark: the entry ID is generated and validated against the top item while the stream is locked
+	added, err := streamstore.AddEntry(c.StreamKey, func(topItem *streamstore.RedisStream) (streamstore.RedisStream, error) {
+		millisecondsTime := c.EntryId.MillisecondsTime
+		sequenceNumber := c.EntryId.SequenceNumber
 
-	if c.EntryId.AutoGenerated == FullyGeneratedEntryId {
-		// autogenerated sequence number time
-		millisecondsTime = time.Now().UnixMilli()
-		utils.Log(fmt.Sprintf("(XADD cmd) Autogenerated time: %d)", millisecondsTime))
-	}
+		if c.EntryId.AutoGenerated == FullyGeneratedEntryId {
+			// autogenerated sequence number time
+			millisecondsTime = time.Now().UnixMilli()
+			utils.Log(fmt.Sprintf("(XADD cmd) Autogenerated time: %d)", millisecondsTime))
+		}
 
-	if c.EntryId.AutoGenerated == PartiallyGeneratedEntryId || c.EntryId.AutoGenerated == FullyGeneratedEntryId {
-		// autogenerated sequence number
-		if topItemFound && topItem.EntryIdMillisecondsTime == millisecondsTime {
-			sequenceNumber = topItem.EntryIdSequenceNumber + 1
-		} else if millisecondsTime == 0 {
-			sequenceNumber = 1
-		} else {
-			sequenceNumber = 0
+		if c.EntryId.AutoGenerated == PartiallyGeneratedEntryId || c.EntryId.AutoGenerated == FullyGeneratedEntryId {
+			// autogenerated sequence number
+			if topItem != nil && topItem.EntryIdMillisecondsTime == millisecondsTime {
+				sequenceNumber = topItem.EntryIdSequenceNumber + 1
+			} else if millisecondsTime == 0 {
+				sequenceNumber = 1
+			} else {
+				sequenceNumber = 0
+			}
+			utils.Log(fmt.Sprintf("(XADD cmd) Autogenerated sequence number: %d)", sequenceNumber))
 		}
-		utils.Log(fmt.Sprintf("(XADD cmd) Autogenerated sequence number: %d)", sequenceNumber))
-	}
 
-	streamValue := streamstore.RedisStream{
-		StreamKey:               c.StreamKey,
-		EntryIdMillisecondsTime: millisecondsTime,
-		EntryIdSequenceNumber:   sequenceNumber,
-		StreamValues:            c.FieldValues,
-		InsertedDatetime:        time.Now(),
-	}
+		streamValue := streamstore.RedisStream{
+			StreamKey:               c.StreamKey,
+			EntryIdMillisecondsTime: millisecondsTime,
+			EntryIdSequenceNumber:   sequenceNumber,
+			StreamValues:            c.FieldValues,
+			InsertedDatetime:        time.Now(),
+		}
 
-	if err := validateEntryId(streamValue, &topItem, topItemFound); err != nil {
-		utils.Log(fmt.Sprintf("(XADD cmd) EntryId validation failed: %e)", err))
+		if err := validateEntryId(streamValue, topItem); err != nil {
+			utils.Log(fmt.Sprintf("(XADD cmd) EntryId validation failed: %e)", err))
+			return streamstore.RedisStream{}, err
+		}
+		utils.Log(fmt.Sprintf("(XADD cmd) Storing record: %v)", streamValue))
+		return streamValue, nil
+	})
+	if err != nil {
 		return respparser.BulkString{}, err
 	}
-
-	storeChannel := streamstore.GetStreamStoreChannel()
-	utils.Log(fmt.Sprintf("(XADD cmd) Sending record using channel: %v)", streamValue))
-	storeChannel <- streamValue
+	cmdCtx.touch(c.StreamKey)
 
 	resp := respparser.BulkString{
-		Value: fmt.Sprintf("%d-%d", millisecondsTime, sequenceNumber),
+		Value: added.StreamId(),
 	}
 
 	return resp, nil
 }
 
-func (c XRangeCommand) Process() (respparser.RespData, error) {
-	items, found := streamstore.GetItems(c.StreamKey, c.StartEntryId.MillisecondsTime, c.EndEntryId.MillisecondsTime, c.StartEntryId.SequenceNumber, c.EndEntryId.SequenceNumber)
+func (c XRangeCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	items, found, err := streamstore.GetItems(c.StreamKey, c.StartEntryId.MillisecondsTime, c.EndEntryId.MillisecondsTime, c.StartEntryId.SequenceNumber, c.EndEntryId.SequenceNumber)
+	if err != nil {
+		return respparser.SimpleError{}, err
+	}
 	if !found {
 		// stream not found
 		utils.Log(fmt.Sprintf("(XRangeCommand) Stream %s not found", c.StreamKey))
 		return respparser.Array{}, nil
 	}
 
-	result := respparser.Array{}
-	for _, i := range items {
-
-		// id
-		id := respparser.BulkString{
-			Value: i.StreamId(),
-		}
-		// values
-		values := respparser.Array{}
-		for k, v := range i.StreamValues {
-			bulkKey := respparser.BulkString{
-				Value: k,
-			}
-			bulkValue := respparser.BulkString{
-				Value: v,
-			}
-			values.Items = append(values.Items, bulkKey, bulkValue)
-		}
-
-		result.Items = append(result.Items, respparser.Array{Items: []respparser.RespData{id, values}})
-	}
-
-	return result, nil
+	// remark: entries are encoded one by one while the reply is written, range results can be large
+	return respparser.ArrayStream{
+		Count: len(items),
+		Item:  func(n int) respparser.RespData { return items[n].ToRespArray() },
+	}, nil
 }
 
-func validateEntryId(s streamstore.RedisStream, topItem *streamstore.RedisStream, topItemFound bool) error {
+// validateEntryId checks the ID is greater than ID of the top item, topItem is nil for empty streams
+func validateEntryId(s streamstore.RedisStream, topItem *streamstore.RedisStream) error {
 	if s.EntryIdMillisecondsTime == 0 && s.EntryIdSequenceNumber == 0 {
-		return errors.New("ERR The ID specified in XADD must be greater than 0-0")
+		return Errorf(CodeErr, "The ID specified in XADD must be greater than 0-0")
 	}
 
-	if topItemFound && (topItem.EntryIdMillisecondsTime > s.EntryIdMillisecondsTime) {
-		return errors.New("ERR The ID specified in XADD is equal or smaller than the target stream top item")
-	} else if topItemFound && (topItem.EntryIdMillisecondsTime == s.EntryIdMillisecondsTime && topItem.EntryIdSequenceNumber >= s.EntryIdSequenceNumber) {
-		return errors.New("ERR The ID specified in XADD is equal or smaller than the target stream top item")
+	if topItem != nil && (topItem.EntryIdMillisecondsTime > s.EntryIdMillisecondsTime) {
+		return Errorf(CodeErr, "The ID specified in XADD is equal or smaller than the target stream top item")
+	} else if topItem != nil && (topItem.EntryIdMillisecondsTime == s.EntryIdMillisecondsTime && topItem.EntryIdSequenceNumber >= s.EntryIdSequenceNumber) {
+		return Errorf(CodeErr, "The ID specified in XADD is equal or smaller than the target stream top item")
 	} else {
 		return nil
 	}
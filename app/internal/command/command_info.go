@@ -0,0 +1,259 @@
+package command
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+// CommandCommand describes commands of the registry, without subcommand all commands are described
+type CommandCommand struct {
+	Subcommand string
+	Args       []string
+}
+
+func (c CommandCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	utils.Log(fmt.Sprintf("(CommandCommand) Processing COMMAND %s", c.Subcommand))
+
+	switch c.Subcommand {
+	case "":
+		return commandInfo(topLevelCommands()), nil
+
+	case "COUNT":
+		return respparser.Integer{Value: len(topLevelCommands())}, nil
+
+	case "INFO":
+		if len(c.Args) == 0 {
+			return commandInfo(topLevelCommands()), nil
+		}
+		return commandInfo(c.Args), nil
+
+	case "DOCS":
+		names := c.Args
+		if len(names) == 0 {
+			names = topLevelCommands()
+		}
+		docs := respparser.Map{}
+		for _, name := range names {
+			// remark: unknown commands are omitted
+			if spec, found := commandSpecs[strings.ToUpper(name)]; found {
+				docs.Add(strings.ToLower(name), describeDocs(strings.ToUpper(name), spec))
+			}
+		}
+		return docs, nil
+
+	case "GETKEYS":
+		return commandGetKeys(c.Args)
+
+	default:
+		return respparser.SimpleError{}, errUnknownSubcommand("COMMAND", c.Subcommand)
+	}
+}
+
+// topLevelCommands returns sorted names of commands, without subcommands
+func topLevelCommands() []string {
+	names := []string{}
+	for name := range commandSpecs {
+		if !strings.Contains(name, "|") {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// subcommandsOf returns sorted full names of subcommands, e.g. CLIENT|ID
+func subcommandsOf(container string) []string {
+	names := []string{}
+	for name := range commandSpecs {
+		if strings.HasPrefix(name, container+"|") {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// commandInfo describes the commands, unknown commands are replied as nil
+func commandInfo(names []string) respparser.Array {
+	result := respparser.Array{}
+	for _, name := range names {
+		spec, found := commandSpecs[strings.ToUpper(name)]
+		if !found {
+			result.Items = append(result.Items, respparser.Array{IsNull: true})
+			continue
+		}
+		result.Items = append(result.Items, describeCommand(strings.ToUpper(name), spec))
+	}
+	return result
+}
+
+// describeCommand replies the command as COMMAND INFO does: name, arity, flags, first key, last key,
+// key step, ACL categories, tips, key specifications and subcommands
+func describeCommand(name string, spec commandSpec) respparser.Array {
+	flags := respparser.Set{}
+	for _, flag := range spec.Flags {
+		flags.Items = append(flags.Items, respparser.SimpleString{Value: flag})
+	}
+	if spec.GetKeys != nil {
+		flags.Items = append(flags.Items, respparser.SimpleString{Value: "movablekeys"})
+	}
+
+	categories := respparser.Set{}
+	// remark: containers like CLIENT have no categories, permissions are checked on their subcommands
+	if !containerCommands[name] || len(spec.Flags) > 0 || len(spec.Categories) > 0 {
+		for _, category := range spec.aclCategories() {
+			categories.Items = append(categories.Items, respparser.SimpleString{Value: "@" + category})
+		}
+	}
+
+	subcommands := respparser.Array{}
+	for _, subcommand := range subcommandsOf(name) {
+		subcommands.Items = append(subcommands.Items, describeCommand(subcommand, commandSpecs[subcommand]))
+	}
+
+	return respparser.Array{Items: []respparser.RespData{
+		respparser.BulkString{Value: strings.ToLower(name)},
+		respparser.Integer{Value: spec.Arity},
+		flags,
+		respparser.Integer{Value: spec.FirstKey},
+		respparser.Integer{Value: spec.LastKey},
+		respparser.Integer{Value: spec.KeyStep},
+		categories,
+		respparser.Array{},
+		describeKeySpecs(spec),
+		subcommands,
+	}}
+}
+
+// describeKeySpecs converts key positions to a key specification, commands with keys
+// at variable positions are reported without it
+func describeKeySpecs(spec commandSpec) respparser.Array {
+	if spec.FirstKey == 0 || spec.GetKeys != nil {
+		return respparser.Array{}
+	}
+
+	access := []string{"RO", "access"}
+	if spec.hasFlag("write") {
+		access = []string{"RW", "update"}
+	}
+	flags := respparser.Array{}
+	for _, flag := range access {
+		flags.Items = append(flags.Items, respparser.SimpleString{Value: flag})
+	}
+
+	// remark: the last key of the range is relative to the first one
+	lastKey := spec.LastKey
+	if lastKey > 0 {
+		lastKey -= spec.FirstKey
+	}
+
+	beginSearch := respparser.Map{}
+	beginSearchSpec := respparser.Map{}
+	beginSearchSpec.Add("index", respparser.Integer{Value: spec.FirstKey})
+	beginSearch.Add("type", respparser.BulkString{Value: "index"})
+	beginSearch.Add("spec", beginSearchSpec)
+
+	findKeys := respparser.Map{}
+	findKeysSpec := respparser.Map{}
+	findKeysSpec.Add("lastkey", respparser.Integer{Value: lastKey})
+	findKeysSpec.Add("keystep", respparser.Integer{Value: spec.KeyStep})
+	findKeysSpec.Add("limit", respparser.Integer{Value: 0})
+	findKeys.Add("type", respparser.BulkString{Value: "range"})
+	findKeys.Add("spec", findKeysSpec)
+
+	keySpec := respparser.Map{}
+	keySpec.Add("flags", flags)
+	keySpec.Add("begin_search", beginSearch)
+	keySpec.Add("find_keys", findKeys)
+	return respparser.Array{Items: []respparser.RespData{keySpec}}
+}
+
+// describeDocs replies the documentation of the command as COMMAND DOCS does
+func describeDocs(name string, spec commandSpec) respparser.Map {
+	docs := respparser.Map{}
+	docs.Add("summary", respparser.BulkString{Value: spec.Summary})
+	docs.Add("since", respparser.BulkString{Value: spec.Since})
+	docs.Add("group", respparser.BulkString{Value: spec.Group})
+	docs.Add("complexity", respparser.BulkString{Value: spec.Complexity})
+	if len(spec.Arguments) > 0 {
+		docs.Add("arguments", describeArgs(spec.Arguments))
+	}
+
+	if subcommands := subcommandsOf(name); len(subcommands) > 0 {
+		subcommandDocs := respparser.Map{}
+		for _, subcommand := range subcommands {
+			subcommandDocs.Add(strings.ToLower(subcommand), describeDocs(subcommand, commandSpecs[subcommand]))
+		}
+		docs.Add("subcommands", subcommandDocs)
+	}
+	return docs
+}
+
+func describeArgs(args []commandArg) respparser.Array {
+	result := respparser.Array{}
+	for _, arg := range args {
+		description := respparser.Map{}
+		description.Add("name", respparser.BulkString{Value: arg.Name})
+		description.Add("type", respparser.BulkString{Value: arg.Type})
+		if arg.Token != "" {
+			description.Add("token", respparser.BulkString{Value: arg.Token})
+		}
+
+		flags := respparser.Array{}
+		if arg.Optional {
+			flags.Items = append(flags.Items, respparser.SimpleString{Value: "optional"})
+		}
+		if arg.Multiple {
+			flags.Items = append(flags.Items, respparser.SimpleString{Value: "multiple"})
+		}
+		if len(flags.Items) > 0 {
+			description.Add("flags", flags)
+		}
+
+		if len(arg.Arguments) > 0 {
+			description.Add("arguments", describeArgs(arg.Arguments))
+		}
+		result.Items = append(result.Items, description)
+	}
+	return result
+}
+
+// commandGetKeys returns keys of the command given in args, e.g. COMMAND GETKEYS SET key value
+func commandGetKeys(args []string) (respparser.RespData, error) {
+	command := &Command{CommandType: strings.ToUpper(args[0]), CommandValues: args[1:]}
+	spec, found := commandSpecs[command.CommandType]
+	if !found || spec.Parse == nil {
+		return respparser.SimpleError{}, Errorf(CodeErr, "Invalid command specified")
+	}
+	if checkArity(command, spec) != nil {
+		return respparser.SimpleError{}, Errorf(CodeErr, "Invalid number of arguments specified for command")
+	}
+
+	spec, _ = lookupCommandSpec(command)
+	keys := commandKeys(command, spec)
+	if len(keys) == 0 {
+		return respparser.SimpleError{}, Errorf(CodeErr, "The command has no key arguments")
+	}
+
+	result := respparser.Array{}
+	for _, key := range keys {
+		result.Items = append(result.Items, respparser.BulkString{Value: key})
+	}
+	return result, nil
+}
+
+func parseCommandCommand(command *Command) (CommandCommand, error) {
+	if len(command.CommandValues) == 0 {
+		return CommandCommand{}, nil
+	}
+
+	commandCommand := CommandCommand{
+		Subcommand: strings.ToUpper(command.CommandValues[0]),
+		Args:       command.CommandValues[1:],
+	}
+	return commandCommand, nil
+}
@@ -0,0 +1,163 @@
+package command
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+func TestArity(t *testing.T) {
+	var tests = []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"PING", "a", "b"}, want: "ERR wrong number of arguments for 'ping' command"},
+		{args: []string{"ECHO"}, want: "ERR wrong number of arguments for 'echo' command"},
+		{args: []string{"ECHO", "a", "b"}, want: "ERR wrong number of arguments for 'echo' command"},
+		{args: []string{"SET", "key"}, want: "ERR wrong number of arguments for 'set' command"},
+		{args: []string{"XADD", "stream", "*", "field"}, want: "ERR wrong number of arguments for 'xadd' command"},
+		{args: []string{"LRANGE", "list", "0"}, want: "ERR wrong number of arguments for 'lrange' command"},
+		{args: []string{"CLIENT", "SETNAME"}, want: "ERR wrong number of arguments for 'client|setname' command"},
+		{args: []string{"CLIENT", "ID", "extra"}, want: "ERR wrong number of arguments for 'client|id' command"},
+		{args: []string{"CONFIG", "GET"}, want: "ERR wrong number of arguments for 'config|get' command"},
+		{args: []string{"ACL", "GETUSER"}, want: "ERR wrong number of arguments for 'acl|getuser' command"},
+		{args: []string{"COMMAND", "GETKEYS"}, want: "ERR wrong number of arguments for 'command|getkeys' command"},
+		{args: []string{"COMMAND", "NOPE"}, want: "ERR unknown subcommand 'NOPE'. Try COMMAND HELP."},
+		{args: []string{"PING", "hello"}, want: "hello"},
+		{args: []string{"ECHO", "hello"}, want: "hello"},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			if reply := runTestCommand(tt.args...); reply.String() != tt.want {
+				t.Errorf("ERROR got %q, want %q", reply.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandGetKeys(t *testing.T) {
+	var tests = []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"GET", "key"}, want: "[key]"},
+		{args: []string{"set", "key", "value", "PX", "100"}, want: "[key]"},
+		{args: []string{"XREAD", "COUNT", "1", "STREAMS", "a", "b", "0", "0"}, want: "[a b]"},
+		{args: []string{"PING"}, want: "ERR The command has no key arguments"},
+		{args: []string{"GET"}, want: "ERR Invalid number of arguments specified for command"},
+		{args: []string{"NOPE", "key"}, want: "ERR Invalid command specified"},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			reply := runTestCommand(append([]string{"COMMAND", "GETKEYS"}, tt.args...)...)
+			got := reply.String()
+			if array, ok := reply.(respparser.Array); ok {
+				keys := []string{}
+				for _, item := range array.Items {
+					keys = append(keys, item.String())
+				}
+				got = "[" + strings.Join(keys, " ") + "]"
+			}
+			if got != tt.want {
+				t.Errorf("ERROR got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandInfo(t *testing.T) {
+	count, ok := runTestCommand("COMMAND", "COUNT").(respparser.Integer)
+	if !ok || count.Value != len(topLevelCommands()) || count.Value == 0 {
+		t.Fatalf("ERROR got %v, want number of commands", count)
+	}
+	if all := runTestCommand("COMMAND").(respparser.Array); len(all.Items) != count.Value {
+		t.Errorf("ERROR got %d commands, want %d", len(all.Items), count.Value)
+	}
+
+	info := runTestCommand("COMMAND", "INFO", "get", "nope", "client").(respparser.Array)
+	if len(info.Items) != 3 {
+		t.Fatalf("ERROR got %d items, want 3", len(info.Items))
+	}
+
+	get := info.Items[0].(respparser.Array).Items
+	wantGet := []string{"get", "2", "", "1", "1", "1"}
+	for n, want := range wantGet {
+		if want != "" && get[n].String() != want {
+			t.Errorf("ERROR got %q at %d, want %q", get[n].String(), n, want)
+		}
+	}
+	if flags := get[2].(respparser.Set); len(flags.Items) != 2 || flags.Items[0].String() != "readonly" || flags.Items[1].String() != "fast" {
+		t.Errorf("ERROR got flags %v, want readonly and fast", flags)
+	}
+	categories := []string{}
+	for _, category := range get[6].(respparser.Set).Items {
+		categories = append(categories, category.String())
+	}
+	if strings.Join(categories, " ") != "@read @fast @string" {
+		t.Errorf("ERROR got categories %v, want @read @fast @string", categories)
+	}
+
+	if nope := info.Items[1].(respparser.Array); !nope.IsNull {
+		t.Errorf("ERROR got %v, want nil for unknown command", nope)
+	}
+
+	client := info.Items[2].(respparser.Array).Items
+	subcommands := client[9].(respparser.Array).Items
+	if len(subcommands) != len(subcommandsOf("CLIENT")) {
+		t.Fatalf("ERROR got %d subcommands, want %d", len(subcommands), len(subcommandsOf("CLIENT")))
+	}
+	for _, subcommand := range subcommands {
+		items := subcommand.(respparser.Array).Items
+		if items[0].String() == "client|kill" && items[1].String() != strconv.Itoa(-3) {
+			t.Errorf("ERROR got arity %s of client|kill, want -3", items[1].String())
+		}
+	}
+}
+
+func TestCommandDocs(t *testing.T) {
+	docs := runTestCommand("COMMAND", "DOCS", "set", "nope", "config").(respparser.Map)
+	if len(docs.Items) != 2 || docs.Items[0].Key.String() != "set" || docs.Items[1].Key.String() != "config" {
+		t.Fatalf("ERROR got %v, want docs of set and config", docs)
+	}
+
+	set := docs.Items[0].Value.(respparser.Map)
+	fields := map[string]respparser.RespData{}
+	for _, item := range set.Items {
+		fields[item.Key.String()] = item.Value
+	}
+	if fields["group"].String() != "string" || fields["summary"].String() == "" {
+		t.Errorf("ERROR got %v, want documentation of string group", set)
+	}
+	if args := fields["arguments"].(respparser.Array); len(args.Items) != 5 {
+		t.Errorf("ERROR got %v, want 5 arguments", args)
+	}
+
+	config := docs.Items[1].Value.(respparser.Map)
+	if subcommands := config.Items[len(config.Items)-1]; subcommands.Key.String() != "subcommands" {
+		t.Errorf("ERROR got %v, want subcommands", subcommands.Key)
+	}
+}
+
+// TestSpecsConsistency guards the registry against typos, commands and subcommands must be dispatchable
+func TestSpecsConsistency(t *testing.T) {
+	for name, spec := range commandSpecs {
+		container, _, isSubcommand := strings.Cut(name, "|")
+		if isSubcommand {
+			if spec.Parse != nil || commandSpecs[container].Parse == nil {
+				t.Errorf("ERROR %s should be parsed by its container command", name)
+			}
+			if spec.Arity < 2 && spec.Arity > -2 {
+				t.Errorf("ERROR arity %d of %s should count the subcommand name", spec.Arity, name)
+			}
+		} else if spec.Parse == nil {
+			t.Errorf("ERROR %s has no parser", name)
+		}
+		if spec.Arity == 0 || spec.Summary == "" || spec.Group == "" {
+			t.Errorf("ERROR %s lacks arity or documentation", name)
+		}
+	}
+}
@@ -1,39 +1,36 @@
 package command
 
 import (
-	"bufio"
-	"errors"
 	"fmt"
 	"math"
 	"strconv"
 	"strings"
+	"time"
 
-	"github.com/codecrafters-io/redis-starter-go/app/internal/respparser"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/store"
 	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
 )
 
-func parsePingCommand(_ *Command) (PingCommand, error) {
-	return PingCommand{}, nil
+func parsePingCommand(command *Command) (PingCommand, error) {
+	switch len(command.CommandValues) {
+	case 0:
+		return PingCommand{}, nil
+	case 1:
+		return PingCommand{Message: command.CommandValues[0], HasMessage: true}, nil
+	default:
+		return PingCommand{}, errWrongArgs("ping")
+	}
 }
 
 func parseEchoCommand(command *Command) (EchoCommand, error) {
-	if command.CommandType != "ECHO" {
-		return EchoCommand{}, errors.New("Not an ECHO")
-	}
-
 	echoCommand := EchoCommand{
-		Message: strings.Join(command.CommandValues, " "),
+		Message: command.CommandValues[0],
 	}
 	return echoCommand, nil
 }
 
 func parseGetCommand(command *Command) (GetCommand, error) {
-	if command.CommandType != "GET" {
-		return GetCommand{}, errors.New("Not a GET")
-	} else if len(command.CommandValues) != 1 {
-		return GetCommand{}, errors.New("GET must contains only one value (key)")
-	}
-
 	getCommand := GetCommand{
 		Key: command.CommandValues[0],
 	}
@@ -41,73 +38,77 @@ func parseGetCommand(command *Command) (GetCommand, error) {
 }
 
 func parseSetCommand(command *Command) (SetCommand, error) {
-	if len(command.CommandValues) < 2 {
-		return SetCommand{}, errors.New("(SET cmd) too few arguments. At least key and value expected")
-	}
-
+	// SET key value [NX | XX] [GET] [EX seconds | PX milliseconds | EXAT unix-time-seconds | PXAT unix-time-milliseconds | KEEPTTL]
 	setCommand := SetCommand{
-		RecordExpirationMillis: math.MaxInt32,
+		Key:   command.CommandValues[0],
+		Value: command.CommandValues[1],
 	}
 
-	for n, arg := range command.CommandValues {
-		if n == 0 {
-			setCommand.Key = arg
-			continue
-		} else if n == 1 {
-			setCommand.Value = arg
-			continue
-		} else {
-			switch arg {
-			case "PX":
-				// millisecond expiry
-				nextElem := n + 1
-				if nextElem >= len(command.CommandValues) {
-					errMsg := "(SET cmd) PX key expects value, but it seems to be missing!"
-					utils.Log(errMsg)
-					return SetCommand{}, errors.New(errMsg)
-				}
+	args := command.CommandValues[2:]
+	hasExpiry := false
+	for n := 0; n < len(args); n++ {
+		option := strings.ToUpper(args[n])
+		switch option {
+		case "NX", "XX":
+			condition := store.SetIfNotExists
+			if option == "XX" {
+				condition = store.SetIfExists
+			}
+			if setCommand.Condition != store.SetAlways && setCommand.Condition != condition {
+				utils.Log("(SET cmd) NX and XX options can't be combined")
+				return SetCommand{}, errSyntax
+			}
+			setCommand.Condition = condition
 
-				pxValue := command.CommandValues[nextElem]
-				pxValueInt, err := strconv.Atoi(pxValue)
-				if err != nil {
-					errMsg := fmt.Sprintf("(SET cmd) PX value is expected to be int, but got %s", pxValue)
-					utils.Log(errMsg)
-					return SetCommand{}, errors.New(errMsg)
-				}
-				setCommand.RecordExpirationMillis = pxValueInt
-
-			case "EX":
-				// second expiry
-				nextElem := n + 1
-				if nextElem >= len(command.CommandValues) {
-					errMsg := "(SET cmd) EX key expects value, but it seems to be missing!"
-					utils.Log(errMsg)
-					return SetCommand{}, errors.New(errMsg)
-				}
+		case "GET":
+			setCommand.Get = true
+
+		case "KEEPTTL":
+			if hasExpiry {
+				return SetCommand{}, errSyntax
+			}
+			hasExpiry = true
+			setCommand.KeepTtl = true
+
+		case "EX", "PX", "EXAT", "PXAT":
+			if hasExpiry || n+1 >= len(args) {
+				utils.Log(fmt.Sprintf("(SET cmd) %s option expects single value", option))
+				return SetCommand{}, errSyntax
+			}
+			hasExpiry = true
+			n++
 
-				exValue := command.CommandValues[nextElem]
-				exValueInt, err := strconv.Atoi(exValue)
-				if err != nil {
-					errMsg := fmt.Sprintf("(SET cmd) EX value is expected to be int, but got %s", exValue)
-					utils.Log(errMsg)
-					return SetCommand{}, errors.New(errMsg)
+			value, err := strconv.ParseInt(args[n], 10, 64)
+			if err != nil {
+				utils.Log(fmt.Sprintf("(SET cmd) %s value is expected to be int, but got %s", option, args[n]))
+				return SetCommand{}, errNotInteger
+			}
+			// remark: seconds are converted to milliseconds, the expiry time must not overflow
+			if value <= 0 || ((option == "EX" || option == "EXAT") && value > math.MaxInt64/1000) {
+				return SetCommand{}, errInvalidExpireTime("set")
+			}
+			if option == "EX" || option == "EXAT" {
+				value *= 1000
+			}
+
+			if option == "EX" || option == "PX" {
+				if value > math.MaxInt64-time.Now().UnixMilli() {
+					return SetCommand{}, errInvalidExpireTime("set")
 				}
-				setCommand.RecordExpirationMillis = exValueInt * 1000 // from sec to millis
-			default:
-				continue
+				setCommand.TtlMillis = value
+			} else {
+				setCommand.ExpireAt = time.UnixMilli(value)
 			}
+
+		default:
+			utils.Log(fmt.Sprintf("(SET cmd) Unknown option %s", args[n]))
+			return SetCommand{}, errSyntax
 		}
 	}
 	return setCommand, nil
 }
 
 func parseTypeCommand(command *Command) (TypeCommand, error) {
-	if command.CommandType != "TYPE" {
-		return TypeCommand{}, errors.New("Not a TYPE")
-	} else if len(command.CommandValues) != 1 {
-		return TypeCommand{}, errors.New("(TYPE cmd) must contains only one value (key)")
-	}
-
 	typeCommand := TypeCommand{
 		Key: command.CommandValues[0],
 	}
@@ -115,10 +116,6 @@ func parseTypeCommand(command *Command) (TypeCommand, error) {
 }
 
 func parseXAddCommand(command *Command) (XAddCommand, error) {
-	if len(command.CommandValues) < 4 {
-		return XAddCommand{}, errors.New("(XADD cmd) Too few arguments. At least stream-key, entry-id, akey and value expected")
-	}
-
 	xaddCommand := XAddCommand{}
 
 	keys := []string{}
@@ -149,7 +146,7 @@ func parseXAddCommand(command *Command) (XAddCommand, error) {
 	}
 
 	if len(keys) != len(values) {
-		return XAddCommand{}, errors.New("(XADD cmd) number of keys must be same as number of values!")
+		return XAddCommand{}, errWrongArgs("xadd")
 	}
 
 	keyValues := make(map[string]string, len(keys))
@@ -161,6 +158,8 @@ func parseXAddCommand(command *Command) (XAddCommand, error) {
 	return xaddCommand, nil
 }
 
+var errInvalidStreamId = Errorf(CodeErr, "Invalid stream ID specified as stream command argument")
+
 func parseXCommandsEntryId(entryIdString string) (EntryId, error) {
 	entryId := EntryId{
 		AutoGenerated: NoneAutoGeneratedEntryId,
@@ -175,9 +174,8 @@ func parseXCommandsEntryId(entryIdString string) (EntryId, error) {
 	// TODO split check
 	if len(split) < 1 || len(split) > 2 {
 		// explicit entry id
-		errMsg := fmt.Sprintf("(parseXCommandsEntryId) EntryId required format '<millisecondsTime>' or '<millisecondsTime>-<sequenceNumber>', but got: %s", entryIdString)
-		utils.Log(errMsg)
-		return entryId, errors.New(errMsg)
+		utils.Log(fmt.Sprintf("(parseXCommandsEntryId) EntryId required format '<millisecondsTime>' or '<millisecondsTime>-<sequenceNumber>', but got: %s", entryIdString))
+		return entryId, errInvalidStreamId
 	}
 
 	var err error
@@ -193,9 +191,8 @@ func parseXCommandsEntryId(entryIdString string) (EntryId, error) {
 	} else {
 		millisecondsTime, err = strconv.ParseInt(entryIdMillisecondsTime, 10, 64)
 		if err != nil {
-			errMsg := fmt.Sprintf("(parseXCommandsEntryId) EntryId millisecondsTime must be an integer, but got: %s", split[0])
-			utils.Log(errMsg)
-			return entryId, errors.New(errMsg)
+			utils.Log(fmt.Sprintf("(parseXCommandsEntryId) EntryId millisecondsTime must be an integer, but got: %s", split[0]))
+			return entryId, errInvalidStreamId
 		}
 	}
 
@@ -214,9 +211,8 @@ func parseXCommandsEntryId(entryIdString string) (EntryId, error) {
 		} else {
 			sequenceNumber, err = strconv.Atoi(entryIdSequenceNumber)
 			if err != nil {
-				errMsg := fmt.Sprintf("(XADD cmd) EntryId sequenceNumber must be an integer, but got: %s", split[1])
-				utils.Log(errMsg)
-				return entryId, errors.New(errMsg)
+				utils.Log(fmt.Sprintf("(XADD cmd) EntryId sequenceNumber must be an integer, but got: %s", split[1]))
+				return entryId, errInvalidStreamId
 			}
 		}
 		entryId.MillisecondsTime = millisecondsTime
@@ -228,9 +224,6 @@ func parseXCommandsEntryId(entryIdString string) (EntryId, error) {
 func parseXRangeCommand(command *Command) (XRangeCommand, error) {
 	// XRANGE key start end [COUNT count]
 	xRangeCommand := XRangeCommand{}
-	if len(command.CommandValues) < 3 {
-		return xRangeCommand, errors.New("(XADD cmd) Too few arguments. At least stream-key, start nillis and end millis expected")
-	}
 
 	xRangeCommand.StreamKey = command.CommandValues[0]
 
@@ -251,9 +244,8 @@ func parseXRangeCommand(command *Command) (XRangeCommand, error) {
 		//startMillis, startSequenceNumber, entryIdError = parseXCommandsEntryId(startEntryId)
 		startEntryId, entryIdError = parseXCommandsEntryId(startEntryIdString)
 		if entryIdError != nil {
-			err := fmt.Errorf("ERROR (parseXrangeCommand) Can't decode start entry id %s: %e", startEntryIdString, entryIdError)
-			utils.Log(err.Error())
-			return xRangeCommand, err
+			utils.Log(fmt.Sprintf("ERROR (parseXrangeCommand) Can't decode start entry id %s", startEntryIdString))
+			return xRangeCommand, entryIdError
 		}
 		// use default
 		if startEntryId.SequenceNumber < 0 {
@@ -271,9 +263,8 @@ func parseXRangeCommand(command *Command) (XRangeCommand, error) {
 	} else {
 		endEntryId, entryIdError = parseXCommandsEntryId(endEntryIdString)
 		if entryIdError != nil {
-			err := fmt.Errorf("ERROR (parseXrangeCommand) Can't decode end entry id %s: %e", endEntryIdString, entryIdError)
-			utils.Log(err.Error())
-			return xRangeCommand, err
+			utils.Log(fmt.Sprintf("ERROR (parseXrangeCommand) Can't decode end entry id %s", endEntryIdString))
+			return xRangeCommand, entryIdError
 		}
 		// use default
 		if endEntryId.SequenceNumber < 0 {
@@ -311,41 +302,70 @@ func arrayToCommand(array respparser.Array) Command {
 	}
 }
 
+// GetCommandHandler looks the command up in the registry, checks its arity and parses its arguments
 func GetCommandHandler(command *Command) (CommandHandler[string], error) {
-	switch command.CommandType {
-	case "PING":
-		return parsePingCommand(command)
-	case "ECHO":
-		return parseEchoCommand(command)
-	case "GET":
-		return parseGetCommand(command)
-	case "SET":
-		return parseSetCommand(command)
-	case "TYPE":
-		return parseTypeCommand(command)
-	case "XADD":
-		return parseXAddCommand(command)
-	case "XRANGE":
-		return parseXRangeCommand(command)
-	case "XREAD":
-		return parseXReadCommand(command)
-	case "RPUSH":
-		return parseRPushCommand(command)
-	case "LRANGE":
-		return parseLRangeCommand(command)
-	default:
-		return PingCommand{}, errors.ErrUnsupported
+	spec, found := commandSpecs[command.CommandType]
+	if !found || spec.Parse == nil {
+		return PingCommand{}, errUnknownCommand(command)
 	}
+	if err := checkArity(command, spec); err != nil {
+		return PingCommand{}, err
+	}
+	return spec.Parse(command)
 }
 
-func ParseCommand(r *bufio.Reader) (*Command, error) {
-	// A client sends the Redis server an array consisting of only bulk strings.
-	// command example *2\r\n$4\r\nLLEN\r\n$6\r\nmylist\r\n
-	arrayElements, err := respparser.DeserializeArray(r)
-	if err != nil {
-		return nil, err
-	}
+// ParseCommand reads the next command. Commands are either RESP arrays of bulk strings
+// or inline commands, i.e. plain text lines as typed in telnet or netcat.
+// Errors are the errors of respparser.Decoder, io.EOF means the client disconnected.
+func ParseCommand(d *respparser.Decoder) (*Command, error) {
+	for {
+		dataType, err := d.PeekType()
+		if err != nil {
+			return nil, err
+		}
+
+		if dataType == respparser.TypeArray {
+			// A client sends the Redis server an array consisting of only bulk strings.
+			// command example *2\r\n$4\r\nLLEN\r\n$6\r\nmylist\r\n
+			arrayElements, err := d.DecodeArray()
+			if err != nil {
+				return nil, err
+			}
+			// remark: empty and null arrays are ignored, the same as Redis does
+			if len(arrayElements.Items) == 0 {
+				continue
+			}
+			for _, item := range arrayElements.Items {
+				bulk, ok := item.(respparser.BulkString)
+				if !ok {
+					return nil, &respparser.ProtocolError{Reason: fmt.Sprintf("expected '$', got '%c'", item.Type())}
+				} else if bulk.IsNull {
+					return nil, &respparser.ProtocolError{Reason: "invalid bulk length"}
+				}
+			}
 
-	command := arrayToCommand(arrayElements)
-	return &command, nil
+			command := arrayToCommand(arrayElements)
+			return &command, nil
+		}
+
+		line, err := d.ReadLine("too big inline request")
+		if err != nil {
+			return nil, err
+		}
+		utils.Log(fmt.Sprintf("(ParseCommand) Inline command received: %q", line))
+
+		args, err := utils.SplitArgs(line)
+		if err != nil {
+			return nil, &respparser.ProtocolError{Reason: "unbalanced quotes in request"}
+		}
+		// remark: empty lines are ignored, the same as Redis does
+		if len(args) == 0 {
+			continue
+		}
+
+		return &Command{
+			CommandType:   strings.ToUpper(args[0]),
+			CommandValues: args[1:],
+		}, nil
+	}
 }
@@ -1,12 +1,15 @@
 package command
 
 import (
+	"errors"
 	"fmt"
 	"maps"
 	"math"
+	"strings"
 	"testing"
 
 	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
 )
 
 func IsEqualSlice[T comparable](a []T, b []T) bool {
@@ -343,3 +346,68 @@ func TestParseXReadCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestParseCommand(t *testing.T) {
+	var tests = []struct {
+		name         string
+		input        string
+		want         []Command
+		wantProtoErr bool
+	}{
+		{
+			name:  "RESP array command should be parsed",
+			input: "*2\r\n$4\r\necho\r\n$2\r\nhi\r\n",
+			want:  []Command{{CommandType: "ECHO", CommandValues: []string{"hi"}}},
+		},
+		{
+			name:  "Inline commands should be parsed",
+			input: "ping\r\nSET a   \"b c\"\n",
+			want: []Command{
+				{CommandType: "PING", CommandValues: []string{}},
+				{CommandType: "SET", CommandValues: []string{"a", "b c"}},
+			},
+		},
+		{
+			name:  "Empty inline lines should be skipped",
+			input: "\r\n  \r\nGET 'it\\'s'\r\n",
+			want:  []Command{{CommandType: "GET", CommandValues: []string{"it's"}}},
+		},
+		{
+			name:  "Inline and RESP commands can be mixed",
+			input: "PING\r\n*1\r\n$4\r\nPING\r\n",
+			want: []Command{
+				{CommandType: "PING", CommandValues: []string{}},
+				{CommandType: "PING"},
+			},
+		},
+		{
+			name:         "Unbalanced quotes should be a protocol error",
+			input:        "SET a \"b\r\n",
+			wantProtoErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := respparser.NewDecoder(strings.NewReader(tt.input))
+			if tt.wantProtoErr {
+				_, err := ParseCommand(d)
+				var protocolErr *respparser.ProtocolError
+				if !errors.As(err, &protocolErr) {
+					t.Errorf("ERROR protocol error expected, but got: %v", err)
+				}
+				return
+			}
+
+			for _, want := range tt.want {
+				ans, err := ParseCommand(d)
+				if err != nil {
+					t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+				}
+				if ans.CommandType != want.CommandType || !IsEqualSlice(ans.CommandValues, want.CommandValues) {
+					t.Errorf("ERROR got %v, want %v", ans, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/config"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/stats"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+type ConfigCommand struct {
+	Subcommand string
+	Args       []string
+}
+
+func (c ConfigCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	utils.Log(fmt.Sprintf("(ConfigCommand) Processing CONFIG %s", c.Subcommand))
+
+	switch c.Subcommand {
+	case "GET":
+		result := respparser.Map{}
+		seen := map[string]bool{}
+		for _, pattern := range c.Args {
+			for _, nv := range config.Match(pattern) {
+				if seen[nv[0]] {
+					continue
+				}
+				seen[nv[0]] = true
+				result.Add(nv[0], respparser.BulkString{Value: nv[1]})
+			}
+		}
+		return result, nil
+
+	case "SET":
+		nameValues := make([][2]string, 0, len(c.Args)/2)
+		for i := 0; i < len(c.Args); i += 2 {
+			nameValues = append(nameValues, [2]string{c.Args[i], c.Args[i+1]})
+		}
+		if err := config.Set(nameValues); err != nil {
+			return respparser.SimpleError{}, toError(err)
+		}
+		return okResponse, nil
+
+	case "RESETSTAT":
+		stats.Reset()
+		return okResponse, nil
+
+	case "REWRITE":
+		if err := config.Rewrite(); err != nil {
+			utils.LogWarning(fmt.Sprintf("(ConfigCommand) CONFIG REWRITE failed: %s", err.Error()))
+			if errors.Is(err, config.ErrNoConfigFile) {
+				return respparser.SimpleError{}, toError(err)
+			}
+			return respparser.SimpleError{}, Errorf(CodeErr, "Rewriting config file: %s", err.Error())
+		}
+		utils.LogNotice("(ConfigCommand) CONFIG REWRITE executed with success")
+		return okResponse, nil
+
+	default:
+		return respparser.SimpleError{}, errUnknownSubcommand("CONFIG", c.Subcommand)
+	}
+}
+
+func parseConfigCommand(command *Command) (ConfigCommand, error) {
+	configCommand := ConfigCommand{
+		Subcommand: strings.ToUpper(command.CommandValues[0]),
+		Args:       command.CommandValues[1:],
+	}
+
+	// remark: the registry checks the minimal number of arguments, parameters and values must come in pairs
+	if configCommand.Subcommand == "SET" && len(configCommand.Args)%2 != 0 {
+		return ConfigCommand{}, errWrongArgs("config|" + configCommand.Subcommand)
+	}
+	return configCommand, nil
+}
@@ -0,0 +1,89 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/store"
+)
+
+// Error codes, clients read the first word of an error reply as its code
+const (
+	CodeErr       = "ERR"
+	CodeWrongType = "WRONGTYPE"
+	CodeNoAuth    = "NOAUTH"
+	CodeNoPerm    = "NOPERM"
+	CodeWrongPass = "WRONGPASS"
+	CodeNoProto   = "NOPROTO"
+	CodeBusyGroup = "BUSYGROUP"
+	CodeNoScript  = "NOSCRIPT"
+	CodeExecAbort = "EXECABORT"
+	CodeLoading   = "LOADING"
+	CodeUnblocked = "UNBLOCKED"
+)
+
+// Error is an error replied to the client, the reply is the code followed by the message
+type Error struct {
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Code + " " + e.Message
+}
+
+// Errorf creates an error with the code and the formatted message
+func Errorf(code string, format string, args ...any) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+var (
+	errSyntax     = Errorf(CodeErr, "syntax error")
+	errNotInteger = Errorf(CodeErr, "value is not an integer or out of range")
+	errNoAuth     = Errorf(CodeNoAuth, "Authentication required.")
+	errWrongType  = Errorf(CodeWrongType, "Operation against a key holding the wrong kind of value")
+)
+
+// maxErrorArgsLen limits the arguments quoted in the unknown command error, the same as Redis does
+const maxErrorArgsLen = 128
+
+// errUnknownCommand quotes the command name and the beginning of its arguments
+func errUnknownCommand(command *Command) *Error {
+	var args strings.Builder
+	for _, arg := range command.CommandValues {
+		remaining := maxErrorArgsLen - args.Len()
+		if remaining <= 0 {
+			break
+		}
+		fmt.Fprintf(&args, "'%.*s' ", remaining, arg)
+	}
+	return Errorf(CodeErr, "unknown command '%.128s', with args beginning with: %s", strings.ToLower(command.CommandType), args.String())
+}
+
+// errWrongArgs is returned when the number of arguments doesn't match the command, name is e.g. get or client|kill
+func errWrongArgs(name string) *Error {
+	return Errorf(CodeErr, "wrong number of arguments for '%s' command", strings.ToLower(name))
+}
+
+// errInvalidExpireTime is returned for expiry times out of range, name is e.g. set or expire
+func errInvalidExpireTime(name string) *Error {
+	return Errorf(CodeErr, "invalid expire time in '%s' command", strings.ToLower(name))
+}
+
+// errUnknownSubcommand refers the client to the help of the container command
+func errUnknownSubcommand(container string, subcommand string) *Error {
+	return Errorf(CodeErr, "unknown subcommand '%.128s'. Try %s HELP.", subcommand, strings.ToUpper(container))
+}
+
+// toError converts errors of other packages to an error reply with the generic ERR code
+func toError(err error) *Error {
+	var replyErr *Error
+	if errors.As(err, &replyErr) {
+		return replyErr
+	}
+	if errors.Is(err, store.ErrWrongType) {
+		return errWrongType
+	}
+	return &Error{Code: CodeErr, Message: err.Error()}
+}
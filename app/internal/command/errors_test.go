@@ -0,0 +1,119 @@
+package command
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/store"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+func TestErrorReplies(t *testing.T) {
+	var tests = []struct {
+		name  string
+		input Command
+		want  string
+	}{
+		{
+			name:  "Unknown command should quote its arguments",
+			input: Command{CommandType: "FOO", CommandValues: []string{"a", "b c"}},
+			want:  "ERR unknown command 'foo', with args beginning with: 'a' 'b c' ",
+		},
+		{
+			name:  "Unknown command without arguments",
+			input: Command{CommandType: "FOO"},
+			want:  "ERR unknown command 'foo', with args beginning with: ",
+		},
+		{
+			name:  "Quoted arguments of unknown command should be limited",
+			input: Command{CommandType: "FOO", CommandValues: []string{strings.Repeat("x", 200), "y"}},
+			want:  "ERR unknown command 'foo', with args beginning with: '" + strings.Repeat("x", 128) + "' ",
+		},
+		{
+			name:  "GET with wrong number of arguments",
+			input: Command{CommandType: "GET", CommandValues: []string{"a", "b"}},
+			want:  "ERR wrong number of arguments for 'get' command",
+		},
+		{
+			name:  "RPUSH without values",
+			input: Command{CommandType: "RPUSH", CommandValues: []string{"list"}},
+			want:  "ERR wrong number of arguments for 'rpush' command",
+		},
+		{
+			name:  "SET with invalid expiry",
+			input: Command{CommandType: "SET", CommandValues: []string{"a", "b", "PX", "soon"}},
+			want:  "ERR value is not an integer or out of range",
+		},
+		{
+			name:  "Unknown subcommand",
+			input: Command{CommandType: "CONFIG", CommandValues: []string{"nope"}},
+			want:  "ERR unknown subcommand 'NOPE'. Try CONFIG HELP.",
+		},
+		{
+			name:  "Container command without subcommand",
+			input: Command{CommandType: "CLIENT"},
+			want:  "ERR wrong number of arguments for 'client' command",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, err := GetCommandHandler(&tt.input)
+			if err == nil {
+				_, err = handler.Process(&CommandContext{})
+			}
+
+			var replyErr *Error
+			if !errors.As(err, &replyErr) {
+				t.Fatalf("ERROR got %v, want reply error", err)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("ERROR got %q, want %q", err.Error(), tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorResponseCode(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    error
+		wantCode string
+		want     string
+	}{
+		{
+			name:     "Reply error keeps its code",
+			input:    Errorf(CodeWrongType, "Operation against a key holding the wrong kind of value"),
+			wantCode: "WRONGTYPE",
+			want:     "WRONGTYPE Operation against a key holding the wrong kind of value",
+		},
+		{
+			name:     "Store type mismatch gets its code",
+			input:    store.ErrWrongType,
+			wantCode: "WRONGTYPE",
+			want:     "WRONGTYPE Operation against a key holding the wrong kind of value",
+		},
+		{
+			name:     "Error without code gets generic code",
+			input:    errors.New("something failed"),
+			wantCode: "ERR",
+			want:     "ERR something failed",
+		},
+		{
+			name:     "Protocol error gets generic code",
+			input:    &respparser.ProtocolError{Reason: "invalid bulk length"},
+			wantCode: "ERR",
+			want:     "ERR Protocol error: invalid bulk length",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reply := ErrorResponse(tt.input).Value.(respparser.SimpleError)
+			if reply.Code() != tt.wantCode || reply.Value != tt.want {
+				t.Errorf("ERROR got %q (%s), want %q (%s)", reply.Value, reply.Code(), tt.want, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/acl"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/stats"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+// IsWriteCommand reports whether the command modifies the keyspace, such commands are held back by CLIENT PAUSE WRITE
+func IsWriteCommand(command *Command) bool {
+	spec, found := lookupCommandSpec(command)
+	return found && spec.hasFlag("write")
+}
+
+// IsBlockingCommand reports whether the command may wait for other clients, e.g. XREAD BLOCK
+func IsBlockingCommand(command *Command) bool {
+	spec, found := lookupCommandSpec(command)
+	return found && spec.hasFlag("blocking")
+}
+
+// FullName returns the lower cased command name as reported by CLIENT LIST
+func FullName(command *Command) string {
+	name := strings.ToLower(command.CommandType)
+	if containerCommands[command.CommandType] && len(command.CommandValues) > 0 {
+		name += "|" + strings.ToLower(command.CommandValues[0])
+	}
+	return name
+}
+
+// checkPermissions verifies the client is authenticated and its user may run the command
+func checkPermissions(command *Command, cmdCtx *CommandContext) error {
+	spec, _ := lookupCommandSpec(command)
+	// remark: commands like AUTH are allowed to every user, authenticated or not
+	if spec.hasFlag("no_auth") {
+		return nil
+	}
+	if !cmdCtx.Client.Authenticated() {
+		return errNoAuth
+	}
+
+	user := cmdCtx.Client.User()
+	err := acl.Check(user, FullName(command), spec.aclCategories(), commandKeys(command, spec))
+	var denied *acl.DeniedError
+	if errors.As(err, &denied) {
+		acl.AddLogEntry(denied.Reason, denied.Object, user, cmdCtx.Client.Info())
+		return Errorf(CodeNoPerm, "%s", denied.Error())
+	}
+	return err
+}
+
+// Execute runs the command on behalf of the client in cmdCtx
+func Execute(command *Command, cmdCtx *CommandContext) CommandResponse {
+	utils.Log(fmt.Sprintf("(Request handler) Command: %s", command))
+	cmdCtx.Client.Touch(FullName(command))
+
+	commandHandler, err := GetCommandHandler(command)
+	if err == nil {
+		err = checkPermissions(command, cmdCtx)
+	}
+	if err == nil && cmdCtx.inSubscriberMode() && !subscriberModeCommands[command.CommandType] {
+		err = Errorf(CodeErr, "Can't execute '%s': only (P|S)SUBSCRIBE / (P|S)UNSUBSCRIBE / PING / QUIT / RESET are allowed in this context", FullName(command))
+	}
+
+	if cmdCtx.transaction != nil && (err != nil || !transactionCommands[command.CommandType]) {
+		return queueCommand(command, commandHandler, err, cmdCtx)
+	}
+	if err != nil {
+		return ErrorResponse(err)
+	}
+
+	stats.TotalCommandsProcessed.Add(1)
+	cmdResponse, err := processCommandShared(command, commandHandler, cmdCtx)
+	if err != nil {
+		return ErrorResponse(err)
+	}
+
+	utils.Log(fmt.Sprintf("(Request handler) Command %s result: %s", command.CommandType, cmdResponse.String()))
+
+	response := CommandResponse{
+		Value: cmdResponse,
+	}
+	return response
+}
+
+// blockingHandler is implemented by commands which may wait for other clients, e.g. XREAD BLOCK. Those
+// don't hold the keyspace lock while waiting, they hold it around each read of the keyspace instead.
+type blockingHandler interface {
+	Blocks(cmdCtx *CommandContext) bool
+}
+
+// processCommandShared runs the command holding the keyspace lock shared, so it doesn't interleave with
+// a running transaction. Commands waiting for other clients don't hold the lock, they'd block transactions.
+func processCommandShared(command *Command, handler CommandHandler[string], cmdCtx *CommandContext) (respparser.RespData, error) {
+	spec, _ := lookupCommandSpec(command)
+	blocking, ok := handler.(blockingHandler)
+	if (spec.FirstKey > 0 || spec.GetKeys != nil) && !(ok && blocking.Blocks(cmdCtx)) {
+		keyspaceLock.RLock()
+		defer keyspaceLock.RUnlock()
+	}
+	return processCommand(handler, cmdCtx)
+}
+
+// processCommand runs the command, keys it modified fail transactions of clients watching them.
+// Keys of write commands changing nothing, e.g. SET NX of an existing key, aren't touched.
+func processCommand(handler CommandHandler[string], cmdCtx *CommandContext) (respparser.RespData, error) {
+	cmdCtx.touchedKeys = nil
+	reply, err := handler.Process(cmdCtx)
+	touchWatchedKeys(cmdCtx.touchedKeys)
+	cmdCtx.touchedKeys = nil
+	return reply, err
+}
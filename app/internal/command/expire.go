@@ -0,0 +1,157 @@
+package command
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/store"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+// ExpireCommand sets the expiry of the key (EXPIRE, PEXPIRE, EXPIREAT and PEXPIREAT)
+type ExpireCommand struct {
+	Key       string
+	Millis    int64 // time to live, or unix time with Absolute set
+	Absolute  bool
+	Condition store.ExpireCondition
+}
+
+// TtlCommand returns the remaining time to live of the key (TTL and PTTL)
+type TtlCommand struct {
+	Key    string
+	Millis bool
+}
+
+// ExpireTimeCommand returns the unix time the key expires at (EXPIRETIME and PEXPIRETIME)
+type ExpireTimeCommand struct {
+	Key    string
+	Millis bool
+}
+
+type PersistCommand struct {
+	Key string
+}
+
+// Replies of TTL and EXPIRETIME for keys without expiry and missing keys
+const (
+	noExpiryReply   = -1
+	missingKeyReply = -2
+)
+
+func (c ExpireCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	expireAt := c.Millis
+	if !c.Absolute {
+		expireAt += time.Now().UnixMilli()
+	}
+	utils.Log(fmt.Sprintf("(ExpireCommand) Key %s expires at %d", c.Key, expireAt))
+
+	if !store.SetExpire(c.Key, time.UnixMilli(expireAt), c.Condition) {
+		return respparser.Integer{Value: 0}, nil
+	}
+	cmdCtx.touch(c.Key)
+	return respparser.Integer{Value: 1}, nil
+}
+
+func (c TtlCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	expire, found := store.ExpireOf(c.Key)
+	if !found {
+		return respparser.Integer{Value: missingKeyReply}, nil
+	}
+	if expire == nil {
+		return respparser.Integer{Value: noExpiryReply}, nil
+	}
+
+	ttl := max(time.Until(*expire).Milliseconds(), 0)
+	if c.Millis {
+		return respparser.Integer{Value: int(ttl)}, nil
+	}
+	// remark: seconds are rounded, the same as in Redis
+	return respparser.Integer{Value: int((ttl + 500) / 1000)}, nil
+}
+
+func (c ExpireTimeCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	expire, found := store.ExpireOf(c.Key)
+	if !found {
+		return respparser.Integer{Value: missingKeyReply}, nil
+	}
+	if expire == nil {
+		return respparser.Integer{Value: noExpiryReply}, nil
+	}
+
+	if c.Millis {
+		return respparser.Integer{Value: int(expire.UnixMilli())}, nil
+	}
+	return respparser.Integer{Value: int(expire.Unix())}, nil
+}
+
+func (c PersistCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	if !store.Persist(c.Key) {
+		return respparser.Integer{Value: 0}, nil
+	}
+	cmdCtx.touch(c.Key)
+	return respparser.Integer{Value: 1}, nil
+}
+
+func parseExpireCommand(command *Command) (ExpireCommand, error) {
+	// EXPIRE key seconds [NX | XX | GT | LT], the same for PEXPIRE, EXPIREAT and PEXPIREAT
+	expireCommand := ExpireCommand{
+		Key:      command.CommandValues[0],
+		Absolute: command.CommandType == "EXPIREAT" || command.CommandType == "PEXPIREAT",
+	}
+
+	value, err := strconv.ParseInt(command.CommandValues[1], 10, 64)
+	if err != nil {
+		return ExpireCommand{}, errNotInteger
+	}
+	// remark: negative times are valid and delete the key, seconds are converted to milliseconds
+	if !strings.HasPrefix(command.CommandType, "P") {
+		if value > math.MaxInt64/1000 || value < math.MinInt64/1000 {
+			return ExpireCommand{}, errInvalidExpireTime(command.CommandType)
+		}
+		value *= 1000
+	}
+	if !expireCommand.Absolute && value > math.MaxInt64-time.Now().UnixMilli() {
+		return ExpireCommand{}, errInvalidExpireTime(command.CommandType)
+	}
+	expireCommand.Millis = value
+
+	for _, arg := range command.CommandValues[2:] {
+		switch strings.ToUpper(arg) {
+		case "NX":
+			expireCommand.Condition |= store.ExpireIfNone
+		case "XX":
+			expireCommand.Condition |= store.ExpireIfSet
+		case "GT":
+			expireCommand.Condition |= store.ExpireIfGreater
+		case "LT":
+			expireCommand.Condition |= store.ExpireIfLess
+		default:
+			return ExpireCommand{}, Errorf(CodeErr, "Unsupported option %s", arg)
+		}
+	}
+
+	condition := expireCommand.Condition
+	if condition&store.ExpireIfNone != 0 && condition != store.ExpireIfNone {
+		return ExpireCommand{}, Errorf(CodeErr, "NX and XX, GT or LT options at the same time are not compatible")
+	}
+	if condition&store.ExpireIfGreater != 0 && condition&store.ExpireIfLess != 0 {
+		return ExpireCommand{}, Errorf(CodeErr, "GT and LT options at the same time are not compatible")
+	}
+	return expireCommand, nil
+}
+
+func parseTtlCommand(command *Command) (TtlCommand, error) {
+	return TtlCommand{Key: command.CommandValues[0], Millis: command.CommandType == "PTTL"}, nil
+}
+
+func parseExpireTimeCommand(command *Command) (ExpireTimeCommand, error) {
+	return ExpireTimeCommand{Key: command.CommandValues[0], Millis: command.CommandType == "PEXPIRETIME"}, nil
+}
+
+func parsePersistCommand(command *Command) (PersistCommand, error) {
+	return PersistCommand{Key: command.CommandValues[0]}, nil
+}
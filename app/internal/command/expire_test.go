@@ -0,0 +1,114 @@
+package command
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestExpire(t *testing.T) {
+	persistent := [][]string{{"SET", "key", "value"}}
+	expiring := [][]string{{"SET", "key", "value", "EX", "100"}}
+
+	var tests = []struct {
+		name    string
+		setup   [][]string
+		args    []string
+		want    string
+		wantTtl string // TTL of the key after the command
+	}{
+		{name: "EXPIRE of a missing key", args: []string{"EXPIRE", "key", "100"}, want: "0", wantTtl: "-2"},
+		{name: "EXPIRE", setup: persistent, args: []string{"EXPIRE", "key", "100"}, want: "1", wantTtl: "100"},
+		{name: "PEXPIRE", setup: persistent, args: []string{"PEXPIRE", "key", "100000"}, want: "1", wantTtl: "100"},
+		{name: "NX without expiry", setup: persistent, args: []string{"EXPIRE", "key", "100", "NX"}, want: "1", wantTtl: "100"},
+		{name: "NX with expiry", setup: expiring, args: []string{"EXPIRE", "key", "200", "NX"}, want: "0", wantTtl: "100"},
+		{name: "XX without expiry", setup: persistent, args: []string{"EXPIRE", "key", "100", "XX"}, want: "0", wantTtl: "-1"},
+		{name: "GT without expiry", setup: persistent, args: []string{"EXPIRE", "key", "100", "GT"}, want: "0", wantTtl: "-1"},
+		{name: "LT without expiry", setup: persistent, args: []string{"EXPIRE", "key", "100", "LT"}, want: "1", wantTtl: "100"},
+		{name: "LT with later expiry", setup: expiring, args: []string{"EXPIRE", "key", "200", "LT"}, want: "0", wantTtl: "100"},
+		{name: "XX and GT with later expiry", setup: expiring, args: []string{"EXPIRE", "key", "200", "xx", "gt"}, want: "1", wantTtl: "200"},
+		{name: "EXPIRE of a list", setup: [][]string{{"RPUSH", "key", "a"}}, args: []string{"PEXPIRE", "key", "100000"}, want: "1", wantTtl: "100"},
+		{name: "EXPIRE of a stream", setup: [][]string{{"XADD", "key", "1-1", "field", "value"}}, args: []string{"EXPIRE", "key", "100"}, want: "1", wantTtl: "100"},
+		{name: "RPUSH should keep the expiry", setup: [][]string{{"RPUSH", "key", "a"}, {"EXPIRE", "key", "100"}}, args: []string{"RPUSH", "key", "b"}, want: "2", wantTtl: "100"},
+		{name: "SET should remove the expiry", setup: expiring, args: []string{"SET", "key", "value"}, want: "OK", wantTtl: "-1"},
+		{name: "Negative EXPIRE should delete the key", setup: expiring, args: []string{"EXPIRE", "key", "-1"}, want: "1", wantTtl: "-2"},
+		{name: "PEXPIREAT in the past should delete the key", setup: [][]string{{"RPUSH", "key", "a"}}, args: []string{"PEXPIREAT", "key", "1000"}, want: "1", wantTtl: "-2"},
+
+		{name: "Time not an integer", setup: persistent, args: []string{"EXPIRE", "key", "many"}, want: "ERR value is not an integer or out of range", wantTtl: "-1"},
+		{name: "Seconds overflow", setup: persistent, args: []string{"EXPIRE", "key", "9223372036854775807"}, want: "ERR invalid expire time in 'expire' command", wantTtl: "-1"},
+		{name: "Milliseconds overflow", setup: persistent, args: []string{"PEXPIRE", "key", "9223372036854775807"}, want: "ERR invalid expire time in 'pexpire' command", wantTtl: "-1"},
+		{name: "NX and XX", setup: persistent, args: []string{"EXPIRE", "key", "100", "NX", "XX"}, want: "ERR NX and XX, GT or LT options at the same time are not compatible", wantTtl: "-1"},
+		{name: "GT and LT", setup: persistent, args: []string{"EXPIRE", "key", "100", "GT", "LT"}, want: "ERR GT and LT options at the same time are not compatible", wantTtl: "-1"},
+		{name: "Unknown option", setup: persistent, args: []string{"EXPIRE", "key", "100", "KEEPTTL"}, want: "ERR Unsupported option KEEPTTL", wantTtl: "-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupKeyspace(t, tt.setup)
+			if reply := runTestCommand(tt.args...); reply.String() != tt.want {
+				t.Errorf("ERROR got %q, want %q", reply.String(), tt.want)
+			}
+			// remark: TTL is rounded, so the time passed since the command doesn't change it
+			if reply := runTestCommand("TTL", "key"); reply.String() != tt.wantTtl {
+				t.Errorf("ERROR got TTL %q, want %q", reply.String(), tt.wantTtl)
+			}
+		})
+	}
+}
+
+func TestTtl(t *testing.T) {
+	inHour := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+
+	var tests = []struct {
+		name  string
+		setup [][]string
+		args  []string
+		want  string
+	}{
+		{name: "TTL of a missing key", args: []string{"TTL", "key"}, want: "-2"},
+		{name: "PTTL of a missing key", args: []string{"PTTL", "key"}, want: "-2"},
+		{name: "EXPIRETIME of a missing key", args: []string{"EXPIRETIME", "key"}, want: "-2"},
+		{name: "TTL without expiry", setup: [][]string{{"SET", "key", "value"}}, args: []string{"TTL", "key"}, want: "-1"},
+		{name: "PEXPIRETIME without expiry", setup: [][]string{{"SET", "key", "value"}}, args: []string{"PEXPIRETIME", "key"}, want: "-1"},
+		{name: "TTL", setup: [][]string{{"SET", "key", "value", "EX", "100"}}, args: []string{"TTL", "key"}, want: "100"},
+		{name: "EXPIRETIME after EXPIREAT", setup: [][]string{{"SET", "key", "value"}, {"EXPIREAT", "key", inHour}}, args: []string{"EXPIRETIME", "key"}, want: inHour},
+		{name: "PEXPIRETIME", setup: [][]string{{"SET", "key", "value", "EXAT", inHour}}, args: []string{"PEXPIRETIME", "key"}, want: inHour + "000"},
+		{name: "TTL of an expired key", setup: [][]string{{"SET", "key", "value", "PXAT", "1"}}, args: []string{"TTL", "key"}, want: "-2"},
+		{name: "TTL without key", args: []string{"TTL"}, want: "ERR wrong number of arguments for 'ttl' command"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupKeyspace(t, tt.setup)
+			if reply := runTestCommand(tt.args...); reply.String() != tt.want {
+				t.Errorf("ERROR got %q, want %q", reply.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestPersist(t *testing.T) {
+	var tests = []struct {
+		name    string
+		setup   [][]string
+		want    string
+		wantTtl string // TTL of the key after PERSIST
+	}{
+		{name: "Missing key", want: "0", wantTtl: "-2"},
+		{name: "Key without expiry", setup: [][]string{{"SET", "key", "value"}}, want: "0", wantTtl: "-1"},
+		{name: "Key with expiry", setup: [][]string{{"SET", "key", "value", "EX", "100"}}, want: "1", wantTtl: "-1"},
+		{name: "List with expiry", setup: [][]string{{"RPUSH", "key", "a"}, {"EXPIRE", "key", "100"}}, want: "1", wantTtl: "-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupKeyspace(t, tt.setup)
+			if reply := runTestCommand("PERSIST", "key"); reply.String() != tt.want {
+				t.Errorf("ERROR got %q, want %q", reply.String(), tt.want)
+			}
+			if reply := runTestCommand("TTL", "key"); reply.String() != tt.wantTtl {
+				t.Errorf("ERROR got TTL %q, want %q", reply.String(), tt.wantTtl)
+			}
+		})
+	}
+}
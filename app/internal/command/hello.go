@@ -0,0 +1,101 @@
+package command
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/acl"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+// ServerVersion is reported by HELLO
+const ServerVersion = "7.2.0"
+
+type HelloCommand struct {
+	Protocol respparser.Protocol // zero keeps the current protocol
+	Username string
+	Password string
+	Auth     bool
+	Name     string
+	SetName  bool
+}
+
+var errHelloNoAuth = Errorf(CodeNoAuth, "HELLO must be called with the client already authenticated, otherwise the HELLO <proto> AUTH <user> <pass> option can be used to authenticate the client and select the RESP protocol version at the same time")
+
+func (c HelloCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	utils.Log(fmt.Sprintf("(HelloCommand) Processing HELLO %d", c.Protocol))
+	self := cmdCtx.Client
+
+	if c.Auth {
+		if !acl.Authenticate(c.Username, c.Password) {
+			acl.AddLogEntry(acl.LogReasonAuth, "AUTH", c.Username, self.Info())
+			return respparser.SimpleError{}, errWrongPass
+		}
+		self.Authenticate(c.Username)
+	} else if !self.Authenticated() {
+		return respparser.SimpleError{}, errHelloNoAuth
+	}
+
+	if c.SetName {
+		if err := validateClientName(c.Name); err != nil {
+			return respparser.SimpleError{}, err
+		}
+		self.SetName(c.Name)
+	}
+	if c.Protocol != 0 {
+		self.SetProtocol(c.Protocol)
+	}
+
+	info := respparser.Map{}
+	info.Add("server", respparser.BulkString{Value: "redis"})
+	info.Add("version", respparser.BulkString{Value: ServerVersion})
+	info.Add("proto", respparser.Integer{Value: int(self.Protocol())})
+	info.Add("id", respparser.Integer{Value: int(self.ID)})
+	info.Add("mode", respparser.BulkString{Value: "standalone"})
+	info.Add("role", respparser.BulkString{Value: "master"})
+	info.Add("modules", respparser.Array{})
+	return info, nil
+}
+
+func parseHelloCommand(command *Command) (HelloCommand, error) {
+	helloCommand := HelloCommand{}
+	args := command.CommandValues
+	if len(args) == 0 {
+		return helloCommand, nil
+	}
+
+	protover, err := strconv.Atoi(args[0])
+	if err != nil {
+		return HelloCommand{}, Errorf(CodeErr, "Protocol version is not an integer or out of range")
+	}
+	if protover != int(respparser.Resp2) && protover != int(respparser.Resp3) {
+		return HelloCommand{}, Errorf(CodeNoProto, "unsupported protocol version")
+	}
+	helloCommand.Protocol = respparser.Protocol(protover)
+
+	for n := 1; n < len(args); n++ {
+		remaining := len(args) - n - 1
+		switch strings.ToUpper(args[n]) {
+		case "AUTH":
+			if remaining < 2 {
+				return HelloCommand{}, Errorf(CodeErr, "Syntax error in HELLO option '%s'", args[n])
+			}
+			helloCommand.Auth = true
+			helloCommand.Username = args[n+1]
+			helloCommand.Password = args[n+2]
+			n += 2
+		case "SETNAME":
+			if remaining < 1 {
+				return HelloCommand{}, Errorf(CodeErr, "Syntax error in HELLO option '%s'", args[n])
+			}
+			helloCommand.SetName = true
+			helloCommand.Name = args[n+1]
+			n++
+		default:
+			return HelloCommand{}, Errorf(CodeErr, "Syntax error in HELLO option '%s'", args[n])
+		}
+	}
+	return helloCommand, nil
+}
@@ -0,0 +1,80 @@
+package command
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+// runTestCommand parses and processes the command without a client, errors are returned as error replies
+func runTestCommand(args ...string) respparser.RespData {
+	command := &Command{CommandType: strings.ToUpper(args[0]), CommandValues: args[1:]}
+	handler, err := GetCommandHandler(command)
+	if err != nil {
+		return ErrorResponse(err).Value
+	}
+	reply, err := handler.Process(&CommandContext{})
+	if err != nil {
+		return ErrorResponse(err).Value
+	}
+	return reply
+}
+
+// commandTest is a command run on a keyspace holding only the keys created by setup,
+// its reply and the keyspace left by it are verified
+type commandTest struct {
+	name  string
+	setup [][]string
+	args  []string
+	want  string
+	state []stateCheck
+}
+
+// stateCheck is a command reading the keyspace and its expected reply, e.g. TTL of the modified key
+type stateCheck struct {
+	args []string
+	want string
+}
+
+// runCommandTests runs each test on its own keyspace, so tests can run alone and in any order
+func runCommandTests(t *testing.T, tests []commandTest) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupKeyspace(t, tt.setup)
+			if reply := runTestCommand(tt.args...); reply.String() != tt.want {
+				t.Errorf("ERROR got %q, want %q", reply.String(), tt.want)
+			}
+			for _, check := range tt.state {
+				if reply := runTestCommand(check.args...); reply.String() != check.want {
+					t.Errorf("ERROR %s got %q, want %q", strings.Join(check.args, " "), reply.String(), check.want)
+				}
+			}
+		})
+	}
+}
+
+// setupKeyspace deletes every key and runs the setup commands, so a test case doesn't depend on keys left by others
+func setupKeyspace(t *testing.T, setup [][]string) {
+	t.Helper()
+	if keys := keyspaceKeys(); len(keys) > 0 {
+		runTestCommand(append([]string{"DEL"}, keys...)...)
+	}
+	for _, args := range setup {
+		if reply, failed := runTestCommand(args...).(respparser.SimpleError); failed {
+			t.Fatalf("ERROR setup %s failed: %s", strings.Join(args, " "), reply.Value)
+		}
+	}
+}
+
+// keyspaceKeys returns all keys sorted, KEYS returns them in any order
+func keyspaceKeys() []string {
+	keys := []string{}
+	for _, key := range runTestCommand("KEYS", "*").(respparser.Array).Items {
+		keys = append(keys, key.String())
+	}
+	slices.Sort(keys)
+	return keys
+}
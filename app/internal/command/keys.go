@@ -0,0 +1,244 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/store"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/streamstore"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+// DelCommand removes keys of any type (DEL and UNLINK)
+type DelCommand struct {
+	Keys []string
+}
+
+// ExistsCommand counts existing keys (EXISTS and TOUCH)
+type ExistsCommand struct {
+	Keys []string
+}
+
+// RenameCommand moves the value to the new key (RENAME), with NX only if the new key doesn't exist (RENAMENX)
+type RenameCommand struct {
+	Key    string
+	NewKey string
+	NX     bool
+}
+
+type CopyCommand struct {
+	Source      string
+	Destination string
+	Replace     bool
+}
+
+type RandomKeyCommand struct{}
+
+// KeysCommand returns all keys matching the glob-style pattern
+type KeysCommand struct {
+	Pattern string
+}
+
+// ScanCommand iterates the keyspace incrementally, the cursor of the reply continues the iteration
+type ScanCommand struct {
+	Cursor uint64
+	Count  int
+	Filter store.ScanFilter
+}
+
+// scanDefaultCount is the number of keys visited by SCAN without COUNT, the same as in Redis
+const scanDefaultCount = 10
+
+var errNoSuchKey = Errorf(CodeErr, "no such key")
+
+func (c DelCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	utils.Log(fmt.Sprintf("(DelCommand) Deleting keys %v", c.Keys))
+	deleted := store.Delete(c.Keys...)
+	cmdCtx.touch(deleted...)
+	return respparser.Integer{Value: len(deleted)}, nil
+}
+
+func (c ExistsCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	return respparser.Integer{Value: store.Exists(c.Keys...)}, nil
+}
+
+func (c RenameCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	utils.Log(fmt.Sprintf("(RenameCommand) Renaming key %s to %s", c.Key, c.NewKey))
+
+	renamed, err := store.Rename(c.Key, c.NewKey, c.NX)
+	if errors.Is(err, store.ErrNoSuchKey) {
+		return respparser.SimpleError{}, errNoSuchKey
+	}
+	if renamed {
+		cmdCtx.touch(c.Key, c.NewKey)
+		notifyStreamWaiters(c.NewKey)
+	}
+
+	if !c.NX {
+		return okResponse, nil
+	}
+	if renamed {
+		return respparser.Integer{Value: 1}, nil
+	}
+	return respparser.Integer{Value: 0}, nil
+}
+
+func (c CopyCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	utils.Log(fmt.Sprintf("(CopyCommand) Copying key %s to %s", c.Source, c.Destination))
+
+	if !store.Copy(c.Source, c.Destination, c.Replace) {
+		return respparser.Integer{Value: 0}, nil
+	}
+	cmdCtx.touch(c.Destination)
+	notifyStreamWaiters(c.Destination)
+	return respparser.Integer{Value: 1}, nil
+}
+
+func (c RandomKeyCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	key, found := store.RandomKey()
+	return respparser.BulkString{Value: key, IsNull: !found}, nil
+}
+
+func (c KeysCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	result := respparser.Array{Items: []respparser.RespData{}}
+	for _, key := range store.Keys(c.Pattern) {
+		result.Items = append(result.Items, respparser.BulkString{Value: key})
+	}
+	return result, nil
+}
+
+func (c ScanCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	keys, cursor := store.Scan(c.Cursor, c.Count, c.Filter)
+	utils.Log(fmt.Sprintf("(ScanCommand) Cursor %d, %d keys, next cursor %d", c.Cursor, len(keys), cursor))
+
+	result := respparser.Array{Items: []respparser.RespData{}}
+	for _, key := range keys {
+		result.Items = append(result.Items, respparser.BulkString{Value: key})
+	}
+	return respparser.Array{Items: []respparser.RespData{
+		respparser.BulkString{Value: strconv.FormatUint(cursor, 10)},
+		result,
+	}}, nil
+}
+
+// notifyStreamWaiters wakes up readers blocked on the key when it holds a stream moved or copied there
+func notifyStreamWaiters(key string) {
+	if store.TypeOf(key) == store.TypeStream {
+		streamstore.NotifyWaiters(key)
+	}
+}
+
+func parseDelCommand(command *Command) (DelCommand, error) {
+	return DelCommand{Keys: command.CommandValues}, nil
+}
+
+func parseExistsCommand(command *Command) (ExistsCommand, error) {
+	return ExistsCommand{Keys: command.CommandValues}, nil
+}
+
+func parseRenameCommand(command *Command) (RenameCommand, error) {
+	renameCommand := RenameCommand{
+		Key:    command.CommandValues[0],
+		NewKey: command.CommandValues[1],
+		NX:     command.CommandType == "RENAMENX",
+	}
+	return renameCommand, nil
+}
+
+func parseCopyCommand(command *Command) (CopyCommand, error) {
+	// COPY source destination [DB destination-db] [REPLACE]
+	copyCommand := CopyCommand{
+		Source:      command.CommandValues[0],
+		Destination: command.CommandValues[1],
+	}
+
+	args := command.CommandValues[2:]
+	for n := 0; n < len(args); n++ {
+		switch strings.ToUpper(args[n]) {
+		case "REPLACE":
+			copyCommand.Replace = true
+
+		case "DB":
+			if n+1 >= len(args) {
+				return CopyCommand{}, errSyntax
+			}
+			n++
+			db, err := strconv.Atoi(args[n])
+			if err != nil {
+				return CopyCommand{}, errNotInteger
+			}
+			// remark: there is a single database, the same as Redis in cluster mode
+			if db != 0 {
+				return CopyCommand{}, Errorf(CodeErr, "DB index is out of range")
+			}
+
+		default:
+			return CopyCommand{}, errSyntax
+		}
+	}
+
+	if copyCommand.Source == copyCommand.Destination {
+		return CopyCommand{}, Errorf(CodeErr, "source and destination objects are the same")
+	}
+	return copyCommand, nil
+}
+
+func parseRandomKeyCommand(_ *Command) (RandomKeyCommand, error) {
+	return RandomKeyCommand{}, nil
+}
+
+func parseKeysCommand(command *Command) (KeysCommand, error) {
+	return KeysCommand{Pattern: command.CommandValues[0]}, nil
+}
+
+func parseScanCommand(command *Command) (ScanCommand, error) {
+	// SCAN cursor [MATCH pattern] [COUNT count] [TYPE type]
+	cursor, err := strconv.ParseUint(command.CommandValues[0], 10, 64)
+	if err != nil {
+		return ScanCommand{}, Errorf(CodeErr, "invalid cursor")
+	}
+	scanCommand := ScanCommand{Cursor: cursor, Count: scanDefaultCount}
+
+	args := command.CommandValues[1:]
+	for n := 0; n < len(args); n++ {
+		option := strings.ToUpper(args[n])
+		if n+1 >= len(args) {
+			return ScanCommand{}, errSyntax
+		}
+		n++
+
+		switch option {
+		case "MATCH":
+			// remark: * matches every key, the pattern doesn't need to be checked then
+			if args[n] != "*" {
+				scanCommand.Filter.Pattern = args[n]
+			}
+
+		case "COUNT":
+			count, err := strconv.Atoi(args[n])
+			if err != nil {
+				return ScanCommand{}, errNotInteger
+			}
+			if count < 1 {
+				return ScanCommand{}, errSyntax
+			}
+			scanCommand.Count = count
+
+		case "TYPE":
+			keyType := strings.ToLower(args[n])
+			switch keyType {
+			case store.TypeString, store.TypeList, store.TypeStream, "set", "zset", "hash":
+				scanCommand.Filter.Type = keyType
+			default:
+				return ScanCommand{}, Errorf(CodeErr, "unknown type name '%s'", args[n])
+			}
+
+		default:
+			return ScanCommand{}, errSyntax
+		}
+	}
+	return scanCommand, nil
+}
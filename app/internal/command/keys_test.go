@@ -0,0 +1,453 @@
+package command
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+func TestDel(t *testing.T) {
+	var tests = []struct {
+		name     string
+		setup    [][]string
+		args     []string
+		want     string
+		wantKeys []string
+	}{
+		{
+			name:     "DEL should count removed keys of every type",
+			setup:    [][]string{{"SET", "string", "value"}, {"RPUSH", "list", "a"}, {"XADD", "stream", "1-1", "field", "value"}},
+			args:     []string{"DEL", "string", "stream", "missing"},
+			want:     "2",
+			wantKeys: []string{"list"},
+		},
+		{
+			name:     "DEL of missing keys",
+			setup:    [][]string{{"SET", "string", "value"}},
+			args:     []string{"DEL", "missing"},
+			want:     "0",
+			wantKeys: []string{"string"},
+		},
+		{
+			name:  "DEL of an expired key",
+			setup: [][]string{{"SET", "string", "value", "PXAT", "1"}},
+			args:  []string{"DEL", "string"},
+			want:  "0",
+		},
+		{
+			name:     "UNLINK",
+			setup:    [][]string{{"SET", "string", "value"}, {"RPUSH", "list", "a"}},
+			args:     []string{"UNLINK", "list"},
+			want:     "1",
+			wantKeys: []string{"string"},
+		},
+		{
+			name:     "Deleted stream should be recreated from scratch",
+			setup:    [][]string{{"XADD", "stream", "5-1", "field", "value"}, {"DEL", "stream"}},
+			args:     []string{"XADD", "stream", "1-1", "field", "value"},
+			want:     "1-1",
+			wantKeys: []string{"stream"},
+		},
+		{
+			name:     "DEL without keys",
+			setup:    [][]string{{"SET", "string", "value"}},
+			args:     []string{"DEL"},
+			want:     "ERR wrong number of arguments for 'del' command",
+			wantKeys: []string{"string"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupKeyspace(t, tt.setup)
+			if reply := runTestCommand(tt.args...); reply.String() != tt.want {
+				t.Errorf("ERROR got %q, want %q", reply.String(), tt.want)
+			}
+			if keys := keyspaceKeys(); !slices.Equal(keys, tt.wantKeys) {
+				t.Errorf("ERROR got keys %v, want %v", keys, tt.wantKeys)
+			}
+		})
+	}
+}
+
+func TestExistsAndTouch(t *testing.T) {
+	var tests = []struct {
+		name  string
+		setup [][]string
+		args  []string
+		want  string
+	}{
+		{
+			name:  "EXISTS should count keys of every type",
+			setup: [][]string{{"SET", "string", "value"}, {"RPUSH", "list", "a"}, {"XADD", "stream", "1-1", "field", "value"}},
+			args:  []string{"EXISTS", "string", "list", "stream", "missing"},
+			want:  "3",
+		},
+		{
+			name:  "EXISTS should count repeated keys",
+			setup: [][]string{{"SET", "string", "value"}},
+			args:  []string{"EXISTS", "string", "string"},
+			want:  "2",
+		},
+		{
+			name:  "Expired key shouldn't exist",
+			setup: [][]string{{"SET", "string", "value", "PXAT", "1"}},
+			args:  []string{"EXISTS", "string"},
+			want:  "0",
+		},
+		{
+			name:  "TOUCH should count existing keys",
+			setup: [][]string{{"RPUSH", "list", "a"}},
+			args:  []string{"TOUCH", "list", "missing"},
+			want:  "1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupKeyspace(t, tt.setup)
+			if reply := runTestCommand(tt.args...); reply.String() != tt.want {
+				t.Errorf("ERROR got %q, want %q", reply.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRename(t *testing.T) {
+	var tests = []struct {
+		name     string
+		setup    [][]string
+		args     []string
+		want     string
+		wantKeys []string
+		read     []string // reads the value left by the command
+		wantRead string
+	}{
+		{
+			name:     "RENAME should move the value",
+			setup:    [][]string{{"RPUSH", "list", "a", "b"}},
+			args:     []string{"RENAME", "list", "renamed"},
+			want:     "OK",
+			wantKeys: []string{"renamed"},
+			read:     []string{"LRANGE", "renamed", "0", "-1"},
+			wantRead: "[a,b]",
+		},
+		{
+			name:     "RENAME should move the expiry",
+			setup:    [][]string{{"SET", "string", "value", "EX", "100"}},
+			args:     []string{"RENAME", "string", "renamed"},
+			want:     "OK",
+			wantKeys: []string{"renamed"},
+			read:     []string{"TTL", "renamed"},
+			wantRead: "100",
+		},
+		{
+			name:     "RENAME should replace a value of other type",
+			setup:    [][]string{{"SET", "string", "value"}, {"RPUSH", "list", "a"}},
+			args:     []string{"RENAME", "string", "list"},
+			want:     "OK",
+			wantKeys: []string{"list"},
+			read:     []string{"GET", "list"},
+			wantRead: "value",
+		},
+		{
+			name:     "RENAME to the same key",
+			setup:    [][]string{{"SET", "string", "value"}},
+			args:     []string{"RENAME", "string", "string"},
+			want:     "OK",
+			wantKeys: []string{"string"},
+			read:     []string{"GET", "string"},
+			wantRead: "value",
+		},
+		{
+			name: "RENAME of a missing key",
+			args: []string{"RENAME", "missing", "other"},
+			want: "ERR no such key",
+		},
+		{
+			name:     "RENAMENX shouldn't replace an existing key",
+			setup:    [][]string{{"SET", "string", "value"}, {"RPUSH", "list", "a"}},
+			args:     []string{"RENAMENX", "string", "list"},
+			want:     "0",
+			wantKeys: []string{"list", "string"},
+			read:     []string{"TYPE", "list"},
+			wantRead: "list",
+		},
+		{
+			name:     "RENAMENX to a new key",
+			setup:    [][]string{{"SET", "string", "value"}},
+			args:     []string{"RENAMENX", "string", "renamed"},
+			want:     "1",
+			wantKeys: []string{"renamed"},
+			read:     []string{"GET", "renamed"},
+			wantRead: "value",
+		},
+		{
+			name: "RENAMENX of a missing key",
+			args: []string{"RENAMENX", "missing", "other"},
+			want: "ERR no such key",
+		},
+		{
+			name:     "RENAME without new key",
+			setup:    [][]string{{"SET", "string", "value"}},
+			args:     []string{"RENAME", "string"},
+			want:     "ERR wrong number of arguments for 'rename' command",
+			wantKeys: []string{"string"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupKeyspace(t, tt.setup)
+			if reply := runTestCommand(tt.args...); reply.String() != tt.want {
+				t.Errorf("ERROR got %q, want %q", reply.String(), tt.want)
+			}
+			if keys := keyspaceKeys(); !slices.Equal(keys, tt.wantKeys) {
+				t.Errorf("ERROR got keys %v, want %v", keys, tt.wantKeys)
+			}
+			if tt.read != nil {
+				if reply := runTestCommand(tt.read...); reply.String() != tt.wantRead {
+					t.Errorf("ERROR got %q after the command, want %q", reply.String(), tt.wantRead)
+				}
+			}
+		})
+	}
+}
+
+func TestCopy(t *testing.T) {
+	var tests = []struct {
+		name     string
+		setup    [][]string
+		args     []string
+		want     string
+		wantKeys []string
+		read     []string // reads the value left by the command
+		wantRead string
+	}{
+		{
+			name:     "COPY should copy the value",
+			setup:    [][]string{{"RPUSH", "source", "a"}},
+			args:     []string{"COPY", "source", "copy"},
+			want:     "1",
+			wantKeys: []string{"copy", "source"},
+			read:     []string{"LRANGE", "copy", "0", "-1"},
+			wantRead: "[a]",
+		},
+		{
+			name:     "Copy should be independent of the source",
+			setup:    [][]string{{"RPUSH", "source", "a"}, {"COPY", "source", "copy"}},
+			args:     []string{"RPUSH", "copy", "b"},
+			want:     "2",
+			wantKeys: []string{"copy", "source"},
+			read:     []string{"LRANGE", "source", "0", "-1"},
+			wantRead: "[a]",
+		},
+		{
+			name:     "COPY should copy the expiry",
+			setup:    [][]string{{"SET", "source", "value", "EX", "100"}},
+			args:     []string{"COPY", "source", "copy"},
+			want:     "1",
+			wantKeys: []string{"copy", "source"},
+			read:     []string{"TTL", "copy"},
+			wantRead: "100",
+		},
+		{
+			name:     "COPY shouldn't replace without REPLACE",
+			setup:    [][]string{{"RPUSH", "source", "a"}, {"RPUSH", "copy", "b"}},
+			args:     []string{"COPY", "source", "copy"},
+			want:     "0",
+			wantKeys: []string{"copy", "source"},
+			read:     []string{"LRANGE", "copy", "0", "-1"},
+			wantRead: "[b]",
+		},
+		{
+			name:     "COPY REPLACE",
+			setup:    [][]string{{"RPUSH", "source", "a"}, {"SET", "copy", "value"}},
+			args:     []string{"COPY", "source", "copy", "REPLACE"},
+			want:     "1",
+			wantKeys: []string{"copy", "source"},
+			read:     []string{"LRANGE", "copy", "0", "-1"},
+			wantRead: "[a]",
+		},
+		{
+			name:     "COPY of a missing key",
+			setup:    [][]string{{"RPUSH", "copy", "b"}},
+			args:     []string{"COPY", "missing", "copy", "REPLACE"},
+			want:     "0",
+			wantKeys: []string{"copy"},
+		},
+		{
+			name:     "COPY to DB 0",
+			setup:    [][]string{{"RPUSH", "source", "a"}},
+			args:     []string{"COPY", "source", "copy", "DB", "0"},
+			want:     "1",
+			wantKeys: []string{"copy", "source"},
+		},
+		{
+			name:     "COPY to other DB",
+			setup:    [][]string{{"RPUSH", "source", "a"}},
+			args:     []string{"COPY", "source", "copy", "DB", "1"},
+			want:     "ERR DB index is out of range",
+			wantKeys: []string{"source"},
+		},
+		{
+			name:     "COPY DB not an integer",
+			setup:    [][]string{{"RPUSH", "source", "a"}},
+			args:     []string{"COPY", "source", "copy", "DB", "first"},
+			want:     "ERR value is not an integer or out of range",
+			wantKeys: []string{"source"},
+		},
+		{
+			name:     "COPY DB without value",
+			setup:    [][]string{{"RPUSH", "source", "a"}},
+			args:     []string{"COPY", "source", "copy", "DB"},
+			want:     "ERR syntax error",
+			wantKeys: []string{"source"},
+		},
+		{
+			name:     "COPY to itself",
+			setup:    [][]string{{"RPUSH", "source", "a"}},
+			args:     []string{"COPY", "source", "source"},
+			want:     "ERR source and destination objects are the same",
+			wantKeys: []string{"source"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupKeyspace(t, tt.setup)
+			if reply := runTestCommand(tt.args...); reply.String() != tt.want {
+				t.Errorf("ERROR got %q, want %q", reply.String(), tt.want)
+			}
+			if keys := keyspaceKeys(); !slices.Equal(keys, tt.wantKeys) {
+				t.Errorf("ERROR got keys %v, want %v", keys, tt.wantKeys)
+			}
+			if tt.read != nil {
+				if reply := runTestCommand(tt.read...); reply.String() != tt.wantRead {
+					t.Errorf("ERROR got %q after the command, want %q", reply.String(), tt.wantRead)
+				}
+			}
+		})
+	}
+}
+
+func TestRandomKey(t *testing.T) {
+	setupKeyspace(t, nil)
+	if reply := runTestCommand("RANDOMKEY"); reply.(respparser.BulkString).IsNull != true {
+		t.Errorf("ERROR got %v, want null reply for empty keyspace", reply)
+	}
+
+	setupKeyspace(t, [][]string{{"SET", "expired", "value", "PXAT", "1"}, {"SET", "string", "value"}})
+	if reply := runTestCommand("RANDOMKEY"); reply.String() != "string" {
+		t.Errorf("ERROR got %q, want the only existing key", reply.String())
+	}
+}
+
+func TestKeys(t *testing.T) {
+	patternKeys := [][]string{{"SET", "a1", "value"}, {"SET", "b2", "value"}, {"SET", "*", "value"}}
+
+	var tests = []struct {
+		name  string
+		setup [][]string
+		args  []string
+		want  []string
+	}{
+		{name: "Range class", setup: patternKeys, args: []string{"KEYS", "[a-b]?"}, want: []string{"a1", "b2"}},
+		{name: "Negated class", setup: patternKeys, args: []string{"KEYS", "[^a]2"}, want: []string{"b2"}},
+		{name: "Escaped star", setup: patternKeys, args: []string{"KEYS", "\\*"}, want: []string{"*"}},
+		{name: "Star inside", setup: [][]string{{"RPUSH", "list", "a"}, {"RPUSH", "lost", "a"}, {"SET", "last", "value"}}, args: []string{"KEYS", "l*t"}, want: []string{"last", "list", "lost"}},
+		{name: "Keys of every type", setup: [][]string{{"SET", "string", "value"}, {"RPUSH", "list", "a"}, {"XADD", "stream", "1-1", "field", "value"}}, args: []string{"KEYS", "*"}, want: []string{"list", "stream", "string"}},
+		{name: "Expired key shouldn't be returned", setup: [][]string{{"SET", "a1", "value", "PXAT", "1"}, {"SET", "a2", "value"}}, args: []string{"KEYS", "a?"}, want: []string{"a2"}},
+		{name: "No match", setup: patternKeys, args: []string{"KEYS", "missing*"}, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupKeyspace(t, tt.setup)
+			reply, ok := runTestCommand(tt.args...).(respparser.Array)
+			if !ok {
+				t.Fatalf("ERROR got %v, want array of keys", reply)
+			}
+			// remark: order of keys is unspecified
+			keys := []string{}
+			for _, item := range reply.Items {
+				keys = append(keys, item.String())
+			}
+			slices.Sort(keys)
+			if !slices.Equal(keys, tt.want) {
+				t.Errorf("ERROR got %v, want %v", keys, tt.want)
+			}
+		})
+	}
+
+	if reply := runTestCommand("KEYS"); reply.String() != "ERR wrong number of arguments for 'keys' command" {
+		t.Errorf("ERROR got %q, want arity error", reply.String())
+	}
+}
+
+func TestScan(t *testing.T) {
+	keys := [][]string{{"SET", "string", "value"}, {"RPUSH", "list", "a"}, {"XADD", "stream", "1-1", "field", "value"}}
+
+	var tests = []struct {
+		name  string
+		setup [][]string
+		args  []string
+		want  string
+	}{
+		{name: "TYPE", setup: keys, args: []string{"SCAN", "0", "TYPE", "STREAM", "COUNT", "100000"}, want: "[0,[stream]]"},
+		{name: "MATCH", setup: keys, args: []string{"SCAN", "0", "MATCH", "s*g", "COUNT", "100000"}, want: "[0,[string]]"},
+		{name: "TYPE of no key", setup: keys, args: []string{"SCAN", "0", "COUNT", "100000", "TYPE", "hash"}, want: "[0,[]]"},
+		{name: "Expired key shouldn't be returned", setup: [][]string{{"SET", "string", "value", "PXAT", "1"}}, args: []string{"SCAN", "0", "COUNT", "100000"}, want: "[0,[]]"},
+		{name: "Invalid cursor", args: []string{"SCAN", "first"}, want: "ERR invalid cursor"},
+		{name: "Negative cursor", args: []string{"SCAN", "-1"}, want: "ERR invalid cursor"},
+		{name: "COUNT not an integer", args: []string{"SCAN", "0", "COUNT", "many"}, want: "ERR value is not an integer or out of range"},
+		{name: "COUNT not positive", args: []string{"SCAN", "0", "COUNT", "0"}, want: "ERR syntax error"},
+		{name: "MATCH without pattern", args: []string{"SCAN", "0", "MATCH"}, want: "ERR syntax error"},
+		{name: "Unknown option", args: []string{"SCAN", "0", "LIMIT", "1"}, want: "ERR syntax error"},
+		{name: "Unknown type", args: []string{"SCAN", "0", "TYPE", "tree"}, want: "ERR unknown type name 'tree'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupKeyspace(t, tt.setup)
+			if reply := runTestCommand(tt.args...); reply.String() != tt.want {
+				t.Errorf("ERROR got %q, want %q", reply.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestScanIteration(t *testing.T) {
+	setup := [][]string{}
+	want := []string{}
+	for n := range 50 {
+		key := "key:" + strconv.Itoa(n)
+		setup = append(setup, []string{"SET", key, "value"})
+		want = append(want, key)
+	}
+	setupKeyspace(t, setup)
+	slices.Sort(want)
+
+	// iteration with the default count should return every key, a key may be returned more than once
+	found := map[string]bool{}
+	cursor := "0"
+	for {
+		reply := runTestCommand("SCAN", cursor)
+		array, ok := reply.(respparser.Array)
+		if !ok || len(array.Items) != 2 {
+			t.Fatalf("ERROR got %v, want cursor and keys", reply)
+		}
+		for _, key := range array.Items[1].(respparser.Array).Items {
+			found[key.String()] = true
+		}
+		if cursor = array.Items[0].String(); cursor == "0" {
+			break
+		}
+	}
+	if keys := slices.Sorted(maps.Keys(found)); !slices.Equal(keys, want) {
+		t.Errorf("ERROR got %v, want %v", keys, want)
+	}
+}
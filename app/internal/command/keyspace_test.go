@@ -0,0 +1,41 @@
+package command
+
+import "testing"
+
+func TestWrongType(t *testing.T) {
+	const wrongType = "WRONGTYPE Operation against a key holding the wrong kind of value"
+	typed := [][]string{
+		{"SET", "string", "value"},
+		{"RPUSH", "list", "a", "b"},
+		{"XADD", "stream", "1-1", "field", "value"},
+	}
+
+	runCommandTests(t, []commandTest{
+		{name: "TYPE of a string", setup: typed, args: []string{"TYPE", "string"}, want: "string"},
+		{name: "TYPE of a list", setup: typed, args: []string{"TYPE", "list"}, want: "list"},
+		{name: "TYPE of a stream", setup: typed, args: []string{"TYPE", "stream"}, want: "stream"},
+		{name: "TYPE of a missing key", setup: typed, args: []string{"TYPE", "missing"}, want: "none"},
+
+		{name: "GET of a list", setup: typed, args: []string{"GET", "list"}, want: wrongType},
+		{name: "GET of a stream", setup: typed, args: []string{"GET", "stream"}, want: wrongType},
+		{name: "RPUSH to a string", setup: typed, args: []string{"RPUSH", "string", "a"}, want: wrongType,
+			state: []stateCheck{{[]string{"GET", "string"}, "value"}}},
+		{name: "RPUSH to a stream", setup: typed, args: []string{"RPUSH", "stream", "a"}, want: wrongType,
+			state: []stateCheck{{[]string{"XRANGE", "stream", "-", "+"}, "[[1-1,{field:value}]]"}}},
+		{name: "LRANGE of a string", setup: typed, args: []string{"LRANGE", "string", "0", "-1"}, want: wrongType},
+		{name: "XADD to a list", setup: typed, args: []string{"XADD", "list", "*", "field", "value"}, want: wrongType,
+			state: []stateCheck{{[]string{"LRANGE", "list", "0", "-1"}, "[a,b]"}}},
+		{name: "XRANGE of a string", setup: typed, args: []string{"XRANGE", "string", "-", "+"}, want: wrongType},
+		{name: "XREAD of a list", setup: typed, args: []string{"XREAD", "STREAMS", "list", "0-0"}, want: wrongType},
+		{name: "SET GET of a list", setup: typed, args: []string{"SET", "list", "value", "GET"}, want: wrongType,
+			state: []stateCheck{{[]string{"LRANGE", "list", "0", "-1"}, "[a,b]"}}},
+
+		// remark: SET replaces values of any type, the same as in Redis
+		{name: "SET replaces a stream", setup: typed, args: []string{"SET", "stream", "value"}, want: "OK",
+			state: []stateCheck{
+				{[]string{"TYPE", "stream"}, "string"},
+				{[]string{"GET", "stream"}, "value"},
+				{[]string{"XRANGE", "stream", "-", "+"}, wrongType},
+			}},
+	})
+}
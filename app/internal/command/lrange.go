@@ -0,0 +1,70 @@
+package command
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/store"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+type LRangeCommand struct {
+	Key   string
+	Start int
+	Stop  int
+}
+
+func (c LRangeCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	utils.Log(fmt.Sprintf("(LRangeCommand) Processing list with key %s", c.Key))
+
+	list, found, err := store.GetList(c.Key)
+	if err != nil {
+		return respparser.SimpleError{}, err
+	}
+	if !found {
+		return respparser.Array{}, nil
+	}
+
+	length := len(list.Values)
+	start, stop := c.Start, c.Stop
+
+	// remark: negative indexes are counted from the tail of the list
+	if start < 0 {
+		start = max(length+start, 0)
+	}
+	if stop < 0 {
+		stop = length + stop
+	}
+	if stop >= length {
+		stop = length - 1
+	}
+
+	result := respparser.Array{}
+	if start > stop || start >= length {
+		return result, nil
+	}
+
+	for _, value := range list.Values[start : stop+1] {
+		result.Items = append(result.Items, respparser.BulkString{Value: value})
+	}
+	return result, nil
+}
+
+func parseLRangeCommand(command *Command) (LRangeCommand, error) {
+	start, err := strconv.Atoi(command.CommandValues[1])
+	if err != nil {
+		return LRangeCommand{}, errNotInteger
+	}
+
+	stop, err := strconv.Atoi(command.CommandValues[2])
+	if err != nil {
+		return LRangeCommand{}, errNotInteger
+	}
+
+	return LRangeCommand{
+		Key:   command.CommandValues[0],
+		Start: start,
+		Stop:  stop,
+	}, nil
+}
@@ -0,0 +1,126 @@
+package command
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/stats"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+// keyspaceLock makes transactions atomic. Commands accessing keys hold it shared,
+// EXEC holds it exclusively while it runs the queued commands.
+var keyspaceLock sync.RWMutex
+
+// transactionCommands run right away between MULTI and EXEC, other commands are queued
+var transactionCommands = map[string]bool{
+	"MULTI":   true,
+	"EXEC":    true,
+	"DISCARD": true,
+	"WATCH":   true,
+	"RESET":   true,
+}
+
+// transaction holds commands queued after MULTI
+type transaction struct {
+	commands []queuedCommand
+	aborted  bool // a command couldn't be queued, EXEC discards the transaction
+}
+
+type queuedCommand struct {
+	handler CommandHandler[string]
+}
+
+type MultiCommand struct{}
+
+type ExecCommand struct{}
+
+type DiscardCommand struct{}
+
+var queuedResponse = respparser.SimpleString{
+	Value: "QUEUED",
+}
+
+// queueCommand adds the parsed command to the transaction, errors of parsing and permission
+// checks abort the transaction and are replied right away
+func queueCommand(command *Command, handler CommandHandler[string], err error, cmdCtx *CommandContext) CommandResponse {
+	if spec, _ := lookupCommandSpec(command); err == nil && spec.hasFlag("no_multi") {
+		err = Errorf(CodeErr, "Command not allowed inside a transaction")
+	}
+	if err != nil {
+		utils.Log(fmt.Sprintf("(MultiCommand) Transaction aborted, %s can't be queued: %s", command.CommandType, err.Error()))
+		cmdCtx.transaction.aborted = true
+		return ErrorResponse(err)
+	}
+
+	utils.Log(fmt.Sprintf("(MultiCommand) Queued %s", command.CommandType))
+	cmdCtx.transaction.commands = append(cmdCtx.transaction.commands, queuedCommand{handler: handler})
+	return CommandResponse{Value: queuedResponse}
+}
+
+func (c MultiCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	if cmdCtx.transaction != nil {
+		return respparser.SimpleError{}, Errorf(CodeErr, "MULTI calls can not be nested")
+	}
+	cmdCtx.transaction = &transaction{}
+	return okResponse, nil
+}
+
+func (c DiscardCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	if cmdCtx.transaction == nil {
+		return respparser.SimpleError{}, Errorf(CodeErr, "DISCARD without MULTI")
+	}
+	cmdCtx.transaction = nil
+	unwatchAllKeys(cmdCtx)
+	return okResponse, nil
+}
+
+// Process runs the queued commands while no other client accesses keys. A nil array is
+// replied when a watched key was modified, then none of the commands is run.
+func (c ExecCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	t := cmdCtx.transaction
+	if t == nil {
+		return respparser.SimpleError{}, Errorf(CodeErr, "EXEC without MULTI")
+	}
+	cmdCtx.transaction = nil
+	defer unwatchAllKeys(cmdCtx)
+
+	if t.aborted {
+		return respparser.SimpleError{}, Errorf(CodeExecAbort, "Transaction discarded because of previous errors.")
+	}
+
+	keyspaceLock.Lock()
+	defer keyspaceLock.Unlock()
+
+	if cmdCtx.watchedKeyTouched.Load() || watchedKeyExpired(cmdCtx) {
+		utils.Log("(ExecCommand) Watched key modified, transaction not executed")
+		return respparser.Array{IsNull: true}, nil
+	}
+
+	cmdCtx.executingTransaction = true
+	defer func() { cmdCtx.executingTransaction = false }()
+
+	replies := respparser.Array{Items: []respparser.RespData{}}
+	for _, queued := range t.commands {
+		stats.TotalCommandsProcessed.Add(1)
+		reply, err := processCommand(queued.handler, cmdCtx)
+		if err != nil {
+			reply = ErrorResponse(err).Value
+		}
+		replies.Items = append(replies.Items, reply)
+	}
+	return replies, nil
+}
+
+func parseMultiCommand(_ *Command) (MultiCommand, error) {
+	return MultiCommand{}, nil
+}
+
+func parseExecCommand(_ *Command) (ExecCommand, error) {
+	return ExecCommand{}, nil
+}
+
+func parseDiscardCommand(_ *Command) (DiscardCommand, error) {
+	return DiscardCommand{}, nil
+}
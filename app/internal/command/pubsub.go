@@ -0,0 +1,182 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/pubsub"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+// subscriberModeCommands are the only commands RESP2 clients may send while they have subscriptions,
+// the connection is used for pushed messages then
+var subscriberModeCommands = map[string]bool{
+	"SUBSCRIBE":    true,
+	"UNSUBSCRIBE":  true,
+	"PSUBSCRIBE":   true,
+	"PUNSUBSCRIBE": true,
+	"PING":         true,
+	"RESET":        true,
+}
+
+// SubscribeCommand subscribes channels (SUBSCRIBE) or glob-style patterns (PSUBSCRIBE)
+type SubscribeCommand struct {
+	Channels []string
+	Patterns bool
+}
+
+// UnsubscribeCommand unsubscribes channels or patterns, all of them when none is given
+type UnsubscribeCommand struct {
+	Channels []string
+	Patterns bool
+}
+
+type PublishCommand struct {
+	Channel string
+	Message string
+}
+
+// PubsubCommand introspects the Pub/Sub state: PUBSUB CHANNELS, NUMSUB and NUMPAT
+type PubsubCommand struct {
+	Subcommand string
+	Args       []string
+}
+
+// inSubscriberMode reports whether only subscriberModeCommands are allowed, RESP3 clients
+// can run any command as pushed messages are distinguishable from replies
+func (c *CommandContext) inSubscriberMode() bool {
+	return c.subscriber != nil && c.Client.Protocol() == respparser.Resp2 && c.Client.IsSubscriber()
+}
+
+// updateSubscriptions records the number of subscriptions reported by CLIENT LIST
+func (c *CommandContext) updateSubscriptions() {
+	channels, patterns := c.subscriber.Count()
+	c.Client.SetSubscriptions(channels, patterns)
+}
+
+// subscriptionReply confirms a (un)subscription, count is the number of subscriptions left to the client
+func subscriptionReply(kind string, channel string, isNull bool, count int) respparser.Push {
+	return respparser.Push{Items: []respparser.RespData{
+		respparser.BulkString{Value: kind},
+		respparser.BulkString{Value: channel, IsNull: isNull},
+		respparser.Integer{Value: count},
+	}}
+}
+
+func (c SubscribeCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	kind, subscribe := "subscribe", pubsub.Subscribe
+	if c.Patterns {
+		kind, subscribe = "psubscribe", pubsub.PSubscribe
+	}
+
+	replies := Replies{}
+	for _, channel := range c.Channels {
+		utils.Log(fmt.Sprintf("(SubscribeCommand) Client %d subscribes %q", cmdCtx.Client.ID, channel))
+		count := subscribe(cmdCtx.subscriber, channel)
+		replies.Items = append(replies.Items, subscriptionReply(kind, channel, false, count))
+	}
+	cmdCtx.updateSubscriptions()
+	return replies, nil
+}
+
+func (c UnsubscribeCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	kind, unsubscribe, subscribed := "unsubscribe", pubsub.Unsubscribe, cmdCtx.subscriber.Channels
+	if c.Patterns {
+		kind, unsubscribe, subscribed = "punsubscribe", pubsub.PUnsubscribe, cmdCtx.subscriber.Patterns
+	}
+
+	channels := c.Channels
+	if len(channels) == 0 {
+		channels = subscribed()
+	}
+
+	replies := Replies{}
+	for _, channel := range channels {
+		utils.Log(fmt.Sprintf("(UnsubscribeCommand) Client %d unsubscribes %q", cmdCtx.Client.ID, channel))
+		count := unsubscribe(cmdCtx.subscriber, channel)
+		replies.Items = append(replies.Items, subscriptionReply(kind, channel, false, count))
+	}
+	// remark: without any subscription the remaining count is confirmed with a nil channel
+	if len(replies.Items) == 0 {
+		channelCount, patternCount := cmdCtx.subscriber.Count()
+		replies.Items = append(replies.Items, subscriptionReply(kind, "", true, channelCount+patternCount))
+	}
+	cmdCtx.updateSubscriptions()
+	return replies, nil
+}
+
+func (c PublishCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	receivers := pubsub.Publish(c.Channel, c.Message)
+	return respparser.Integer{Value: receivers}, nil
+}
+
+func (c PubsubCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	utils.Log(fmt.Sprintf("(PubsubCommand) Processing PUBSUB %s", c.Subcommand))
+
+	switch c.Subcommand {
+	case "CHANNELS":
+		pattern := ""
+		if len(c.Args) == 1 {
+			pattern = c.Args[0]
+		}
+		channels := respparser.Array{Items: []respparser.RespData{}}
+		for _, channel := range pubsub.ActiveChannels(pattern) {
+			channels.Items = append(channels.Items, respparser.BulkString{Value: channel})
+		}
+		return channels, nil
+
+	case "NUMSUB":
+		counts := respparser.Array{Items: []respparser.RespData{}}
+		for _, channel := range c.Args {
+			counts.Items = append(counts.Items,
+				respparser.BulkString{Value: channel},
+				respparser.Integer{Value: pubsub.NumSub(channel)},
+			)
+		}
+		return counts, nil
+
+	case "NUMPAT":
+		return respparser.Integer{Value: pubsub.NumPat()}, nil
+
+	default:
+		return respparser.SimpleError{}, errUnknownSubcommand("PUBSUB", c.Subcommand)
+	}
+}
+
+func parseSubscribeCommand(command *Command) (SubscribeCommand, error) {
+	return SubscribeCommand{Channels: command.CommandValues}, nil
+}
+
+func parsePSubscribeCommand(command *Command) (SubscribeCommand, error) {
+	return SubscribeCommand{Channels: command.CommandValues, Patterns: true}, nil
+}
+
+func parseUnsubscribeCommand(command *Command) (UnsubscribeCommand, error) {
+	return UnsubscribeCommand{Channels: command.CommandValues}, nil
+}
+
+func parsePUnsubscribeCommand(command *Command) (UnsubscribeCommand, error) {
+	return UnsubscribeCommand{Channels: command.CommandValues, Patterns: true}, nil
+}
+
+func parsePublishCommand(command *Command) (PublishCommand, error) {
+	publishCommand := PublishCommand{
+		Channel: command.CommandValues[0],
+		Message: command.CommandValues[1],
+	}
+	return publishCommand, nil
+}
+
+func parsePubsubCommand(command *Command) (PubsubCommand, error) {
+	pubsubCommand := PubsubCommand{
+		Subcommand: strings.ToUpper(command.CommandValues[0]),
+		Args:       command.CommandValues[1:],
+	}
+
+	// remark: the minimal number of arguments is checked by the registry
+	if pubsubCommand.Subcommand == "CHANNELS" && len(pubsubCommand.Args) > 1 {
+		return PubsubCommand{}, errWrongArgs("pubsub|channels")
+	}
+	return pubsubCommand, nil
+}
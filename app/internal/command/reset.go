@@ -0,0 +1,35 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/acl"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/pubsub"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+// ResetCommand restores the connection state of a newly connected client: the transaction is discarded,
+// keys are unwatched, subscriptions are dropped and the client is deauthenticated and switched to RESP2
+type ResetCommand struct{}
+
+func (c ResetCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	utils.Log(fmt.Sprintf("(ResetCommand) Resetting client %d", cmdCtx.Client.ID))
+
+	cmdCtx.transaction = nil
+	unwatchAllKeys(cmdCtx)
+	if cmdCtx.subscriber != nil {
+		pubsub.UnsubscribeAll(cmdCtx.subscriber)
+		cmdCtx.updateSubscriptions()
+	}
+
+	cmdCtx.Client.Reset()
+	if acl.DefaultUserNoPass() {
+		cmdCtx.Client.Authenticate(acl.DefaultUserName)
+	}
+	return respparser.SimpleString{Value: "RESET"}, nil
+}
+
+func parseResetCommand(_ *Command) (ResetCommand, error) {
+	return ResetCommand{}, nil
+}
@@ -1,12 +1,11 @@
 package command
 
 import (
-	"errors"
 	"fmt"
 
-	"github.com/codecrafters-io/redis-starter-go/app/internal/respparser"
 	"github.com/codecrafters-io/redis-starter-go/app/internal/store"
 	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
 )
 
 type RPushCommand struct {
@@ -14,7 +13,7 @@ type RPushCommand struct {
 	Values []string
 }
 
-func (c RPushCommand) Process() (respparser.RespData, error) {
+func (c RPushCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
 	utils.Log(fmt.Sprintf("(RPushCommand) Processing list with key %s", c.Key))
 
 	listStoreValue := store.ListStoreValue{
@@ -22,16 +21,16 @@ func (c RPushCommand) Process() (respparser.RespData, error) {
 		Values: c.Values,
 	}
 
-	numOfAddedElements := store.ListStoreLive.Append(listStoreValue)
+	numOfAddedElements, err := store.AppendList(listStoreValue)
+	if err != nil {
+		return respparser.SimpleError{}, err
+	}
+	cmdCtx.touch(c.Key)
 	respInt := respparser.Integer{Value: numOfAddedElements}
 	return respInt, nil
 }
 
 func parseRPushCommand(command *Command) (RPushCommand, error) {
-	if command.CommandType != "RPUSH" {
-		return RPushCommand{}, errors.New("Not a RPUSH")
-	}
-
 	rPushCommand := RPushCommand{
 		// remark: length is len of command values - 1 (first element is always list key)
 		Values: make([]string, len(command.CommandValues)-1),
@@ -0,0 +1,110 @@
+package command
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/store"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+func TestParseSetCommand(t *testing.T) {
+	var tests = []struct {
+		name    string
+		args    []string
+		want    SetCommand
+		wantErr string
+	}{
+		{
+			name: "Without options the key shouldn't expire",
+			args: []string{"key", "value"},
+			want: SetCommand{Key: "key", Value: "value"},
+		},
+		{
+			name: "Options should be case insensitive",
+			args: []string{"key", "value", "nx", "px", "30000"},
+			want: SetCommand{Key: "key", Value: "value", Condition: store.SetIfNotExists, TtlMillis: 30000},
+		},
+		{
+			name: "EX should be converted to milliseconds",
+			args: []string{"key", "value", "XX", "GET", "Ex", "10"},
+			want: SetCommand{Key: "key", Value: "value", Condition: store.SetIfExists, Get: true, TtlMillis: 10000},
+		},
+		{
+			name: "EXAT and PXAT should set absolute expiry",
+			args: []string{"key", "value", "EXAT", "1700000000"},
+			want: SetCommand{Key: "key", Value: "value", ExpireAt: time.UnixMilli(1700000000000)},
+		},
+		{
+			name: "KEEPTTL",
+			args: []string{"key", "value", "keepttl", "get"},
+			want: SetCommand{Key: "key", Value: "value", KeepTtl: true, Get: true},
+		},
+		{name: "NX and XX conflict", args: []string{"key", "value", "NX", "XX"}, wantErr: "ERR syntax error"},
+		{name: "Two expiries conflict", args: []string{"key", "value", "EX", "1", "PX", "1"}, wantErr: "ERR syntax error"},
+		{name: "Expiry and KEEPTTL conflict", args: []string{"key", "value", "PXAT", "1", "KEEPTTL"}, wantErr: "ERR syntax error"},
+		{name: "Missing expiry value", args: []string{"key", "value", "EX"}, wantErr: "ERR syntax error"},
+		{name: "Unknown option", args: []string{"key", "value", "FOREVER"}, wantErr: "ERR syntax error"},
+		{name: "Expiry not an integer", args: []string{"key", "value", "PX", "1.5"}, wantErr: "ERR value is not an integer or out of range"},
+		{name: "Expiry not positive", args: []string{"key", "value", "EX", "0"}, wantErr: "ERR invalid expire time in 'set' command"},
+		{name: "Expiry overflow", args: []string{"key", "value", "EX", strconv.FormatInt(1<<62, 10)}, wantErr: "ERR invalid expire time in 'set' command"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ans, err := parseSetCommand(&Command{CommandType: "SET", CommandValues: tt.args})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("ERROR got %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+			}
+			if ans != tt.want {
+				t.Errorf("ERROR got %+v, want %+v", ans, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetOptions(t *testing.T) {
+	nilReply := "" // remark: null bulk strings have empty value
+	var tests = []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"SET", "set-test:lock", "owner-1", "NX", "PX", "30000"}, want: "OK"},
+		{args: []string{"SET", "set-test:lock", "owner-2", "NX", "PX", "30000"}, want: nilReply},
+		{args: []string{"GET", "set-test:lock"}, want: "owner-1"},
+		{args: []string{"SET", "set-test:missing", "value", "XX"}, want: nilReply},
+		{args: []string{"GET", "set-test:missing"}, want: nilReply},
+		{args: []string{"SET", "set-test:lock", "owner-3", "XX", "GET", "KEEPTTL"}, want: "owner-1"},
+		{args: []string{"SET", "set-test:new", "value", "GET"}, want: nilReply},
+		{args: []string{"SET", "set-test:new", "other", "NX", "GET"}, want: "value"},
+		{args: []string{"GET", "set-test:new"}, want: "value"},
+		{args: []string{"SET", "set-test:past", "value", "PXAT", "1"}, want: "OK"},
+		{args: []string{"GET", "set-test:past"}, want: nilReply},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			if reply := runTestCommand(tt.args...); reply.String() != tt.want {
+				t.Errorf("ERROR got %q, want %q", reply.String(), tt.want)
+			}
+		})
+	}
+
+	if lock, _, _ := store.Get("set-test:lock"); lock.Expire == nil || time.Until(*lock.Expire) < 29*time.Second {
+		t.Errorf("ERROR got expiry %v, want the one kept by KEEPTTL", lock.Expire)
+	}
+	if value, _, _ := store.Get("set-test:new"); value.Expire != nil {
+		t.Errorf("ERROR got expiry %v, want none", value.Expire)
+	}
+	if reply := runTestCommand("SET", "set-test:plain", "value", "XX"); reply.(respparser.BulkString).IsNull != true {
+		t.Errorf("ERROR got %v, want null reply", reply)
+	}
+}
@@ -0,0 +1,66 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+type ShutdownOptions struct {
+	NoSave bool
+	Save   bool
+	Now    bool // don't wait for in-flight commands of other clients
+	Force  bool // ignore errors that would normally prevent the server to exit
+}
+
+type ShutdownCommand struct {
+	Options ShutdownOptions
+}
+
+var shutdownHandler func(ShutdownOptions) error
+
+// SetShutdownHandler registers the function that shuts the server down on SHUTDOWN command
+func SetShutdownHandler(handler func(ShutdownOptions) error) {
+	shutdownHandler = handler
+}
+
+func (c ShutdownCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	utils.LogWarning(fmt.Sprintf("(ShutdownCommand) User requested shutdown: %+v", c.Options))
+
+	if shutdownHandler == nil {
+		return respparser.SimpleError{}, Errorf(CodeErr, "Errors trying to SHUTDOWN. Check logs.")
+	}
+
+	if err := shutdownHandler(c.Options); err != nil {
+		utils.LogWarning(fmt.Sprintf("(ShutdownCommand) Shutdown failed: %s", err.Error()))
+		return respparser.SimpleError{}, Errorf(CodeErr, "Errors trying to SHUTDOWN. Check logs.")
+	}
+
+	// remark: on success the connection is closed without any reply
+	return NoReply{}, nil
+}
+
+func parseShutdownCommand(command *Command) (ShutdownCommand, error) {
+	shutdownCommand := ShutdownCommand{}
+	for _, arg := range command.CommandValues {
+		switch strings.ToUpper(arg) {
+		case "NOSAVE":
+			shutdownCommand.Options.NoSave = true
+		case "SAVE":
+			shutdownCommand.Options.Save = true
+		case "NOW":
+			shutdownCommand.Options.Now = true
+		case "FORCE":
+			shutdownCommand.Options.Force = true
+		default:
+			return ShutdownCommand{}, errSyntax
+		}
+	}
+
+	if shutdownCommand.Options.Save && shutdownCommand.Options.NoSave {
+		return ShutdownCommand{}, errSyntax
+	}
+	return shutdownCommand, nil
+}
@@ -0,0 +1,656 @@
+package command
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/acl"
+)
+
+// parseFunc parses arguments of a command into its handler
+type parseFunc func(command *Command) (CommandHandler[string], error)
+
+// handlerOf adapts parsers returning concrete command types to parseFunc
+func handlerOf[T CommandHandler[string]](parse func(command *Command) (T, error)) parseFunc {
+	return func(command *Command) (CommandHandler[string], error) {
+		return parse(command)
+	}
+}
+
+// commandArg documents an argument of a command as reported by COMMAND DOCS
+type commandArg struct {
+	Name      string
+	Type      string // key, string, integer, double, pattern, unix-time, pure-token, oneof or block
+	Token     string // keyword preceding the argument, e.g. PX
+	Optional  bool
+	Multiple  bool
+	Arguments []commandArg // alternatives of oneof and parts of block arguments
+}
+
+// commandSpec describes a command: its arity, flags, ACL categories, key positions and documentation.
+// Arity counts the command name (and the subcommand name), negative arity is the minimal number of arguments.
+// Key positions follow Redis conventions, the command name itself is at position 0.
+type commandSpec struct {
+	Parse      parseFunc // nil for subcommands, those are parsed by their container command
+	Arity      int
+	Flags      []string // write, readonly, denyoom, admin, noscript, blocking, loading, stale, fast, no_auth, pubsub, no_multi
+	Categories []string // categories implied by flags are added by aclCategories
+	FirstKey   int
+	LastKey    int // negative values are counted from the last argument
+	KeyStep    int
+	GetKeys    func(args []string) []string // optional, for commands with keys at variable positions
+
+	Summary    string
+	Since      string
+	Group      string
+	Complexity string
+	Arguments  []commandArg
+}
+
+var (
+	keyArg     = commandArg{Name: "key", Type: "key"}
+	connFlags  = []string{"noscript", "loading", "stale"}
+	subFlags   = []string{"pubsub", "noscript", "loading", "stale", "no_multi"}
+	adminFlags = []string{"admin", "noscript", "loading", "stale"}
+
+	expireConditionArg = commandArg{Name: "condition", Type: "oneof", Optional: true, Arguments: []commandArg{
+		{Name: "nx", Type: "pure-token", Token: "NX"},
+		{Name: "xx", Type: "pure-token", Token: "XX"},
+		{Name: "gt", Type: "pure-token", Token: "GT"},
+		{Name: "lt", Type: "pure-token", Token: "LT"},
+	}}
+)
+
+var commandSpecs = map[string]commandSpec{
+	"PING": {
+		Parse: handlerOf(parsePingCommand), Arity: -1, Flags: []string{"fast"}, Categories: []string{"connection"},
+		Summary: "Returns the server's liveliness response.", Since: "1.0.0", Group: "connection", Complexity: "O(1)",
+		Arguments: []commandArg{{Name: "message", Type: "string", Optional: true}},
+	},
+	"ECHO": {
+		Parse: handlerOf(parseEchoCommand), Arity: 2, Flags: []string{"fast"}, Categories: []string{"connection"},
+		Summary: "Returns the given string.", Since: "1.0.0", Group: "connection", Complexity: "O(1)",
+		Arguments: []commandArg{{Name: "message", Type: "string"}},
+	},
+	"AUTH": {
+		Parse: handlerOf(parseAuthCommand), Arity: -2, Flags: []string{"noscript", "loading", "stale", "fast", "no_auth"}, Categories: []string{"connection"},
+		Summary: "Authenticates the connection.", Since: "1.0.0", Group: "connection", Complexity: "O(N) where N is the number of passwords defined for the user",
+		Arguments: []commandArg{{Name: "username", Type: "string", Optional: true}, {Name: "password", Type: "string"}},
+	},
+	"HELLO": {
+		Parse: handlerOf(parseHelloCommand), Arity: -1, Flags: []string{"noscript", "loading", "stale", "fast", "no_auth"}, Categories: []string{"connection"},
+		Summary: "Handshakes with the Redis server.", Since: "6.0.0", Group: "connection", Complexity: "O(1)",
+		Arguments: []commandArg{{Name: "arguments", Type: "block", Optional: true, Arguments: []commandArg{
+			{Name: "protover", Type: "integer"},
+			{Name: "auth", Type: "block", Token: "AUTH", Optional: true, Arguments: []commandArg{{Name: "username", Type: "string"}, {Name: "password", Type: "string"}}},
+			{Name: "clientname", Type: "string", Token: "SETNAME", Optional: true},
+		}}},
+	},
+	"GET": {
+		Parse: handlerOf(parseGetCommand), Arity: 2, Flags: []string{"readonly", "fast"}, Categories: []string{"string"},
+		FirstKey: 1, LastKey: 1, KeyStep: 1,
+		Summary: "Returns the string value of a key.", Since: "1.0.0", Group: "string", Complexity: "O(1)",
+		Arguments: []commandArg{keyArg},
+	},
+	"SET": {
+		Parse: handlerOf(parseSetCommand), Arity: -3, Flags: []string{"write", "denyoom"}, Categories: []string{"string"},
+		FirstKey: 1, LastKey: 1, KeyStep: 1,
+		Summary: "Sets the string value of a key, ignoring its type. The key is created if it doesn't exist.", Since: "1.0.0", Group: "string", Complexity: "O(1)",
+		Arguments: []commandArg{
+			keyArg,
+			{Name: "value", Type: "string"},
+			{Name: "condition", Type: "oneof", Optional: true, Arguments: []commandArg{
+				{Name: "nx", Type: "pure-token", Token: "NX"},
+				{Name: "xx", Type: "pure-token", Token: "XX"},
+			}},
+			{Name: "get", Type: "pure-token", Token: "GET", Optional: true},
+			{Name: "expiration", Type: "oneof", Optional: true, Arguments: []commandArg{
+				{Name: "seconds", Type: "integer", Token: "EX"},
+				{Name: "milliseconds", Type: "integer", Token: "PX"},
+				{Name: "unix-time-seconds", Type: "unix-time", Token: "EXAT"},
+				{Name: "unix-time-milliseconds", Type: "unix-time", Token: "PXAT"},
+				{Name: "keepttl", Type: "pure-token", Token: "KEEPTTL"},
+			}},
+		},
+	},
+	"TYPE": {
+		Parse: handlerOf(parseTypeCommand), Arity: 2, Flags: []string{"readonly", "fast"}, Categories: []string{"keyspace"},
+		FirstKey: 1, LastKey: 1, KeyStep: 1,
+		Summary: "Determines the type of value stored at a key.", Since: "1.0.0", Group: "generic", Complexity: "O(1)",
+		Arguments: []commandArg{keyArg},
+	},
+	"DEL": {
+		Parse: handlerOf(parseDelCommand), Arity: -2, Flags: []string{"write"}, Categories: []string{"keyspace"},
+		FirstKey: 1, LastKey: -1, KeyStep: 1,
+		Summary: "Deletes one or more keys.", Since: "1.0.0", Group: "generic", Complexity: "O(N) where N is the number of keys that will be removed.",
+		Arguments: []commandArg{{Name: "key", Type: "key", Multiple: true}},
+	},
+	"UNLINK": {
+		Parse: handlerOf(parseDelCommand), Arity: -2, Flags: []string{"write", "fast"}, Categories: []string{"keyspace"},
+		FirstKey: 1, LastKey: -1, KeyStep: 1,
+		Summary: "Asynchronously deletes one or more keys.", Since: "4.0.0", Group: "generic", Complexity: "O(1) for each key removed regardless of its size.",
+		Arguments: []commandArg{{Name: "key", Type: "key", Multiple: true}},
+	},
+	"EXISTS": {
+		Parse: handlerOf(parseExistsCommand), Arity: -2, Flags: []string{"readonly", "fast"}, Categories: []string{"keyspace"},
+		FirstKey: 1, LastKey: -1, KeyStep: 1,
+		Summary: "Determines whether one or more keys exist.", Since: "1.0.0", Group: "generic", Complexity: "O(N) where N is the number of keys to check.",
+		Arguments: []commandArg{{Name: "key", Type: "key", Multiple: true}},
+	},
+	"TOUCH": {
+		Parse: handlerOf(parseExistsCommand), Arity: -2, Flags: []string{"readonly", "fast"}, Categories: []string{"keyspace"},
+		FirstKey: 1, LastKey: -1, KeyStep: 1,
+		Summary: "Returns the number of existing keys out of those specified after updating the time they were last accessed.", Since: "3.2.1", Group: "generic", Complexity: "O(N) where N is the number of keys that will be touched.",
+		Arguments: []commandArg{{Name: "key", Type: "key", Multiple: true}},
+	},
+	"RENAME": {
+		Parse: handlerOf(parseRenameCommand), Arity: 3, Flags: []string{"write"}, Categories: []string{"keyspace"},
+		FirstKey: 1, LastKey: 2, KeyStep: 1,
+		Summary: "Renames a key and overwrites the destination.", Since: "1.0.0", Group: "generic", Complexity: "O(1)",
+		Arguments: []commandArg{keyArg, {Name: "newkey", Type: "key"}},
+	},
+	"RENAMENX": {
+		Parse: handlerOf(parseRenameCommand), Arity: 3, Flags: []string{"write", "fast"}, Categories: []string{"keyspace"},
+		FirstKey: 1, LastKey: 2, KeyStep: 1,
+		Summary: "Renames a key only when the target key name doesn't exist.", Since: "1.0.0", Group: "generic", Complexity: "O(1)",
+		Arguments: []commandArg{keyArg, {Name: "newkey", Type: "key"}},
+	},
+	"COPY": {
+		Parse: handlerOf(parseCopyCommand), Arity: -3, Flags: []string{"write", "denyoom"}, Categories: []string{"keyspace"},
+		FirstKey: 1, LastKey: 2, KeyStep: 1,
+		Summary: "Copies the value of a key to a new key.", Since: "6.2.0", Group: "generic", Complexity: "O(N) worst case for collections, where N is the number of nested items. O(1) for string values.",
+		Arguments: []commandArg{
+			{Name: "source", Type: "key"},
+			{Name: "destination", Type: "key"},
+			{Name: "destination-db", Type: "integer", Token: "DB", Optional: true},
+			{Name: "replace", Type: "pure-token", Token: "REPLACE", Optional: true},
+		},
+	},
+	"RANDOMKEY": {
+		Parse: handlerOf(parseRandomKeyCommand), Arity: 1, Flags: []string{"readonly"}, Categories: []string{"keyspace"},
+		Summary: "Returns a random key name from the database.", Since: "1.0.0", Group: "generic", Complexity: "O(1)",
+	},
+	"EXPIRE": {
+		Parse: handlerOf(parseExpireCommand), Arity: -3, Flags: []string{"write", "fast"}, Categories: []string{"keyspace"},
+		FirstKey: 1, LastKey: 1, KeyStep: 1,
+		Summary: "Sets the expiration time of a key in seconds.", Since: "1.0.0", Group: "generic", Complexity: "O(1)",
+		Arguments: []commandArg{keyArg, {Name: "seconds", Type: "integer"}, expireConditionArg},
+	},
+	"PEXPIRE": {
+		Parse: handlerOf(parseExpireCommand), Arity: -3, Flags: []string{"write", "fast"}, Categories: []string{"keyspace"},
+		FirstKey: 1, LastKey: 1, KeyStep: 1,
+		Summary: "Sets the expiration time of a key in milliseconds.", Since: "2.6.0", Group: "generic", Complexity: "O(1)",
+		Arguments: []commandArg{keyArg, {Name: "milliseconds", Type: "integer"}, expireConditionArg},
+	},
+	"EXPIREAT": {
+		Parse: handlerOf(parseExpireCommand), Arity: -3, Flags: []string{"write", "fast"}, Categories: []string{"keyspace"},
+		FirstKey: 1, LastKey: 1, KeyStep: 1,
+		Summary: "Sets the expiration time of a key to a Unix timestamp.", Since: "1.2.0", Group: "generic", Complexity: "O(1)",
+		Arguments: []commandArg{keyArg, {Name: "unix-time-seconds", Type: "unix-time"}, expireConditionArg},
+	},
+	"PEXPIREAT": {
+		Parse: handlerOf(parseExpireCommand), Arity: -3, Flags: []string{"write", "fast"}, Categories: []string{"keyspace"},
+		FirstKey: 1, LastKey: 1, KeyStep: 1,
+		Summary: "Sets the expiration time of a key to a Unix milliseconds timestamp.", Since: "2.6.0", Group: "generic", Complexity: "O(1)",
+		Arguments: []commandArg{keyArg, {Name: "unix-time-milliseconds", Type: "unix-time"}, expireConditionArg},
+	},
+	"TTL": {
+		Parse: handlerOf(parseTtlCommand), Arity: 2, Flags: []string{"readonly", "fast"}, Categories: []string{"keyspace"},
+		FirstKey: 1, LastKey: 1, KeyStep: 1,
+		Summary: "Returns the expiration time in seconds of a key.", Since: "1.0.0", Group: "generic", Complexity: "O(1)",
+		Arguments: []commandArg{keyArg},
+	},
+	"PTTL": {
+		Parse: handlerOf(parseTtlCommand), Arity: 2, Flags: []string{"readonly", "fast"}, Categories: []string{"keyspace"},
+		FirstKey: 1, LastKey: 1, KeyStep: 1,
+		Summary: "Returns the expiration time in milliseconds of a key.", Since: "2.6.0", Group: "generic", Complexity: "O(1)",
+		Arguments: []commandArg{keyArg},
+	},
+	"EXPIRETIME": {
+		Parse: handlerOf(parseExpireTimeCommand), Arity: 2, Flags: []string{"readonly", "fast"}, Categories: []string{"keyspace"},
+		FirstKey: 1, LastKey: 1, KeyStep: 1,
+		Summary: "Returns the expiration time of a key as a Unix timestamp.", Since: "7.0.0", Group: "generic", Complexity: "O(1)",
+		Arguments: []commandArg{keyArg},
+	},
+	"PEXPIRETIME": {
+		Parse: handlerOf(parseExpireTimeCommand), Arity: 2, Flags: []string{"readonly", "fast"}, Categories: []string{"keyspace"},
+		FirstKey: 1, LastKey: 1, KeyStep: 1,
+		Summary: "Returns the expiration time of a key as a Unix milliseconds timestamp.", Since: "7.0.0", Group: "generic", Complexity: "O(1)",
+		Arguments: []commandArg{keyArg},
+	},
+	"PERSIST": {
+		Parse: handlerOf(parsePersistCommand), Arity: 2, Flags: []string{"write", "fast"}, Categories: []string{"keyspace"},
+		FirstKey: 1, LastKey: 1, KeyStep: 1,
+		Summary: "Removes the expiration time of a key.", Since: "2.2.0", Group: "generic", Complexity: "O(1)",
+		Arguments: []commandArg{keyArg},
+	},
+	"KEYS": {
+		Parse: handlerOf(parseKeysCommand), Arity: 2, Flags: []string{"readonly"}, Categories: []string{"keyspace", "dangerous"},
+		Summary: "Returns all key names that match a pattern.", Since: "1.0.0", Group: "generic", Complexity: "O(N) with N being the number of keys in the database, under the assumption that the key names in the database and the given pattern have limited length.",
+		Arguments: []commandArg{{Name: "pattern", Type: "pattern"}},
+	},
+	"SCAN": {
+		Parse: handlerOf(parseScanCommand), Arity: -2, Flags: []string{"readonly"}, Categories: []string{"keyspace"},
+		Summary: "Iterates over the key names in the database.", Since: "2.8.0", Group: "generic", Complexity: "O(1) for every call. O(N) for a complete iteration, including enough command calls for the cursor to return back to 0. N is the number of elements inside the collection.",
+		Arguments: []commandArg{
+			{Name: "cursor", Type: "integer"},
+			{Name: "pattern", Type: "pattern", Token: "MATCH", Optional: true},
+			{Name: "count", Type: "integer", Token: "COUNT", Optional: true},
+			{Name: "type", Type: "string", Token: "TYPE", Optional: true},
+		},
+	},
+	"XADD": {
+		Parse: handlerOf(parseXAddCommand), Arity: -5, Flags: []string{"write", "denyoom", "fast"}, Categories: []string{"stream"},
+		FirstKey: 1, LastKey: 1, KeyStep: 1,
+		Summary: "Appends a new message to a stream. Creates the key if it doesn't exist.", Since: "5.0.0", Group: "stream", Complexity: "O(1) when adding a new entry",
+		Arguments: []commandArg{keyArg, {Name: "id", Type: "string"}, {Name: "data", Type: "block", Multiple: true, Arguments: []commandArg{
+			{Name: "field", Type: "string"},
+			{Name: "value", Type: "string"},
+		}}},
+	},
+	"XRANGE": {
+		Parse: handlerOf(parseXRangeCommand), Arity: -4, Flags: []string{"readonly"}, Categories: []string{"stream"},
+		FirstKey: 1, LastKey: 1, KeyStep: 1,
+		Summary: "Returns the messages from a stream within a range of IDs.", Since: "5.0.0", Group: "stream", Complexity: "O(N) with N being the number of elements being returned",
+		Arguments: []commandArg{keyArg, {Name: "start", Type: "string"}, {Name: "end", Type: "string"}},
+	},
+	"XREAD": {
+		Parse: handlerOf(parseXReadCommand), Arity: -4, Flags: []string{"readonly", "blocking"}, Categories: []string{"stream"},
+		GetKeys: xreadKeys,
+		Summary: "Returns messages from multiple streams with IDs greater than the ones requested. Blocks until a message is available otherwise.", Since: "5.0.0", Group: "stream", Complexity: "O(1) for each stream mentioned",
+		Arguments: []commandArg{
+			{Name: "milliseconds", Type: "integer", Token: "BLOCK", Optional: true},
+			{Name: "streams", Type: "block", Token: "STREAMS", Arguments: []commandArg{
+				{Name: "key", Type: "key", Multiple: true},
+				{Name: "id", Type: "string", Multiple: true},
+			}},
+		},
+	},
+	"RPUSH": {
+		Parse: handlerOf(parseRPushCommand), Arity: -3, Flags: []string{"write", "denyoom", "fast"}, Categories: []string{"list"},
+		FirstKey: 1, LastKey: 1, KeyStep: 1,
+		Summary: "Appends one or more elements to a list. Creates the key if it doesn't exist.", Since: "1.0.0", Group: "list", Complexity: "O(1) for each element added",
+		Arguments: []commandArg{keyArg, {Name: "element", Type: "string", Multiple: true}},
+	},
+	"LRANGE": {
+		Parse: handlerOf(parseLRangeCommand), Arity: 4, Flags: []string{"readonly"}, Categories: []string{"list"},
+		FirstKey: 1, LastKey: 1, KeyStep: 1,
+		Summary: "Returns a range of elements from a list.", Since: "1.0.0", Group: "list", Complexity: "O(S+N) where S is the distance of start offset from HEAD and N is the number of elements in the specified range",
+		Arguments: []commandArg{keyArg, {Name: "start", Type: "integer"}, {Name: "stop", Type: "integer"}},
+	},
+	"SHUTDOWN": {
+		Parse: handlerOf(parseShutdownCommand), Arity: -1, Flags: []string{"admin", "noscript", "loading", "stale"},
+		Summary: "Synchronously saves the database(s) to disk and shuts down the Redis server.", Since: "1.0.0", Group: "server", Complexity: "O(N) when saving, where N is the total number of keys in all databases",
+		Arguments: []commandArg{
+			{Name: "save-selector", Type: "oneof", Optional: true, Arguments: []commandArg{{Name: "nosave", Type: "pure-token", Token: "NOSAVE"}, {Name: "save", Type: "pure-token", Token: "SAVE"}}},
+			{Name: "now", Type: "pure-token", Token: "NOW", Optional: true},
+			{Name: "force", Type: "pure-token", Token: "FORCE", Optional: true},
+		},
+	},
+
+	"MULTI": {
+		Parse: handlerOf(parseMultiCommand), Arity: 1, Flags: []string{"noscript", "loading", "stale", "fast"}, Categories: []string{"transaction"},
+		Summary: "Starts a transaction.", Since: "1.2.0", Group: "transactions", Complexity: "O(1)",
+	},
+	"EXEC": {
+		Parse: handlerOf(parseExecCommand), Arity: 1, Flags: []string{"noscript", "loading", "stale"}, Categories: []string{"transaction"},
+		Summary: "Executes all commands in a transaction.", Since: "1.2.0", Group: "transactions", Complexity: "Depends on commands in the transaction",
+	},
+	"DISCARD": {
+		Parse: handlerOf(parseDiscardCommand), Arity: 1, Flags: []string{"noscript", "loading", "stale", "fast"}, Categories: []string{"transaction"},
+		Summary: "Discards a transaction.", Since: "2.0.0", Group: "transactions", Complexity: "O(N), when N is the number of queued commands",
+	},
+	"WATCH": {
+		Parse: handlerOf(parseWatchCommand), Arity: -2, Flags: []string{"noscript", "loading", "stale", "fast"}, Categories: []string{"transaction"},
+		FirstKey: 1, LastKey: -1, KeyStep: 1,
+		Summary: "Monitors changes to keys to determine the execution of a transaction.", Since: "2.2.0", Group: "transactions", Complexity: "O(1) for every key.",
+		Arguments: []commandArg{{Name: "key", Type: "key", Multiple: true}},
+	},
+	"UNWATCH": {
+		Parse: handlerOf(parseUnwatchCommand), Arity: 1, Flags: []string{"noscript", "loading", "stale", "fast"}, Categories: []string{"transaction"},
+		Summary: "Forgets about watched keys of a transaction.", Since: "2.2.0", Group: "transactions", Complexity: "O(1)",
+	},
+
+	"RESET": {
+		Parse: handlerOf(parseResetCommand), Arity: 1, Flags: []string{"noscript", "loading", "stale", "fast", "no_auth"}, Categories: []string{"connection"},
+		Summary: "Resets the connection.", Since: "6.2.0", Group: "connection", Complexity: "O(1)",
+	},
+
+	"SUBSCRIBE": {
+		Parse: handlerOf(parseSubscribeCommand), Arity: -2, Flags: subFlags,
+		Summary: "Listens for messages published to channels.", Since: "2.0.0", Group: "pubsub", Complexity: "O(N) where N is the number of channels to subscribe to.",
+		Arguments: []commandArg{{Name: "channel", Type: "string", Multiple: true}},
+	},
+	"UNSUBSCRIBE": {
+		Parse: handlerOf(parseUnsubscribeCommand), Arity: -1, Flags: subFlags,
+		Summary: "Stops listening to messages posted to channels.", Since: "2.0.0", Group: "pubsub", Complexity: "O(N) where N is the number of channels to unsubscribe.",
+		Arguments: []commandArg{{Name: "channel", Type: "string", Optional: true, Multiple: true}},
+	},
+	"PSUBSCRIBE": {
+		Parse: handlerOf(parsePSubscribeCommand), Arity: -2, Flags: subFlags,
+		Summary: "Listens for messages published to channels that match one or more patterns.", Since: "2.0.0", Group: "pubsub", Complexity: "O(N) where N is the number of patterns to subscribe to.",
+		Arguments: []commandArg{{Name: "pattern", Type: "pattern", Multiple: true}},
+	},
+	"PUNSUBSCRIBE": {
+		Parse: handlerOf(parsePUnsubscribeCommand), Arity: -1, Flags: subFlags,
+		Summary: "Stops listening to messages published to channels that match one or more patterns.", Since: "2.0.0", Group: "pubsub", Complexity: "O(N) where N is the number of patterns to unsubscribe.",
+		Arguments: []commandArg{{Name: "pattern", Type: "pattern", Optional: true, Multiple: true}},
+	},
+	"PUBLISH": {
+		Parse: handlerOf(parsePublishCommand), Arity: 3, Flags: []string{"pubsub", "loading", "stale", "fast"},
+		Summary: "Posts a message to a channel.", Since: "2.0.0", Group: "pubsub", Complexity: "O(N+M) where N is the number of clients subscribed to the receiving channel and M is the total number of subscribed patterns (by any client).",
+		Arguments: []commandArg{{Name: "channel", Type: "string"}, {Name: "message", Type: "string"}},
+	},
+
+	// container commands, their subcommands are specified as CONTAINER|SUBCOMMAND
+	"CONFIG": {
+		Parse: handlerOf(parseConfigCommand), Arity: -2,
+		Summary: "A container for server configuration commands.", Since: "2.0.0", Group: "server", Complexity: "Depends on subcommand.",
+	},
+	"ACL": {
+		Parse: handlerOf(parseAclCommand), Arity: -2,
+		Summary: "A container for Access List Control commands.", Since: "6.0.0", Group: "server", Complexity: "Depends on subcommand.",
+	},
+	"CLIENT": {
+		Parse: handlerOf(parseClientCommand), Arity: -2,
+		Summary: "A container for client connection commands.", Since: "2.4.0", Group: "connection", Complexity: "Depends on subcommand.",
+	},
+	"PUBSUB": {
+		Parse: handlerOf(parsePubsubCommand), Arity: -2,
+		Summary: "A container for Pub/Sub commands.", Since: "2.8.0", Group: "pubsub", Complexity: "Depends on subcommand.",
+	},
+	"COMMAND": {
+		Parse: handlerOf(parseCommandCommand), Arity: -1, Flags: []string{"loading", "stale"}, Categories: []string{"connection"},
+		Summary: "Returns detailed information about all commands.", Since: "2.8.13", Group: "server", Complexity: "O(N) where N is the total number of Redis commands",
+	},
+
+	"CONFIG|GET": {
+		Arity: -3, Flags: adminFlags,
+		Summary: "Returns the effective values of configuration parameters.", Since: "2.0.0", Group: "server", Complexity: "O(N) when N is the number of configuration parameters provided",
+		Arguments: []commandArg{{Name: "parameter", Type: "string", Multiple: true}},
+	},
+	"CONFIG|SET": {
+		Arity: -4, Flags: adminFlags,
+		Summary: "Sets configuration parameters in-flight.", Since: "2.0.0", Group: "server", Complexity: "O(N) when N is the number of configuration parameters provided",
+		Arguments: []commandArg{{Name: "data", Type: "block", Multiple: true, Arguments: []commandArg{{Name: "parameter", Type: "string"}, {Name: "value", Type: "string"}}}},
+	},
+	"CONFIG|RESETSTAT": {
+		Arity: 2, Flags: adminFlags,
+		Summary: "Resets the server's statistics.", Since: "2.0.0", Group: "server", Complexity: "O(1)",
+	},
+	"CONFIG|REWRITE": {
+		Arity: 2, Flags: adminFlags,
+		Summary: "Persists the effective configuration to file.", Since: "2.8.0", Group: "server", Complexity: "O(1)",
+	},
+
+	"ACL|CAT": {
+		Arity: -2, Flags: connFlags,
+		Summary: "Lists the ACL categories, or the commands inside a category.", Since: "6.0.0", Group: "server", Complexity: "O(1) since the categories and commands are a fixed set.",
+		Arguments: []commandArg{{Name: "category", Type: "string", Optional: true}},
+	},
+	"ACL|DELUSER": {
+		Arity: -3, Flags: adminFlags,
+		Summary: "Deletes ACL users, and terminates their connections.", Since: "6.0.0", Group: "server", Complexity: "O(1) amortized time considering the typical user.",
+		Arguments: []commandArg{{Name: "username", Type: "string", Multiple: true}},
+	},
+	"ACL|GETUSER": {
+		Arity: 3, Flags: adminFlags,
+		Summary: "Lists the ACL rules of a user.", Since: "6.0.0", Group: "server", Complexity: "O(N). Where N is the number of password, command and pattern rules that the user has.",
+		Arguments: []commandArg{{Name: "username", Type: "string"}},
+	},
+	"ACL|LIST": {
+		Arity: 2, Flags: adminFlags,
+		Summary: "Dumps the effective rules in ACL file format.", Since: "6.0.0", Group: "server", Complexity: "O(N). Where N is the number of configured users.",
+	},
+	"ACL|LOAD": {
+		Arity: 2, Flags: adminFlags,
+		Summary: "Reloads the rules from the configured ACL file.", Since: "6.0.0", Group: "server", Complexity: "O(N). Where N is the number of configured users.",
+	},
+	"ACL|LOG": {
+		Arity: -2, Flags: adminFlags,
+		Summary: "Lists recent security events generated due to ACL rules.", Since: "6.0.0", Group: "server", Complexity: "O(N) with N being the number of entries shown.",
+		Arguments: []commandArg{{Name: "operation", Type: "oneof", Optional: true, Arguments: []commandArg{{Name: "count", Type: "integer"}, {Name: "reset", Type: "pure-token", Token: "RESET"}}}},
+	},
+	"ACL|SAVE": {
+		Arity: 2, Flags: adminFlags,
+		Summary: "Saves the effective ACL rules in the configured ACL file.", Since: "6.0.0", Group: "server", Complexity: "O(N). Where N is the number of configured users.",
+	},
+	"ACL|SETUSER": {
+		Arity: -3, Flags: adminFlags,
+		Summary: "Creates and modifies an ACL user and its rules.", Since: "6.0.0", Group: "server", Complexity: "O(N). Where N is the number of rules provided.",
+		Arguments: []commandArg{{Name: "username", Type: "string"}, {Name: "rule", Type: "string", Optional: true, Multiple: true}},
+	},
+	"ACL|USERS": {
+		Arity: 2, Flags: adminFlags,
+		Summary: "Lists all ACL users.", Since: "6.0.0", Group: "server", Complexity: "O(N). Where N is the number of configured users.",
+	},
+	"ACL|WHOAMI": {
+		Arity: 2, Flags: connFlags,
+		Summary: "Returns the authenticated username of the current connection.", Since: "6.0.0", Group: "server", Complexity: "O(1)",
+	},
+
+	"CLIENT|ID": {
+		Arity: 2, Flags: connFlags, Categories: []string{"connection"},
+		Summary: "Returns the unique client ID of the connection.", Since: "5.0.0", Group: "connection", Complexity: "O(1)",
+	},
+	"CLIENT|INFO": {
+		Arity: 2, Flags: connFlags, Categories: []string{"connection"},
+		Summary: "Returns information about the connection.", Since: "6.2.0", Group: "connection", Complexity: "O(1)",
+	},
+	"CLIENT|LIST": {
+		Arity: -2, Flags: adminFlags, Categories: []string{"connection"},
+		Summary: "Lists open connections.", Since: "2.4.0", Group: "connection", Complexity: "O(N) where N is the number of client connections",
+		Arguments: []commandArg{
+			{Name: "client-type", Type: "oneof", Token: "TYPE", Optional: true, Arguments: []commandArg{
+				{Name: "normal", Type: "pure-token", Token: "NORMAL"},
+				{Name: "master", Type: "pure-token", Token: "MASTER"},
+				{Name: "replica", Type: "pure-token", Token: "REPLICA"},
+				{Name: "pubsub", Type: "pure-token", Token: "PUBSUB"},
+			}},
+			{Name: "client-id", Type: "integer", Token: "ID", Optional: true, Multiple: true},
+		},
+	},
+	"CLIENT|SETNAME": {
+		Arity: 3, Flags: connFlags, Categories: []string{"connection"},
+		Summary: "Sets the connection name.", Since: "2.6.9", Group: "connection", Complexity: "O(1)",
+		Arguments: []commandArg{{Name: "connection-name", Type: "string"}},
+	},
+	"CLIENT|GETNAME": {
+		Arity: 2, Flags: connFlags, Categories: []string{"connection"},
+		Summary: "Returns the name of the connection.", Since: "2.6.9", Group: "connection", Complexity: "O(1)",
+	},
+	"CLIENT|KILL": {
+		Arity: -3, Flags: adminFlags, Categories: []string{"connection"},
+		Summary: "Terminates open connections.", Since: "2.4.0", Group: "connection", Complexity: "O(N) where N is the number of client connections",
+		Arguments: []commandArg{{Name: "filter", Type: "oneof", Multiple: true, Arguments: []commandArg{
+			{Name: "ip:port", Type: "string"},
+			{Name: "client-id", Type: "integer", Token: "ID"},
+			{Name: "ip:port", Type: "string", Token: "ADDR"},
+			{Name: "ip:port", Type: "string", Token: "LADDR"},
+			{Name: "username", Type: "string", Token: "USER"},
+			{Name: "skipme", Type: "string", Token: "SKIPME"},
+			{Name: "maxage", Type: "integer", Token: "MAXAGE"},
+		}}},
+	},
+	"CLIENT|PAUSE": {
+		Arity: -3, Flags: adminFlags, Categories: []string{"connection"},
+		Summary: "Suspends commands processing.", Since: "3.0.0", Group: "connection", Complexity: "O(1)",
+		Arguments: []commandArg{{Name: "timeout", Type: "integer"}, {Name: "mode", Type: "oneof", Optional: true, Arguments: []commandArg{
+			{Name: "write", Type: "pure-token", Token: "WRITE"},
+			{Name: "all", Type: "pure-token", Token: "ALL"},
+		}}},
+	},
+	"CLIENT|UNPAUSE": {
+		Arity: 2, Flags: adminFlags, Categories: []string{"connection"},
+		Summary: "Resumes processing commands from paused clients.", Since: "6.2.0", Group: "connection", Complexity: "O(N) Where N is the number of paused clients",
+	},
+	"CLIENT|UNBLOCK": {
+		Arity: -3, Flags: adminFlags, Categories: []string{"connection"},
+		Summary: "Unblocks a client blocked by a blocking command from a different connection.", Since: "5.0.0", Group: "connection", Complexity: "O(log N) where N is the number of client connections",
+		Arguments: []commandArg{{Name: "client-id", Type: "integer"}, {Name: "unblock-type", Type: "oneof", Optional: true, Arguments: []commandArg{
+			{Name: "timeout", Type: "pure-token", Token: "TIMEOUT"},
+			{Name: "error", Type: "pure-token", Token: "ERROR"},
+		}}},
+	},
+	"CLIENT|REPLY": {
+		Arity: 3, Flags: connFlags, Categories: []string{"connection"},
+		Summary: "Instructs the server whether to reply to commands.", Since: "3.2.0", Group: "connection", Complexity: "O(1)",
+		Arguments: []commandArg{{Name: "action", Type: "oneof", Arguments: []commandArg{
+			{Name: "on", Type: "pure-token", Token: "ON"},
+			{Name: "off", Type: "pure-token", Token: "OFF"},
+			{Name: "skip", Type: "pure-token", Token: "SKIP"},
+		}}},
+	},
+
+	"COMMAND|COUNT": {
+		Arity: 2, Flags: []string{"loading", "stale"}, Categories: []string{"connection"},
+		Summary: "Returns a count of commands.", Since: "2.8.13", Group: "server", Complexity: "O(1)",
+	},
+	"COMMAND|INFO": {
+		Arity: -2, Flags: []string{"loading", "stale"}, Categories: []string{"connection"},
+		Summary: "Returns information about one, multiple or all commands.", Since: "2.8.13", Group: "server", Complexity: "O(N) where N is the number of commands to look up",
+		Arguments: []commandArg{{Name: "command-name", Type: "string", Optional: true, Multiple: true}},
+	},
+	"COMMAND|DOCS": {
+		Arity: -2, Flags: []string{"loading", "stale"}, Categories: []string{"connection"},
+		Summary: "Returns documentary information about one, multiple or all commands.", Since: "7.0.0", Group: "server", Complexity: "O(N) where N is the number of commands to look up",
+		Arguments: []commandArg{{Name: "command-name", Type: "string", Optional: true, Multiple: true}},
+	},
+	"COMMAND|GETKEYS": {
+		Arity: -3, Flags: []string{"loading", "stale"}, Categories: []string{"connection"},
+		Summary: "Extracts the key names from an arbitrary command.", Since: "2.8.13", Group: "server", Complexity: "O(N) where N is the number of arguments to the command",
+		Arguments: []commandArg{{Name: "command", Type: "string"}, {Name: "arg", Type: "string", Optional: true, Multiple: true}},
+	},
+
+	"PUBSUB|CHANNELS": {
+		Arity: -2, Flags: []string{"pubsub", "loading", "stale"},
+		Summary: "Returns the active channels.", Since: "2.8.0", Group: "pubsub", Complexity: "O(N) where N is the number of active channels, and assuming constant time pattern matching (relatively short channels and patterns)",
+		Arguments: []commandArg{{Name: "pattern", Type: "pattern", Optional: true}},
+	},
+	"PUBSUB|NUMSUB": {
+		Arity: -2, Flags: []string{"pubsub", "loading", "stale"},
+		Summary: "Returns a count of subscribers to channels.", Since: "2.8.0", Group: "pubsub", Complexity: "O(N) for the NUMSUB subcommand, where N is the number of requested channels",
+		Arguments: []commandArg{{Name: "channel", Type: "string", Optional: true, Multiple: true}},
+	},
+	"PUBSUB|NUMPAT": {
+		Arity: 2, Flags: []string{"pubsub", "loading", "stale"},
+		Summary: "Returns a count of unique pattern subscriptions.", Since: "2.8.0", Group: "pubsub", Complexity: "O(1)",
+	},
+}
+
+// containerCommands have subcommands, they are reported together with their subcommand, e.g. client|list
+var containerCommands = map[string]bool{}
+
+func init() {
+	for name := range commandSpecs {
+		if container, _, isSubcommand := strings.Cut(name, "|"); isSubcommand {
+			containerCommands[container] = true
+		}
+	}
+
+	acl.SetCommandValidator(func(name string) bool {
+		name = strings.ToUpper(name)
+		if _, found := commandSpecs[name]; found {
+			return true
+		}
+		// remark: subcommands without own spec are validated by their container command
+		container, _, isSubcommand := strings.Cut(name, "|")
+		return isSubcommand && containerCommands[container]
+	})
+}
+
+func (s commandSpec) hasFlag(flag string) bool {
+	return slices.Contains(s.Flags, flag)
+}
+
+// arityMatches checks the number of arguments including the command name
+func (s commandSpec) arityMatches(numOfArgs int) bool {
+	if s.Arity < 0 {
+		return numOfArgs >= -s.Arity
+	}
+	return numOfArgs == s.Arity
+}
+
+// aclCategories returns the declared categories together with the ones implied by flags, the same way as Redis
+// puts write commands to @write, admin commands to @admin and @dangerous and commands that aren't fast to @slow
+func (s commandSpec) aclCategories() []string {
+	categories := []string{}
+	implied := []struct{ flag, category string }{
+		{"write", "write"}, {"readonly", "read"}, {"admin", "admin"}, {"admin", "dangerous"},
+		{"fast", "fast"}, {"blocking", "blocking"}, {"pubsub", "pubsub"},
+	}
+	for _, imp := range implied {
+		if s.hasFlag(imp.flag) {
+			categories = append(categories, imp.category)
+		}
+	}
+	if !s.hasFlag("fast") {
+		categories = append(categories, "slow")
+	}
+	for _, category := range s.Categories {
+		if !slices.Contains(categories, category) {
+			categories = append(categories, category)
+		}
+	}
+	return categories
+}
+
+// lookupCommandSpec returns spec of the subcommand if defined, otherwise spec of the command
+func lookupCommandSpec(command *Command) (commandSpec, bool) {
+	if len(command.CommandValues) > 0 {
+		if spec, found := commandSpecs[strings.ToUpper(FullName(command))]; found {
+			return spec, true
+		}
+	}
+	spec, found := commandSpecs[command.CommandType]
+	return spec, found
+}
+
+// checkArity verifies the number of arguments of the command and of its subcommand
+func checkArity(command *Command, spec commandSpec) error {
+	if !spec.arityMatches(len(command.CommandValues) + 1) {
+		return errWrongArgs(command.CommandType)
+	}
+	if containerCommands[command.CommandType] && len(command.CommandValues) > 0 {
+		// remark: unknown subcommands are reported by the container command
+		name := FullName(command)
+		if subSpec, found := commandSpecs[strings.ToUpper(name)]; found && !subSpec.arityMatches(len(command.CommandValues)+1) {
+			return errWrongArgs(name)
+		}
+	}
+	return nil
+}
+
+// commandKeys returns keys the command accesses
+func commandKeys(command *Command, spec commandSpec) []string {
+	if spec.GetKeys != nil {
+		return spec.GetKeys(command.CommandValues)
+	}
+	if spec.FirstKey == 0 {
+		return nil
+	}
+
+	// remark: CommandValues don't contain the command name, positions are shifted by one
+	args := command.CommandValues
+	last := spec.LastKey
+	if last < 0 {
+		last = len(args) + 1 + last
+	}
+
+	keys := []string{}
+	for pos := spec.FirstKey; pos <= last && pos-1 < len(args); pos += spec.KeyStep {
+		keys = append(keys, args[pos-1])
+	}
+	return keys
+}
+
+// xreadKeys returns stream keys, the first half of arguments after STREAMS
+func xreadKeys(args []string) []string {
+	for i, arg := range args {
+		if strings.ToUpper(arg) == "STREAMS" {
+			streams := args[i+1:]
+			return streams[:len(streams)/2]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package command
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/store"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+// WatchCommand marks keys for the check-and-set of the next transaction, EXEC fails when any of them is modified
+type WatchCommand struct {
+	Keys []string
+}
+
+// UnwatchCommand forgets all watched keys
+type UnwatchCommand struct{}
+
+// watchedKey is a key watched by the connection, a key existing when watched is
+// considered modified when it expires before EXEC
+type watchedKey struct {
+	key     string
+	existed bool
+}
+
+// watchRegistry maps watched keys to connections watching them, the same way as Redis
+// tracks watched keys, so keys nobody watches aren't tracked at all
+type watchRegistry struct {
+	mu   sync.Mutex
+	keys map[string]map[*CommandContext]struct{}
+}
+
+var watchedKeys = watchRegistry{
+	keys: map[string]map[*CommandContext]struct{}{},
+}
+
+func (c WatchCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	if cmdCtx.transaction != nil {
+		return respparser.SimpleError{}, Errorf(CodeErr, "WATCH inside MULTI is not allowed")
+	}
+	watchKeys(cmdCtx, c.Keys)
+	return okResponse, nil
+}
+
+func (c UnwatchCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
+	unwatchAllKeys(cmdCtx)
+	return okResponse, nil
+}
+
+func watchKeys(cmdCtx *CommandContext, keys []string) {
+	watchedKeys.mu.Lock()
+	defer watchedKeys.mu.Unlock()
+
+	for _, key := range keys {
+		if slices.ContainsFunc(cmdCtx.watchedKeys, func(w watchedKey) bool { return w.key == key }) {
+			continue
+		}
+		utils.Log(fmt.Sprintf("(WatchCommand) Watching key %q", key))
+		if watchedKeys.keys[key] == nil {
+			watchedKeys.keys[key] = map[*CommandContext]struct{}{}
+		}
+		watchedKeys.keys[key][cmdCtx] = struct{}{}
+		cmdCtx.watchedKeys = append(cmdCtx.watchedKeys, watchedKey{key: key, existed: store.Exists(key) > 0})
+	}
+}
+
+// unwatchAllKeys forgets keys watched by the connection and clears its check-and-set state
+func unwatchAllKeys(cmdCtx *CommandContext) {
+	watchedKeys.mu.Lock()
+	defer watchedKeys.mu.Unlock()
+
+	for _, watched := range cmdCtx.watchedKeys {
+		delete(watchedKeys.keys[watched.key], cmdCtx)
+		if len(watchedKeys.keys[watched.key]) == 0 {
+			delete(watchedKeys.keys, watched.key)
+		}
+	}
+	cmdCtx.watchedKeys = nil
+	cmdCtx.watchedKeyTouched.Store(false)
+}
+
+// touch records keys modified by the running command, the keys are touched once the command completes
+func (c *CommandContext) touch(keys ...string) {
+	c.touchedKeys = append(c.touchedKeys, keys...)
+}
+
+// touchWatchedKeys fails transactions of connections watching any of the modified keys
+func touchWatchedKeys(keys []string) {
+	watchedKeys.mu.Lock()
+	defer watchedKeys.mu.Unlock()
+
+	for _, key := range keys {
+		for cmdCtx := range watchedKeys.keys[key] {
+			utils.Log(fmt.Sprintf("(WatchCommand) Watched key %q modified", key))
+			cmdCtx.watchedKeyTouched.Store(true)
+		}
+	}
+}
+
+// watchedKeyExpired reports whether a key existing when watched is gone. Deleted keys are
+// reported by touchWatchedKeys already, so the key has expired meanwhile.
+func watchedKeyExpired(cmdCtx *CommandContext) bool {
+	for _, watched := range cmdCtx.watchedKeys {
+		if watched.existed && store.Exists(watched.key) == 0 {
+			utils.Log(fmt.Sprintf("(WatchCommand) Watched key %q expired", watched.key))
+			return true
+		}
+	}
+	return false
+}
+
+func parseWatchCommand(command *Command) (WatchCommand, error) {
+	return WatchCommand{Keys: command.CommandValues}, nil
+}
+
+func parseUnwatchCommand(_ *Command) (UnwatchCommand, error) {
+	return UnwatchCommand{}, nil
+}
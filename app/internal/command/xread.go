@@ -8,21 +8,47 @@ import (
 	"strings"
 	"time"
 
-	"github.com/codecrafters-io/redis-starter-go/app/internal/respparser"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/clients"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/store"
 	"github.com/codecrafters-io/redis-starter-go/app/internal/streamstore"
 	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
 )
 
+// serverContext is cancelled on server shutdown to wake up blocked readers
+var serverContext = context.Background()
+
+// SetServerContext sets the context blocking commands wait on, it must be called before serving clients
+func SetServerContext(ctx context.Context) {
+	serverContext = ctx
+}
+
 type XReadCommand struct {
 	Streams     []XReadStream
 	BlockMillis int
 	IsBlocking  bool
 }
 
-func (c XReadCommand) Process() (respparser.RespData, error) {
+// Blocks reports whether XREAD may wait for entries, blocking commands don't block inside transactions
+func (c XReadCommand) Blocks(cmdCtx *CommandContext) bool {
+	return c.IsBlocking && !cmdCtx.executingTransaction
+}
+
+func (c XReadCommand) Process(cmdCtx *CommandContext) (respparser.RespData, error) {
 	utils.Log("(XReadCommand) Processing XRead command")
 	streams := []respparser.RespData{}
 
+	// remark: a blocking read doesn't hold the keyspace lock, it's held around each read instead,
+	// so the read doesn't see a half applied transaction
+	blocks := c.Blocks(cmdCtx)
+	readShared := func(read func() error) error {
+		if blocks {
+			keyspaceLock.RLock()
+			defer keyspaceLock.RUnlock()
+		}
+		return read()
+	}
+
 	for _, stream := range c.Streams {
 
 		entryId := stream.entryId
@@ -31,12 +57,11 @@ func (c XReadCommand) Process() (respparser.RespData, error) {
 
 		// Create stream filter for this use case
 		streamFilter := func(stream streamstore.RedisStream) bool {
-			if stream.EntryIdMillisecondsTime == entryId.MillisecondsTime && stream.EntryIdSequenceNumber == entryId.SequenceNumber {
-				// exclusive search
-				return false
+			// exclusive search, IDs are compared by time first and by sequence number for the same time
+			if stream.EntryIdMillisecondsTime != entryId.MillisecondsTime {
+				return stream.EntryIdMillisecondsTime > entryId.MillisecondsTime
 			}
-
-			return stream.EntryIdMillisecondsTime >= entryId.MillisecondsTime && stream.EntryIdSequenceNumber >= entryId.SequenceNumber
+			return stream.EntryIdSequenceNumber > entryId.SequenceNumber
 		}
 
 		streamFilterArray := func(filter []streamstore.RedisStream) []streamstore.RedisStream {
@@ -55,36 +80,76 @@ func (c XReadCommand) Process() (respparser.RespData, error) {
 		var result []streamstore.RedisStream = nil
 		var found bool = false
 
-		if !entryId.StreamTopItems {
-			result, found = streamstore.GetItemsByFilter(streamKey, streamFilterArray)
+		err := readShared(func() (err error) {
+			if !entryId.StreamTopItems {
+				result, found, err = streamstore.GetItemsByFilter(streamKey, streamFilterArray)
+				return err
+			}
+			if keyType := store.TypeOf(streamKey); keyType != store.TypeStream && keyType != store.TypeNone {
+				return store.ErrWrongType
+			}
+			return nil
+		})
+		if err != nil {
+			return respparser.SimpleError{}, err
 		}
 
-		if !found && c.IsBlocking {
+		if !found && blocks {
 			var ctx context.Context
 			var cancel context.CancelFunc
 			if c.BlockMillis <= 0 {
 				// blocking read indefinitely
 				utils.Log(fmt.Sprintf("(XReadCommand) Stream with key %s not found, waiting for response", streamKey))
-				ctx, cancel = context.WithCancel(context.Background())
+				ctx, cancel = context.WithCancel(serverContext)
 			} else {
 				// blocking reads with timeout
 				utils.Log(fmt.Sprintf("(XReadCommand) Stream with key %s not found, waiting for %d milliseconds for response", streamKey, c.BlockMillis))
-				ctx, cancel = context.WithTimeout(context.Background(), time.Duration(c.BlockMillis)*time.Millisecond)
+				ctx, cancel = context.WithTimeout(serverContext, time.Duration(c.BlockMillis)*time.Millisecond)
 			}
 			defer cancel()
 
-			streamNotificationChannel := streamstore.GetStreamNotificationChannel()
+			// remark: the blocked client can be woken up by CLIENT UNBLOCK or CLIENT KILL
+			if cmdCtx.Client != nil {
+				var unblock context.CancelFunc
+				ctx, unblock = cmdCtx.Client.Block(ctx)
+				defer unblock()
+			}
+
+			added, stopWaiting := streamstore.Wait(streamKey)
+			defer stopWaiting()
+
+			// remark: $ stands for the top item of the stream when the reader starts waiting
+			if entryId.StreamTopItems {
+				var topItem streamstore.RedisStream
+				err := readShared(func() (err error) {
+					topItem, _, err = streamstore.GetTopItem(streamKey)
+					return err
+				})
+				if err != nil {
+					return respparser.SimpleError{}, err
+				}
+				entryId = EntryId{MillisecondsTime: topItem.EntryIdMillisecondsTime, SequenceNumber: topItem.EntryIdSequenceNumber}
+			}
 
 			for !found {
+				// remark: the stream is read again after the reader is registered, so no entry added meanwhile is missed
+				err := readShared(func() (err error) {
+					result, found, err = streamstore.GetItemsByFilter(streamKey, streamFilterArray)
+					return err
+				})
+				if err != nil {
+					return respparser.SimpleError{}, err
+				}
+				if found {
+					break
+				}
+
 				select {
-				case s := <-streamNotificationChannel:
-					if entryId.StreamTopItems || streamFilter(s) {
-						result = []streamstore.RedisStream{
-							s,
-						}
-						found = true
-					}
+				case <-added:
 				case <-ctx.Done():
+					if errors.Is(context.Cause(ctx), clients.ErrUnblockedError) {
+						return respparser.SimpleError{}, Errorf(CodeUnblocked, "client unblocked via CLIENT UNBLOCK")
+					}
 					// remark: when timeout occurs, nil array is returned
 					nilArray := respparser.Array{IsNull: true}
 					return nilArray, nil
@@ -119,10 +184,6 @@ func (c XReadCommand) Process() (respparser.RespData, error) {
 }
 
 func parseXReadCommand(command *Command) (XReadCommand, error) {
-	if command.CommandType != "XREAD" {
-		return XReadCommand{}, errors.New("Not a XREAD")
-	}
-
 	xReadCommand := XReadCommand{}
 
 	for i, value := range command.CommandValues {
@@ -131,13 +192,13 @@ func parseXReadCommand(command *Command) (XReadCommand, error) {
 		switch upperValue {
 		case "BLOCK":
 			if i+1 >= len(command.CommandValues) {
-				return xReadCommand, fmt.Errorf("missing BLOCK value")
+				return xReadCommand, errSyntax
 			}
 
 			blockMillis, err := strconv.Atoi(command.CommandValues[i+1])
 			if err != nil {
 				utils.Log(fmt.Sprintf("ERROR (parseXReadCommand) Invalid BLOCK value. Int expected, but got: %s. Error: %v", command.CommandValues[i+1], err))
-				return xReadCommand, err
+				return xReadCommand, Errorf(CodeErr, "timeout is not an integer or out of range")
 			}
 
 			xReadCommand.BlockMillis = blockMillis
@@ -146,9 +207,8 @@ func parseXReadCommand(command *Command) (XReadCommand, error) {
 		case "STREAMS":
 			streams := command.CommandValues[i+1:]
 			if len(streams)%2 != 0 {
-				err := fmt.Errorf("ERROR (parseXReadCommand) Even number of streams value expected, but got: %d", len(streams))
-				utils.Log(err.Error())
-				return xReadCommand, err
+				utils.Log(fmt.Sprintf("ERROR (parseXReadCommand) Even number of streams value expected, but got: %d", len(streams)))
+				return xReadCommand, Errorf(CodeErr, "Unbalanced 'xread' list of streams: for each stream key an ID or '$' must be specified.")
 			}
 
 			for j := 0; j < len(streams)/2; j++ {
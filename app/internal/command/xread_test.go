@@ -0,0 +1,73 @@
+package command
+
+import (
+	"testing"
+	"time"
+)
+
+func TestXReadReturnsEntriesAfterId(t *testing.T) {
+	for _, id := range []string{"1-1", "1-5", "2-0", "2-3", "10-0"} {
+		runTestCommand("XADD", "xread-test:stream", id, "field", id)
+	}
+
+	var tests = []struct {
+		name string
+		id   string
+		want string
+	}{
+		{name: "Later time with lower sequence number", id: "1-5", want: "[[xread-test:stream,[[2-0,{field:2-0}],[2-3,{field:2-3}],[10-0,{field:10-0}]]]]"},
+		{name: "Same time with higher sequence number", id: "2-1", want: "[[xread-test:stream,[[2-3,{field:2-3}],[10-0,{field:10-0}]]]]"},
+		{name: "Time compared as number", id: "9-9", want: "[[xread-test:stream,[[10-0,{field:10-0}]]]]"},
+		{name: "Last entry is excluded", id: "10-0", want: "[]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reply := runTestCommand("XREAD", "STREAMS", "xread-test:stream", tt.id)
+			if got := reply.String(); got != tt.want {
+				t.Errorf("ERROR got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestXReadDoesntReadDuringTransaction(t *testing.T) {
+	var tests = []struct {
+		name string
+		args []string
+	}{
+		{name: "Without BLOCK", args: []string{"STREAMS", "xread-test:locked", "0"}},
+		{name: "With BLOCK", args: []string{"BLOCK", "10", "STREAMS", "xread-test:locked", "0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command := &Command{CommandType: "XREAD", CommandValues: tt.args}
+			handler, err := GetCommandHandler(command)
+			if err != nil {
+				t.Fatalf("ERROR handler expected, but err got: %s", err.Error())
+			}
+
+			// remark: EXEC holds the lock exclusively while it runs queued commands
+			keyspaceLock.Lock()
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				processCommandShared(command, handler, &CommandContext{})
+			}()
+
+			select {
+			case <-done:
+				t.Errorf("ERROR XREAD finished while the transaction was running")
+			case <-time.After(50 * time.Millisecond):
+			}
+			keyspaceLock.Unlock()
+
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatalf("ERROR XREAD didn't finish after the transaction")
+			}
+		})
+	}
+}
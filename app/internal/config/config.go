@@ -0,0 +1,170 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+)
+
+// Param describes a single configuration directive
+type Param struct {
+	Name      string
+	Default   string
+	Usage     string
+	Immutable bool                     // immutable params can be set on startup only
+	Validate  func(value string) error // optional: validation of a new value
+	Apply     func(value string) error // optional: propagates the new value to the server
+}
+
+type Config struct {
+	mu     sync.RWMutex
+	params map[string]*Param
+	values map[string]string
+}
+
+var liveConfig = newConfig(defaultParams())
+
+func newConfig(params []*Param) *Config {
+	c := &Config{
+		params: make(map[string]*Param, len(params)),
+		values: make(map[string]string, len(params)),
+	}
+	for _, p := range params {
+		c.params[p.Name] = p
+		c.values[p.Name] = p.Default
+	}
+	return c
+}
+
+// Params returns all known params sorted by name
+func Params() []*Param {
+	liveConfig.mu.RLock()
+	defer liveConfig.mu.RUnlock()
+
+	params := make([]*Param, 0, len(liveConfig.params))
+	for _, p := range liveConfig.params {
+		params = append(params, p)
+	}
+	slices.SortFunc(params, func(a, b *Param) int { return strings.Compare(a.Name, b.Name) })
+	return params
+}
+
+func Get(name string) (string, bool) {
+	liveConfig.mu.RLock()
+	defer liveConfig.mu.RUnlock()
+
+	value, found := liveConfig.values[strings.ToLower(name)]
+	return value, found
+}
+
+// GetString returns value of a known param, unknown params are programming errors
+func GetString(name string) string {
+	value, found := Get(name)
+	if !found {
+		panic(fmt.Sprintf("(config) unknown param %s", name))
+	}
+	return value
+}
+
+func GetInt(name string) int {
+	value, err := parseInt(GetString(name))
+	if err != nil {
+		panic(fmt.Sprintf("(config) param %s is not an integer: %s", name, err.Error()))
+	}
+	return value
+}
+
+// GetFileMode returns value of a known param holding octal permissions
+func GetFileMode(name string) os.FileMode {
+	perm, err := strconv.ParseUint(GetString(name), 8, 32)
+	if err != nil {
+		panic(fmt.Sprintf("(config) param %s is not an octal permission: %s", name, err.Error()))
+	}
+	return os.FileMode(perm)
+}
+
+// Match returns name and value pairs of all params matching the glob pattern
+func Match(pattern string) [][2]string {
+	result := [][2]string{}
+	for _, p := range Params() {
+		if utils.GlobMatchNoCase(pattern, p.Name) {
+			value, _ := Get(p.Name)
+			result = append(result, [2]string{p.Name, value})
+		}
+	}
+	return result
+}
+
+// Load sets the param on startup, immutable params are allowed
+func Load(name string, value string) error {
+	return liveConfig.set([][2]string{{name, value}}, true)
+}
+
+// Set changes params at runtime. All values are validated before any of them is applied.
+func Set(nameValues [][2]string) error {
+	return liveConfig.set(nameValues, false)
+}
+
+func (c *Config) set(nameValues [][2]string, startup bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seen := map[string]bool{}
+	for _, nv := range nameValues {
+		name := strings.ToLower(nv[0])
+		p, found := c.params[name]
+		if !found {
+			return fmt.Errorf("Unknown option or number of arguments for CONFIG SET - '%s'", nv[0])
+		}
+		if seen[name] {
+			return fmt.Errorf("CONFIG SET failed (possibly related to argument '%s') - duplicate parameter", nv[0])
+		}
+		seen[name] = true
+
+		if p.Immutable && !startup {
+			return fmt.Errorf("CONFIG SET failed (possibly related to argument '%s') - can't set immutable config", nv[0])
+		}
+		if p.Validate != nil {
+			if err := p.Validate(nv[1]); err != nil {
+				return fmt.Errorf("CONFIG SET failed (possibly related to argument '%s') - %s", nv[0], err.Error())
+			}
+		}
+	}
+
+	for n, nv := range nameValues {
+		name := strings.ToLower(nv[0])
+		p := c.params[name]
+		if p.Apply != nil {
+			if err := p.Apply(nv[1]); err != nil {
+				c.rollback(nameValues[:n])
+				return fmt.Errorf("CONFIG SET failed (possibly related to argument '%s') - %s", nv[0], err.Error())
+			}
+		}
+		utils.Log(fmt.Sprintf("(config) %s set to %s", name, nv[1]))
+	}
+	for _, nv := range nameValues {
+		c.values[strings.ToLower(nv[0])] = nv[1]
+	}
+	return nil
+}
+
+// rollback applies previous values of already applied params again, so a failed CONFIG SET
+// changes nothing. The lock must be held.
+func (c *Config) rollback(applied [][2]string) {
+	for n := len(applied) - 1; n >= 0; n-- {
+		name := strings.ToLower(applied[n][0])
+		p := c.params[name]
+		if p.Apply == nil {
+			continue
+		}
+		// remark: the previous value was applied before, so it's expected to be applied again
+		if err := p.Apply(c.values[name]); err != nil {
+			utils.LogWarning(fmt.Sprintf("(config) Can't restore %s to %s: %s", name, c.values[name], err.Error()))
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConfigSet(t *testing.T) {
+	var tests = []struct {
+		name      string
+		input     [][2]string
+		wantErr   bool
+		wantParam string
+		wantValue string
+	}{
+		{
+			name:      "Mutable param should be changed",
+			input:     [][2]string{{"dbfilename", "other.rdb"}},
+			wantParam: "dbfilename",
+			wantValue: "other.rdb",
+		},
+		{
+			name:      "Param names are case insensitive",
+			input:     [][2]string{{"DBFILENAME", "upper.rdb"}},
+			wantParam: "dbfilename",
+			wantValue: "upper.rdb",
+		},
+		{
+			name:      "Immutable param should be rejected",
+			input:     [][2]string{{"port", "6380"}},
+			wantErr:   true,
+			wantParam: "port",
+			wantValue: "6379",
+		},
+		{
+			name:      "Unknown param should be rejected",
+			input:     [][2]string{{"unknown-param", "1"}},
+			wantErr:   true,
+			wantParam: "dbfilename",
+			wantValue: "upper.rdb",
+		},
+		{
+			name:      "Memory units should be accepted",
+			input:     [][2]string{{"proto-max-bulk-len", "2mb"}},
+			wantParam: "proto-max-bulk-len",
+			wantValue: "2mb",
+		},
+		{
+			name:      "Memory value below minimum should be rejected",
+			input:     [][2]string{{"proto-max-bulk-len", "1k"}},
+			wantErr:   true,
+			wantParam: "proto-max-bulk-len",
+			wantValue: "2mb",
+		},
+		{
+			name:      "Plain bytes should be accepted",
+			input:     [][2]string{{"proto-max-bulk-len", "536870912"}},
+			wantParam: "proto-max-bulk-len",
+			wantValue: "536870912",
+		},
+		{
+			name:      "Invalid value rejects the whole set",
+			input:     [][2]string{{"dbfilename", "valid.rdb"}, {"loglevel", "loud"}},
+			wantErr:   true,
+			wantParam: "dbfilename",
+			wantValue: "upper.rdb",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Set(tt.input)
+			if tt.wantErr && err == nil {
+				t.Errorf("ERROR error expected, but got nil")
+			} else if !tt.wantErr && err != nil {
+				t.Errorf("ERROR result expected, but err got: %s", err.Error())
+			}
+
+			if got := GetString(tt.wantParam); got != tt.wantValue {
+				t.Errorf("ERROR got %s, want %s", got, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestConfigMatch(t *testing.T) {
+	var tests = []struct {
+		name    string
+		pattern string
+		want    []string
+	}{
+		{
+			name:    "Exact name should match",
+			pattern: "port",
+			want:    []string{"port"},
+		},
+		{
+			name:    "Glob should match multiple params",
+			pattern: "d*",
+			want:    []string{"dbfilename", "dir"},
+		},
+		{
+			name:    "Unknown param matches nothing",
+			pattern: "nothing-here",
+			want:    []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ans := Match(tt.pattern)
+			if len(ans) != len(tt.want) {
+				t.Fatalf("ERROR got %v, want %v", ans, tt.want)
+			}
+			for n, nv := range ans {
+				if nv[0] != tt.want[n] {
+					t.Errorf("ERROR got %v, want %v", ans, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestConfigSetRollsBackOnApplyFailure(t *testing.T) {
+	applied := map[string]string{}
+	record := func(name string) func(string) error {
+		return func(value string) error {
+			applied[name] = value
+			return nil
+		}
+	}
+	c := newConfig([]*Param{
+		{Name: "first", Default: "1", Apply: record("first")},
+		{Name: "second", Default: "2"},
+		{Name: "failing", Default: "3", Apply: func(value string) error { return errors.New("can't apply") }},
+	})
+
+	err := c.set([][2]string{{"first", "10"}, {"second", "20"}, {"failing", "30"}}, false)
+	if err == nil {
+		t.Fatalf("ERROR error expected, but got nil")
+	}
+
+	for name, want := range map[string]string{"first": "1", "second": "2", "failing": "3"} {
+		if got := c.values[name]; got != want {
+			t.Errorf("ERROR got %s = %s, want %s", name, got, want)
+		}
+	}
+	if got := applied["first"]; got != "1" {
+		t.Errorf("ERROR got first applied as %s, want previous value 1 restored", got)
+	}
+}
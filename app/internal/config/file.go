@@ -0,0 +1,197 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+)
+
+// rewriteSignature separates user content from params appended by CONFIG REWRITE
+const rewriteSignature = "# Generated by CONFIG REWRITE"
+
+var ErrNoConfigFile = errors.New("The server is running without a config file")
+
+var configFile struct {
+	mu   sync.Mutex
+	path string
+}
+
+// FileError points to the offending line of a config file
+type FileError struct {
+	Path   string
+	Line   int
+	Text   string
+	Reason string
+}
+
+func (e *FileError) Error() string {
+	return fmt.Sprintf("*** FATAL CONFIG FILE ERROR ***\nReading the configuration file %s, at line %d\n>>> '%s'\n%s", e.Path, e.Line, e.Text, e.Reason)
+}
+
+// FilePath returns path of the loaded config file, empty when started without one
+func FilePath() string {
+	configFile.mu.Lock()
+	defer configFile.mu.Unlock()
+	return configFile.path
+}
+
+// LoadFile reads a redis.conf style file: one directive per line, '#' starts a comment
+func LoadFile(path string) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(absPath)
+	if err != nil {
+		return fmt.Errorf("Fatal error, can't open config file '%s': %s", path, err.Error())
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fileErr := func(reason string) error {
+			return &FileError{Path: path, Line: lineNum, Text: line, Reason: reason}
+		}
+
+		args, err := utils.SplitArgs(line)
+		if err != nil {
+			return fileErr("Unbalanced quotes in configuration line")
+		}
+
+		name := strings.ToLower(args[0])
+		if _, found := Get(name); !found {
+			return fileErr(fmt.Sprintf("Bad directive or wrong number of arguments: unknown directive '%s'", args[0]))
+		}
+		if len(args) != 2 {
+			return fileErr(fmt.Sprintf("Bad directive or wrong number of arguments: '%s' expects exactly one argument", args[0]))
+		}
+
+		if err := liveConfig.set([][2]string{{name, args[1]}}, true); err != nil {
+			return fileErr(err.Error())
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	configFile.mu.Lock()
+	configFile.path = absPath
+	configFile.mu.Unlock()
+
+	utils.LogNotice(fmt.Sprintf("(config) Configuration loaded from %s", absPath))
+	return nil
+}
+
+// Rewrite writes the current config back to the loaded file. Comments, ordering and
+// unrelated lines are kept, known directives are updated in place and duplicates removed.
+// Params missing in the file are appended only when they differ from their default.
+func Rewrite() error {
+	configFile.mu.Lock()
+	defer configFile.mu.Unlock()
+
+	if configFile.path == "" {
+		return ErrNoConfigFile
+	}
+
+	content, err := os.ReadFile(configFile.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	written := map[string]bool{}
+	lines := []string{}
+	existingLines := []string{}
+	if len(content) > 0 {
+		existingLines = strings.Split(strings.TrimRight(string(content), "\n"), "\n")
+	}
+
+	hasSignature := false
+	for _, line := range existingLines {
+		if line == rewriteSignature {
+			hasSignature = true
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			lines = append(lines, line)
+			continue
+		}
+
+		args, err := utils.SplitArgs(trimmed)
+		if err != nil || len(args) == 0 {
+			lines = append(lines, line)
+			continue
+		}
+
+		name := strings.ToLower(args[0])
+		value, found := Get(name)
+		if !found {
+			lines = append(lines, line)
+			continue
+		}
+		if written[name] {
+			// drop duplicated directives, the first occurrence holds the value
+			continue
+		}
+		written[name] = true
+		lines = append(lines, name+" "+utils.QuoteArg(value))
+	}
+
+	for _, p := range Params() {
+		value, _ := Get(p.Name)
+		if written[p.Name] || value == p.Default {
+			continue
+		}
+		if !hasSignature {
+			lines = append(lines, rewriteSignature)
+			hasSignature = true
+		}
+		lines = append(lines, p.Name+" "+utils.QuoteArg(value))
+	}
+
+	return writeFileAtomic(configFile.path, []byte(strings.Join(lines, "\n")+"\n"))
+}
+
+func writeFileAtomic(path string, content []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".redis-config-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
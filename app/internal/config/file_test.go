@@ -0,0 +1,106 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "redis.conf")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("ERROR can't write config file: %s", err.Error())
+	}
+	return path
+}
+
+func TestLoadFile(t *testing.T) {
+	var tests = []struct {
+		name     string
+		content  string
+		wantLine int
+		want     map[string]string
+	}{
+		{
+			name:    "Directives, comments and quoted values should be loaded",
+			content: "# server config\n\nloglevel notice\n  dbfilename \"my dump.rdb\"\n",
+			want:    map[string]string{"loglevel": "notice", "dbfilename": "my dump.rdb"},
+		},
+		{
+			name:     "Unknown directive should fail with line number",
+			content:  "loglevel notice\nappendonly yes\n",
+			wantLine: 2,
+		},
+		{
+			name:     "Wrong number of arguments should fail",
+			content:  "dbfilename a.rdb b.rdb\n",
+			wantLine: 1,
+		},
+		{
+			name:     "Invalid value should fail",
+			content:  "# comment\nworkers zero\n",
+			wantLine: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := LoadFile(writeConfigFile(t, tt.content))
+
+			if tt.wantLine > 0 {
+				var fileErr *FileError
+				if !errors.As(err, &fileErr) {
+					t.Fatalf("ERROR file error expected, but got: %v", err)
+				}
+				if fileErr.Line != tt.wantLine {
+					t.Errorf("ERROR got line %d, want %d", fileErr.Line, tt.wantLine)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+			}
+			for name, value := range tt.want {
+				if got := GetString(name); got != value {
+					t.Errorf("ERROR %s got %s, want %s", name, got, value)
+				}
+			}
+		})
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	content := "# my comment\nloglevel notice\n\n# dump file\ndbfilename a.rdb\ndbfilename b.rdb\n"
+	path := writeConfigFile(t, content)
+	if err := LoadFile(path); err != nil {
+		t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+	}
+
+	if err := Set([][2]string{{"dbfilename", "c d.rdb"}, {"loglevel", "warning"}}); err != nil {
+		t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+	}
+	if err := Load("workers", "4"); err != nil {
+		t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+	}
+
+	if err := Rewrite(); err != nil {
+		t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+	}
+
+	got, _ := os.ReadFile(path)
+	want := "# my comment\nloglevel warning\n\n# dump file\ndbfilename \"c d.rdb\"\n" + rewriteSignature + "\nworkers 4\n"
+	if string(got) != want {
+		t.Errorf("ERROR got %q, want %q", string(got), want)
+	}
+
+	// second rewrite must be stable
+	if err := Rewrite(); err != nil {
+		t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+	}
+	again, _ := os.ReadFile(path)
+	if string(again) != want {
+		t.Errorf("ERROR got %q, want %q", string(again), want)
+	}
+}
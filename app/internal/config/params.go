@@ -0,0 +1,287 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/acl"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+func defaultParams() []*Param {
+	return []*Param{
+		{
+			Name:      "bind",
+			Default:   "0.0.0.0",
+			Usage:     "interface address the server listens on",
+			Immutable: true,
+		},
+		{
+			Name:      "port",
+			Default:   "6379",
+			Usage:     "TCP port the server listens on",
+			Immutable: true,
+			Validate:  intRange(0, 65535),
+		},
+		{
+			Name:      "tls-port",
+			Default:   "0",
+			Usage:     "TLS port the server listens on, disabled when 0",
+			Immutable: true,
+			Validate:  intRange(0, 65535),
+		},
+		{
+			Name:      "tls-cert-file",
+			Default:   "",
+			Usage:     "PEM encoded server certificate",
+			Immutable: true,
+		},
+		{
+			Name:      "tls-key-file",
+			Default:   "",
+			Usage:     "PEM encoded private key of the server certificate",
+			Immutable: true,
+		},
+		{
+			Name:      "tls-ca-cert-file",
+			Default:   "",
+			Usage:     "PEM encoded CA certificates used to verify client certificates",
+			Immutable: true,
+		},
+		{
+			Name:      "tls-auth-clients",
+			Default:   "yes",
+			Usage:     "client certificate authentication: yes, no or optional",
+			Immutable: true,
+			Validate:  oneOf("yes", "no", "optional"),
+		},
+		{
+			Name:      "unixsocket",
+			Default:   "",
+			Usage:     "path of the unix socket to listen on, disabled when empty",
+			Immutable: true,
+		},
+		{
+			Name:      "unixsocketperm",
+			Default:   "0",
+			Usage:     "octal permissions of the unix socket file, 0 keeps the umask default",
+			Immutable: true,
+			Validate:  octalPerm,
+		},
+		{
+			Name:     "dir",
+			Default:  ".",
+			Usage:    "working directory of the server",
+			Validate: existingDir,
+			Apply:    os.Chdir,
+		},
+		{
+			Name:     "dbfilename",
+			Default:  "dump.rdb",
+			Usage:    "name of the database file within dir",
+			Validate: plainFilename,
+		},
+		{
+			Name:      "workers",
+			Default:   "10",
+			Usage:     "size of the event loop worker pool",
+			Immutable: true,
+			Validate:  intRange(1, 1024),
+		},
+		{
+			Name:      "eventloop-queue-size",
+			Default:   "10",
+			Usage:     "buffer size of the event loop task queues",
+			Immutable: true,
+			Validate:  intRange(0, 1<<20),
+		},
+		{
+			Name:     "shutdown-timeout",
+			Default:  "10",
+			Usage:    "seconds to wait for in-flight commands on shutdown",
+			Validate: intRange(0, 1<<20),
+		},
+		{
+			Name:     "loglevel",
+			Default:  "debug",
+			Usage:    "log verbosity: debug, verbose, notice, warning or nothing",
+			Validate: validLogLevel,
+			Apply:    applyLogLevel,
+		},
+		{
+			Name:     "proto-max-bulk-len",
+			Default:  "536870912",
+			Usage:    "maximum length of a bulk string sent by clients, memory units like 512mb are accepted",
+			Validate: memoryRange(1024*1024, math.MaxInt64),
+			Apply:    applyMaxBulkLen,
+		},
+		{
+			Name:     "proto-max-multibulk-len",
+			Default:  "1048576",
+			Usage:    "maximum number of elements of an array sent by clients",
+			Validate: intRange(1, math.MaxInt32),
+			Apply:    applyMaxMultibulkLen,
+		},
+		{
+			Name:    "requirepass",
+			Default: "",
+			Usage:   "password of the default user, the default user needs no password when empty",
+			Apply:   acl.SetRequirePass,
+		},
+		{
+			Name:      "aclfile",
+			Default:   "",
+			Usage:     "path of the ACL file with user definitions",
+			Immutable: true,
+		},
+		{
+			Name:     "acllog-max-len",
+			Default:  "128",
+			Usage:    "maximum number of ACL LOG entries",
+			Validate: intRange(0, 1<<20),
+			Apply:    applyAclLogMaxLen,
+		},
+	}
+}
+
+func parseInt(value string) (int, error) {
+	return strconv.Atoi(strings.TrimSpace(value))
+}
+
+func intRange(min int, max int) func(string) error {
+	return func(value string) error {
+		i, err := parseInt(value)
+		if err != nil {
+			return errors.New("argument couldn't be parsed into an integer")
+		}
+		if i < min || i > max {
+			return fmt.Errorf("argument must be between %d and %d inclusive", min, max)
+		}
+		return nil
+	}
+}
+
+// parseMemory parses bytes with an optional unit: k, m, g are powers of 1000, kb, mb, gb powers of 1024
+func parseMemory(value string) (int64, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	units := []struct {
+		suffix     string
+		multiplier int64
+	}{
+		{"kb", 1024}, {"mb", 1024 * 1024}, {"gb", 1024 * 1024 * 1024},
+		{"k", 1000}, {"m", 1000 * 1000}, {"g", 1000 * 1000 * 1000},
+	}
+
+	multiplier := int64(1)
+	for _, unit := range units {
+		if number, found := strings.CutSuffix(value, unit.suffix); found {
+			value, multiplier = number, unit.multiplier
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n > math.MaxInt64/multiplier {
+		return 0, errors.New("argument must be a memory value")
+	}
+	return n * multiplier, nil
+}
+
+func memoryRange(min int64, max int64) func(string) error {
+	return func(value string) error {
+		n, err := parseMemory(value)
+		if err != nil {
+			return err
+		}
+		if n < min || n > max {
+			return fmt.Errorf("argument must be between %d and %d inclusive", min, max)
+		}
+		return nil
+	}
+}
+
+func oneOf(allowed ...string) func(string) error {
+	return func(value string) error {
+		for _, a := range allowed {
+			if strings.EqualFold(a, value) {
+				return nil
+			}
+		}
+		return fmt.Errorf("argument(s) must be one of the following: %s", strings.Join(allowed, ", "))
+	}
+}
+
+func octalPerm(value string) error {
+	perm, err := strconv.ParseUint(value, 8, 32)
+	if err != nil || perm > 0777 {
+		return errors.New("argument must be an octal permission between 0 and 777")
+	}
+	return nil
+}
+
+func existingDir(value string) error {
+	info, err := os.Stat(value)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", value)
+	}
+	return nil
+}
+
+func plainFilename(value string) error {
+	if value == "" || strings.ContainsRune(value, os.PathSeparator) {
+		return errors.New("dbfilename can't be a path, just a filename")
+	}
+	return nil
+}
+
+func validLogLevel(value string) error {
+	_, err := utils.ParseLogLevel(value)
+	if err != nil {
+		return errors.New("argument(s) must be one of the following: debug, verbose, notice, warning, nothing")
+	}
+	return nil
+}
+
+func applyLogLevel(value string) error {
+	level, err := utils.ParseLogLevel(value)
+	if err != nil {
+		return err
+	}
+	utils.SetLogLevel(level)
+	return nil
+}
+
+func applyAclLogMaxLen(value string) error {
+	maxLen, err := parseInt(value)
+	if err != nil {
+		return err
+	}
+	acl.SetLogMaxLen(maxLen)
+	return nil
+}
+
+func applyMaxBulkLen(value string) error {
+	maxLen, err := parseMemory(value)
+	if err != nil {
+		return err
+	}
+	respparser.SetMaxBulkLen(maxLen)
+	return nil
+}
+
+func applyMaxMultibulkLen(value string) error {
+	maxLen, err := parseInt(value)
+	if err != nil {
+		return err
+	}
+	respparser.SetMaxMultibulkLen(int64(maxLen))
+	return nil
+}
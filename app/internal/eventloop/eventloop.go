@@ -1,11 +1,16 @@
 package eventloop
 
-import "sync"
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
 type Task struct {
 	MainTask   func() // main task to execute
 	Callback   func() // optional callback function
 	IsBlocking bool   // flag to determine if a task is blocking or not
+	MayWait    bool   // task may wait for other tasks, e.g. XREAD BLOCK, it runs outside of the worker pool
 }
 
 type CommandEventLoop struct {
@@ -14,6 +19,9 @@ type CommandEventLoop struct {
 	Stop         chan bool // channel to indicate the event loop to stop
 }
 
+// drainPollInterval is how often a stopping event loop checks for running workers
+const drainPollInterval = 10 * time.Millisecond
+
 func Add(eventLoop *CommandEventLoop, task *Task) {
 	// push task to command channel
 	eventLoop.MainTask <- *task
@@ -24,6 +32,8 @@ func AddToTaskQueue(eventLoop *CommandEventLoop, task *Task) {
 	eventLoop.CommandQueue <- *task
 }
 
+// StopEventLoop asks the event loop to stop. Queued tasks and tasks running
+// in the worker pool are finished first, wait on the wait group returned by InitEventLoop.
 func StopEventLoop(eventLoop *CommandEventLoop) {
 	eventLoop.Stop <- true
 }
@@ -34,6 +44,44 @@ func InitEventLoop(eventLoop *CommandEventLoop, workerPoolSize int) *sync.WaitGr
 	// add event loop goroutine to the wait group
 	wg.Add(1)
 	workerPool := make(chan struct{}, workerPoolSize)
+	// number of running tasks which may wait, those don't take a worker
+	waiting := &atomic.Int64{}
+
+	runInBackground := func(task Task) {
+		task.MainTask()
+		if task.Callback != nil {
+			// If callback exists, run it after main task is completed
+			AddToTaskQueue(eventLoop, &Task{
+				MainTask: task.Callback,
+			})
+		}
+	}
+
+	runTask := func(task Task) {
+		if task.MayWait {
+			// remark: waiting tasks would hold workers until all are taken, then the event loop
+			// would stop on acquiring a worker and no task could wake the waiting ones up
+			waiting.Add(1)
+			go func() {
+				defer waiting.Add(-1)
+				runInBackground(task)
+			}()
+		} else if task.IsBlocking {
+			// append blocking tasks to worker pool
+			workerPool <- struct{}{} // acquire a worker
+
+			// execute blocking task in separate go routine
+			go func() {
+				defer func() {
+					<-workerPool // release the worker back to the pool
+				}()
+				runInBackground(task)
+			}()
+		} else {
+			// handle non blocking tasks
+			task.MainTask()
+		}
+	}
 
 	// start the event loop
 	go func() {
@@ -42,32 +90,13 @@ func InitEventLoop(eventLoop *CommandEventLoop, workerPoolSize int) *sync.WaitGr
 		for {
 			select {
 			case task := <-eventLoop.MainTask:
-				if task.IsBlocking {
-					// append blocking tasks to worker pool
-					workerPool <- struct{}{} // acquire a worker
-
-					// execute blocking task in separate go routine
-					go func() {
-						defer func() {
-							<-workerPool // release the worker back to the pool
-						}()
-						task.MainTask()
-						if task.Callback != nil {
-							// If callback exists, run it after main task is completed
-							AddToTaskQueue(eventLoop, &Task{
-								MainTask: task.Callback,
-							})
-						}
-					}()
-				} else {
-					// handle non blocking tasks
-					task.MainTask()
-				}
+				runTask(task)
 			case task := <-eventLoop.CommandQueue:
 				// execute callback task
 				task.MainTask()
 			case stop := <-eventLoop.Stop:
 				if stop {
+					drainEventLoop(eventLoop, workerPool, waiting, runTask)
 					return
 				}
 			}
@@ -75,3 +104,17 @@ func InitEventLoop(eventLoop *CommandEventLoop, workerPoolSize int) *sync.WaitGr
 	}()
 	return &wg
 }
+
+// drainEventLoop runs all queued tasks and waits until every worker is released and every waiting
+// task is finished, callbacks of finishing workers are still served from the command queue
+func drainEventLoop(eventLoop *CommandEventLoop, workerPool chan struct{}, waiting *atomic.Int64, runTask func(Task)) {
+	for len(workerPool) > 0 || waiting.Load() > 0 || len(eventLoop.MainTask) > 0 || len(eventLoop.CommandQueue) > 0 {
+		select {
+		case task := <-eventLoop.MainTask:
+			runTask(task)
+		case task := <-eventLoop.CommandQueue:
+			task.MainTask()
+		case <-time.After(drainPollInterval):
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package pubsub
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+// subscriberBufferSize is the number of messages waiting for delivery to a single subscriber.
+// Publishers never wait for slow subscribers, a subscriber with full buffer is disconnected.
+const subscriberBufferSize = 1024
+
+// Message is a published message delivered to a subscriber
+type Message struct {
+	Pattern string // set when the message matched a pattern subscription
+	Channel string
+	Payload string
+}
+
+// ToResp returns the message as push data, RESP2 clients receive it as an array
+func (m Message) ToResp() respparser.Push {
+	if m.Pattern != "" {
+		return respparser.Push{Items: []respparser.RespData{
+			respparser.BulkString{Value: "pmessage"},
+			respparser.BulkString{Value: m.Pattern},
+			respparser.BulkString{Value: m.Channel},
+			respparser.BulkString{Value: m.Payload},
+		}}
+	}
+	return respparser.Push{Items: []respparser.RespData{
+		respparser.BulkString{Value: "message"},
+		respparser.BulkString{Value: m.Channel},
+		respparser.BulkString{Value: m.Payload},
+	}}
+}
+
+// Subscriber receives messages of its channels and patterns
+type Subscriber struct {
+	messages   chan Message
+	onOverflow func()
+	overflowed atomic.Bool
+
+	// remark: guarded by the registry lock
+	channels []string
+	patterns []string
+	closed   bool
+}
+
+type registry struct {
+	mu       sync.RWMutex
+	channels map[string]map[*Subscriber]struct{}
+	patterns map[string]map[*Subscriber]struct{}
+}
+
+var subscriptions = registry{
+	channels: map[string]map[*Subscriber]struct{}{},
+	patterns: map[string]map[*Subscriber]struct{}{},
+}
+
+// NewSubscriber creates a subscriber without subscriptions, onOverflow is called once
+// when a message is dropped because the subscriber doesn't read its messages fast enough
+func NewSubscriber(onOverflow func()) *Subscriber {
+	return &Subscriber{
+		messages:   make(chan Message, subscriberBufferSize),
+		onOverflow: onOverflow,
+	}
+}
+
+// Messages returns the channel messages are delivered to, it's closed by Close
+func (s *Subscriber) Messages() <-chan Message {
+	return s.messages
+}
+
+// Count returns the number of subscribed channels and patterns
+func (s *Subscriber) Count() (channels int, patterns int) {
+	subscriptions.mu.RLock()
+	defer subscriptions.mu.RUnlock()
+	return len(s.channels), len(s.patterns)
+}
+
+// Channels returns subscribed channels in the order they were subscribed
+func (s *Subscriber) Channels() []string {
+	subscriptions.mu.RLock()
+	defer subscriptions.mu.RUnlock()
+	return slices.Clone(s.channels)
+}
+
+// Patterns returns subscribed patterns in the order they were subscribed
+func (s *Subscriber) Patterns() []string {
+	subscriptions.mu.RLock()
+	defer subscriptions.mu.RUnlock()
+	return slices.Clone(s.patterns)
+}
+
+// deliver queues the message without waiting, the registry lock must be held
+func (s *Subscriber) deliver(m Message) {
+	select {
+	case s.messages <- m:
+	default:
+		if s.overflowed.CompareAndSwap(false, true) {
+			utils.LogWarning(fmt.Sprintf("(PubSub) Subscriber dropped, %d messages waiting for delivery", len(s.messages)))
+			s.onOverflow()
+		}
+	}
+}
+
+func add(subscribers map[string]map[*Subscriber]struct{}, name string, s *Subscriber) {
+	if subscribers[name] == nil {
+		subscribers[name] = map[*Subscriber]struct{}{}
+	}
+	subscribers[name][s] = struct{}{}
+}
+
+func remove(subscribers map[string]map[*Subscriber]struct{}, name string, s *Subscriber) {
+	delete(subscribers[name], s)
+	if len(subscribers[name]) == 0 {
+		delete(subscribers, name)
+	}
+}
+
+// Subscribe subscribes the channel and returns the number of subscriptions afterwards
+func Subscribe(s *Subscriber, channel string) int {
+	subscriptions.mu.Lock()
+	defer subscriptions.mu.Unlock()
+
+	if !s.closed && !slices.Contains(s.channels, channel) {
+		add(subscriptions.channels, channel, s)
+		s.channels = append(s.channels, channel)
+	}
+	return len(s.channels) + len(s.patterns)
+}
+
+// Unsubscribe unsubscribes the channel and returns the number of subscriptions afterwards
+func Unsubscribe(s *Subscriber, channel string) int {
+	subscriptions.mu.Lock()
+	defer subscriptions.mu.Unlock()
+
+	if n := slices.Index(s.channels, channel); n >= 0 {
+		remove(subscriptions.channels, channel, s)
+		s.channels = slices.Delete(s.channels, n, n+1)
+	}
+	return len(s.channels) + len(s.patterns)
+}
+
+// PSubscribe subscribes the glob pattern and returns the number of subscriptions afterwards
+func PSubscribe(s *Subscriber, pattern string) int {
+	subscriptions.mu.Lock()
+	defer subscriptions.mu.Unlock()
+
+	if !s.closed && !slices.Contains(s.patterns, pattern) {
+		add(subscriptions.patterns, pattern, s)
+		s.patterns = append(s.patterns, pattern)
+	}
+	return len(s.channels) + len(s.patterns)
+}
+
+// PUnsubscribe unsubscribes the pattern and returns the number of subscriptions afterwards
+func PUnsubscribe(s *Subscriber, pattern string) int {
+	subscriptions.mu.Lock()
+	defer subscriptions.mu.Unlock()
+
+	if n := slices.Index(s.patterns, pattern); n >= 0 {
+		remove(subscriptions.patterns, pattern, s)
+		s.patterns = slices.Delete(s.patterns, n, n+1)
+	}
+	return len(s.channels) + len(s.patterns)
+}
+
+// UnsubscribeAll unsubscribes all channels and patterns of the subscriber
+func UnsubscribeAll(s *Subscriber) {
+	subscriptions.mu.Lock()
+	defer subscriptions.mu.Unlock()
+	unsubscribeAll(s)
+}
+
+func unsubscribeAll(s *Subscriber) {
+	for _, channel := range s.channels {
+		remove(subscriptions.channels, channel, s)
+	}
+	for _, pattern := range s.patterns {
+		remove(subscriptions.patterns, pattern, s)
+	}
+	s.channels, s.patterns = nil, nil
+}
+
+// Close unsubscribes everything and closes the message channel, nothing is delivered afterwards
+func Close(s *Subscriber) {
+	subscriptions.mu.Lock()
+	defer subscriptions.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+	unsubscribeAll(s)
+	s.closed = true
+	close(s.messages)
+}
+
+// Publish delivers the message to subscribers of the channel and of matching patterns.
+// The number of receivers is returned, a subscriber matched by several patterns is counted for each.
+func Publish(channel string, payload string) int {
+	subscriptions.mu.RLock()
+	defer subscriptions.mu.RUnlock()
+
+	receivers := 0
+	for s := range subscriptions.channels[channel] {
+		s.deliver(Message{Channel: channel, Payload: payload})
+		receivers++
+	}
+	for pattern, subscribers := range subscriptions.patterns {
+		if !utils.GlobMatch(pattern, channel) {
+			continue
+		}
+		for s := range subscribers {
+			s.deliver(Message{Pattern: pattern, Channel: channel, Payload: payload})
+			receivers++
+		}
+	}
+	utils.Log(fmt.Sprintf("(PubSub) Message published to %q, receivers: %d", channel, receivers))
+	return receivers
+}
+
+// ActiveChannels returns sorted channels with at least one subscriber, optionally only channels matching the pattern
+func ActiveChannels(pattern string) []string {
+	subscriptions.mu.RLock()
+	defer subscriptions.mu.RUnlock()
+
+	channels := []string{}
+	for channel := range subscriptions.channels {
+		if pattern == "" || utils.GlobMatch(pattern, channel) {
+			channels = append(channels, channel)
+		}
+	}
+	slices.Sort(channels)
+	return channels
+}
+
+// NumSub returns the number of subscribers of the channel, pattern subscribers aren't counted
+func NumSub(channel string) int {
+	subscriptions.mu.RLock()
+	defer subscriptions.mu.RUnlock()
+	return len(subscriptions.channels[channel])
+}
+
+// NumPat returns the number of patterns subscribed by all clients
+func NumPat() int {
+	subscriptions.mu.RLock()
+	defer subscriptions.mu.RUnlock()
+	return len(subscriptions.patterns)
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/command"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/config"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+func TestAuthAndAclPermissions(t *testing.T) {
+	if err := config.Set([][2]string{{"requirepass", "secret"}}); err != nil {
+		t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+	}
+	defer config.Set([][2]string{{"requirepass", ""}})
+
+	s, exitStatus := startTestServer(t)
+	defer func() {
+		s.Shutdown(command.ShutdownOptions{})
+		<-exitStatus
+	}()
+
+	conn, replies := dialTestServer(t, s)
+
+	var steps = []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"GET", "key"}, want: "NOAUTH"},
+		{args: []string{"AUTH", "wrong"}, want: "WRONGPASS"},
+		{args: []string{"AUTH", "secret"}, want: "OK"},
+		{args: []string{"ACL", "SETUSER", "reader", "on", ">pw", "~cache:*", "+@read"}, want: "OK"},
+		{args: []string{"AUTH", "reader", "pw"}, want: "OK"},
+		{args: []string{"ACL", "WHOAMI"}, want: "NOPERM"},
+		{args: []string{"SET", "cache:1", "value"}, want: "NOPERM User reader has no permissions to run the 'set' command"},
+		{args: []string{"GET", "other"}, want: "NOPERM No permissions to access a key"},
+		{args: []string{"AUTH", "default", "secret"}, want: "OK"},
+		{args: []string{"ACL", "WHOAMI"}, want: "default"},
+	}
+
+	for _, step := range steps {
+		reply := sendTestCommand(t, conn, replies, step.args...)
+		if !strings.HasPrefix(reply.String(), step.want) {
+			t.Errorf("ERROR %v got %v, want %s", step.args, reply, step.want)
+		}
+	}
+
+	log := sendTestCommand(t, conn, replies, "ACL", "LOG").(respparser.Array)
+	if len(log.Items) != 4 {
+		t.Fatalf("ERROR got %d ACL LOG entries, want 4", len(log.Items))
+	}
+	newest := log.Items[0].(respparser.Array)
+	if newest.Items[3].String() != "key" || newest.Items[7].String() != "other" {
+		t.Errorf("ERROR unexpected newest ACL LOG entry %v", newest)
+	}
+
+	if reply := sendTestCommand(t, conn, replies, "ACL", "DELUSER", "reader"); reply.String() != "1" {
+		t.Errorf("ERROR got %v, want 1", reply)
+	}
+	sendTestCommand(t, conn, replies, "ACL", "LOG", "RESET")
+}
@@ -0,0 +1,277 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/command"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/config"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+// readTestReply reads a single reply, bulk strings are read by their length so they can hold new lines
+func readTestReply(r *bufio.Reader) (respparser.RespData, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+
+	switch respparser.RespDataType(line[0]) {
+	case respparser.TypeSimpleString:
+		return respparser.SimpleString{Value: line[1:]}, nil
+	case respparser.TypeSimpleError:
+		return respparser.SimpleError{Value: line[1:]}, nil
+	case respparser.TypeInteger:
+		value, err := strconv.Atoi(line[1:])
+		return respparser.Integer{Value: value}, err
+	case respparser.TypeBulkString:
+		length, err := strconv.Atoi(line[1:])
+		if err != nil || length < 0 {
+			return respparser.BulkString{IsNull: true}, err
+		}
+		value := make([]byte, length+2)
+		if _, err := io.ReadFull(r, value); err != nil {
+			return nil, err
+		}
+		return respparser.BulkString{Value: string(value[:length])}, nil
+	case respparser.TypeArray:
+		length, err := strconv.Atoi(line[1:])
+		if err != nil || length < 0 {
+			return respparser.Array{IsNull: true}, err
+		}
+		array := respparser.Array{Items: make([]respparser.RespData, length)}
+		for n := range length {
+			if array.Items[n], err = readTestReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return array, nil
+	case respparser.TypePush:
+		length, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		push := respparser.Push{Items: make([]respparser.RespData, length)}
+		for n := range length {
+			if push.Items[n], err = readTestReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return push, nil
+	case respparser.TypeMap:
+		length, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		m := respparser.Map{Items: make([]respparser.MapItem, length)}
+		for n := range length {
+			if m.Items[n].Key, err = readTestReply(r); err != nil {
+				return nil, err
+			}
+			if m.Items[n].Value, err = readTestReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case respparser.TypeNull:
+		return respparser.Null{}, nil
+	}
+	return nil, fmt.Errorf("unexpected reply %q", line)
+}
+
+func sendTestCommand(t *testing.T, conn net.Conn, replies *bufio.Reader, args ...string) respparser.RespData {
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := conn.Write(encodeCommand(args...)); err != nil {
+		t.Fatalf("ERROR can't send %v: %s", args, err.Error())
+	}
+	reply, err := readTestReply(replies)
+	if err != nil {
+		t.Fatalf("ERROR reply to %v expected, but err got: %s", args, err.Error())
+	}
+	return reply
+}
+
+func TestClientCommands(t *testing.T) {
+	s, exitStatus := startTestServer(t)
+	defer func() {
+		s.Shutdown(command.ShutdownOptions{})
+		<-exitStatus
+	}()
+
+	conn, replies := dialTestServer(t, s)
+	otherConn, otherReplies := dialTestServer(t, s)
+
+	id := sendTestCommand(t, conn, replies, "CLIENT", "ID").String()
+	otherId := sendTestCommand(t, otherConn, otherReplies, "CLIENT", "ID").String()
+
+	if reply := sendTestCommand(t, conn, replies, "CLIENT", "GETNAME"); !reply.(respparser.BulkString).IsNull {
+		t.Errorf("ERROR got %v, want null name", reply)
+	}
+	if reply := sendTestCommand(t, conn, replies, "CLIENT", "SETNAME", "worker 1"); reply.Type() != respparser.TypeSimpleError {
+		t.Errorf("ERROR got %v, want error for name with space", reply)
+	}
+	sendTestCommand(t, conn, replies, "CLIENT", "SETNAME", "worker-1")
+	if reply := sendTestCommand(t, conn, replies, "CLIENT", "GETNAME"); reply.String() != "worker-1" {
+		t.Errorf("ERROR got %v, want worker-1", reply)
+	}
+
+	list := sendTestCommand(t, otherConn, otherReplies, "CLIENT", "LIST", "ID", id).String()
+	for _, want := range []string{"id=" + id + " ", "name=worker-1 ", "cmd=client|getname ", "user=default"} {
+		if !strings.Contains(list, want) {
+			t.Errorf("ERROR CLIENT LIST %q doesn't contain %q", list, want)
+		}
+	}
+
+	info := sendTestCommand(t, conn, replies, "CLIENT", "INFO").String()
+	if !strings.HasPrefix(info, "id="+id+" ") || !strings.Contains(info, "cmd=client|info") {
+		t.Errorf("ERROR unexpected CLIENT INFO %q", info)
+	}
+
+	// CLIENT REPLY SKIP suppresses the reply of the next command only
+	conn.Write(encodeCommand("CLIENT", "REPLY", "SKIP"))
+	conn.Write(encodeCommand("ECHO", "skipped"))
+	if reply := sendTestCommand(t, conn, replies, "ECHO", "sent"); reply.String() != "sent" {
+		t.Errorf("ERROR got %v, want sent", reply)
+	}
+
+	// blocked reader is woken up by CLIENT UNBLOCK
+	otherConn.Write(encodeCommand("XREAD", "BLOCK", "0", "STREAMS", "unblock-stream", "$"))
+	time.Sleep(100 * time.Millisecond)
+	if !strings.Contains(sendTestCommand(t, conn, replies, "CLIENT", "LIST", "ID", otherId).String(), "flags=b") {
+		t.Errorf("ERROR blocked client should have b flag")
+	}
+	if reply := sendTestCommand(t, conn, replies, "CLIENT", "UNBLOCK", otherId, "ERROR"); reply.String() != "1" {
+		t.Errorf("ERROR got %v, want 1", reply)
+	}
+	unblocked, _ := readTestReply(otherReplies)
+	if !strings.HasPrefix(unblocked.String(), "UNBLOCKED") {
+		t.Errorf("ERROR got %v, want UNBLOCKED error", unblocked)
+	}
+	if reply := sendTestCommand(t, conn, replies, "CLIENT", "UNBLOCK", otherId); reply.String() != "0" {
+		t.Errorf("ERROR got %v, want 0 for not blocked client", reply)
+	}
+
+	// CLIENT KILL closes the other connection
+	if reply := sendTestCommand(t, conn, replies, "CLIENT", "KILL", "ID", otherId); reply.String() != "1" {
+		t.Errorf("ERROR got %v, want 1", reply)
+	}
+	otherConn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := otherReplies.ReadByte(); err == nil {
+		t.Errorf("ERROR killed connection should be closed")
+	}
+	if reply := sendTestCommand(t, conn, replies, "CLIENT", "KILL", "ID", otherId); reply.String() != "0" {
+		t.Errorf("ERROR got %v, want 0", reply)
+	}
+}
+
+func TestHelloProtocolSwitch(t *testing.T) {
+	s, exitStatus := startTestServer(t)
+	defer func() {
+		s.Shutdown(command.ShutdownOptions{})
+		<-exitStatus
+	}()
+
+	conn, replies := dialTestServer(t, s)
+
+	if reply := sendTestCommand(t, conn, replies, "HELLO", "4"); !strings.HasPrefix(reply.String(), "NOPROTO") {
+		t.Errorf("ERROR got %v, want NOPROTO error", reply)
+	}
+	if reply := sendTestCommand(t, conn, replies, "CONFIG", "GET", "port"); reply.Type() != respparser.TypeArray {
+		t.Errorf("ERROR got %v, want RESP2 array", reply)
+	}
+
+	hello, ok := sendTestCommand(t, conn, replies, "HELLO", "3", "SETNAME", "resp3-client").(respparser.Map)
+	if !ok {
+		t.Fatalf("ERROR got %v, want map", hello)
+	}
+	if hello.Items[2].Key.String() != "proto" || hello.Items[2].Value.String() != "3" {
+		t.Errorf("ERROR unexpected HELLO reply %v", hello)
+	}
+
+	if reply := sendTestCommand(t, conn, replies, "CONFIG", "GET", "port"); reply.Type() != respparser.TypeMap {
+		t.Errorf("ERROR got %v, want RESP3 map", reply)
+	}
+	if reply := sendTestCommand(t, conn, replies, "GET", "hello-missing-key"); reply.Type() != respparser.TypeNull {
+		t.Errorf("ERROR got %v, want RESP3 null", reply)
+	}
+	if info := sendTestCommand(t, conn, replies, "CLIENT", "INFO").String(); !strings.Contains(info, "name=resp3-client") || !strings.Contains(info, "resp=3") {
+		t.Errorf("ERROR unexpected CLIENT INFO %q", info)
+	}
+}
+
+func TestClientPauseWrite(t *testing.T) {
+	s, exitStatus := startTestServer(t)
+	defer func() {
+		s.Shutdown(command.ShutdownOptions{})
+		<-exitStatus
+	}()
+
+	conn, replies := dialTestServer(t, s)
+	writerConn, writerReplies := dialTestServer(t, s)
+
+	sendTestCommand(t, conn, replies, "CLIENT", "PAUSE", "5000", "WRITE")
+
+	writerConn.Write(encodeCommand("SET", "paused-key", "value"))
+	time.Sleep(100 * time.Millisecond)
+
+	// reads still work, the write is held back
+	if reply := sendTestCommand(t, conn, replies, "GET", "paused-key"); !reply.(respparser.BulkString).IsNull {
+		t.Errorf("ERROR got %v, want write to be paused", reply)
+	}
+
+	sendTestCommand(t, conn, replies, "CLIENT", "UNPAUSE")
+	writerConn.SetDeadline(time.Now().Add(5 * time.Second))
+	if reply, err := readTestReply(writerReplies); err != nil || reply.String() != "OK" {
+		t.Fatalf("ERROR OK expected, but got: %v, %v", reply, err)
+	}
+	if reply := sendTestCommand(t, conn, replies, "GET", "paused-key"); reply.String() != "value" {
+		t.Errorf("ERROR got %v, want value", reply)
+	}
+}
+
+func TestBlockedReadersDontTakeWorkers(t *testing.T) {
+	const workers = 2
+	previous := config.GetString("workers")
+	if err := config.Load("workers", strconv.Itoa(workers)); err != nil {
+		t.Fatalf("ERROR can't configure workers: %s", err.Error())
+	}
+	t.Cleanup(func() { config.Load("workers", previous) })
+
+	s, exitStatus := startTestServer(t)
+	defer func() {
+		s.Shutdown(command.ShutdownOptions{})
+		<-exitStatus
+	}()
+
+	// remark: more readers block than there are workers
+	ids := []string{}
+	blocked := []*bufio.Reader{}
+	for range workers + 1 {
+		conn, replies := dialTestServer(t, s)
+		ids = append(ids, sendTestCommand(t, conn, replies, "CLIENT", "ID").String())
+		conn.Write(encodeCommand("XREAD", "BLOCK", "0", "STREAMS", "workers-stream", "$"))
+		blocked = append(blocked, replies)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	conn, replies := dialTestServer(t, s)
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if reply := sendTestCommand(t, conn, replies, "PING"); reply.String() != "PONG" {
+		t.Errorf("ERROR got %v, want PONG", reply)
+	}
+	for n, id := range ids {
+		if reply := sendTestCommand(t, conn, replies, "CLIENT", "UNBLOCK", id); reply.String() != "1" {
+			t.Errorf("ERROR got %v, want 1", reply)
+		}
+		if reply, err := readTestReply(blocked[n]); err != nil || reply.String() != "[]" {
+			t.Errorf("ERROR got %v (%v), want nil reply of unblocked reader", reply, err)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/clients"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/command"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/eventloop"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+// ioBufferSize is the size of per connection read and write buffers
+const ioBufferSize = 16 * 1024
+
+// replyWriter serializes replies of commands with messages published to the client
+type replyWriter struct {
+	mu      sync.Mutex
+	encoder *respparser.Encoder
+}
+
+// HandleConnection serves a single client connection. Commands are read from a persistent
+// buffered reader so pipelined commands aren't lost, they are executed one by one in the
+// order they arrived and replies are flushed once there is no more pipelined input pending.
+func HandleConnection(conn net.Conn, eventLoop *eventloop.CommandEventLoop) {
+	defer conn.Close()
+
+	cmdCtx := command.NewCommandContext(clients.New(conn))
+	defer clients.Remove(cmdCtx.Client)
+
+	decoder := respparser.NewDecoder(bufio.NewReaderSize(conn, ioBufferSize))
+	writer := &replyWriter{encoder: respparser.NewEncoder(bufio.NewWriterSize(conn, ioBufferSize))}
+	defer func() {
+		writer.mu.Lock()
+		defer writer.mu.Unlock()
+		writer.encoder.Flush()
+	}()
+
+	// remark: Release closes the message channel, the delivery ends once the remaining messages are written
+	delivered := make(chan struct{})
+	go deliverMessages(cmdCtx, writer, delivered)
+	defer func() { <-delivered }()
+	defer cmdCtx.Release()
+
+	for {
+		var cmdResult command.CommandResponse
+		closeConnection := false
+		cmd, err := command.ParseCommand(decoder)
+		if errors.Is(err, respparser.ErrProtocol) {
+			// remark: the rest of the input can't be parsed reliably, reply the error and close the connection
+			utils.Log(fmt.Sprintf("(Connection handler) %s", err.Error()))
+			cmdResult = command.ErrorResponse(err)
+			closeConnection = true
+			writer.mu.Lock()
+		} else if errors.Is(err, io.EOF) {
+			utils.Log("(Connection handler) Client closed the connection")
+			break
+		} else if err != nil {
+			utils.Log(fmt.Sprintf("(Connection handler) Can't read command: %s", err.Error()))
+			break
+		} else {
+			// remark: CLIENT commands are never paused, so the pause can be lifted by CLIENT UNPAUSE
+			if cmd.CommandType != "CLIENT" {
+				clients.WaitIfPaused(command.IsWriteCommand(cmd))
+			}
+			// remark: the writer is held while the command runs, so messages of just subscribed channels
+			// don't overtake the confirmation. Blocking commands let messages through while waiting.
+			if command.IsBlockingCommand(cmd) {
+				cmdResult = executeCommand(cmd, cmdCtx, eventLoop)
+				writer.mu.Lock()
+			} else {
+				writer.mu.Lock()
+				cmdResult = executeCommand(cmd, cmdCtx, eventLoop)
+			}
+		}
+
+		stop := writeReply(writer.encoder, cmdResult, cmdCtx, closeConnection, decoder.Buffered() == 0)
+		writer.mu.Unlock()
+		if stop {
+			break
+		}
+	}
+}
+
+// writeReply writes the command result, the writer lock must be held. It reports whether the connection must be closed.
+func writeReply(encoder *respparser.Encoder, cmdResult command.CommandResponse, cmdCtx *command.CommandContext, closeConnection bool, flush bool) bool {
+	_, noReply := cmdResult.Value.(command.NoReply)
+	if cmdCtx.Client.ReplyAllowed() && !noReply {
+		utils.Log(fmt.Sprintf("(Connection handler) Sending response: %s", cmdResult.Value.DebugString()))
+
+		replies := []respparser.RespData{cmdResult.Value}
+		if multiple, ok := cmdResult.Value.(command.Replies); ok {
+			replies = multiple.Items
+		}
+		encoder.SetProtocol(cmdCtx.Client.Protocol())
+		for _, reply := range replies {
+			if encodeErr := encoder.Encode(reply); encodeErr != nil {
+				// remark: the reply may be written partially, the client can't continue reading
+				utils.Log(fmt.Sprintf("(Connection handler) Error writing response: %s", encodeErr.Error()))
+				return true
+			}
+		}
+	}
+
+	if closeConnection || cmdCtx.Client.CloseAfterReply() {
+		utils.Log("(Connection handler) Closing connection after reply")
+		return true
+	}
+
+	// remark: batch replies of pipelined commands, flush only when the client waits for them
+	if flush {
+		if flushErr := encoder.Flush(); flushErr != nil {
+			utils.Log(fmt.Sprintf("(Connection handler) Error writing client: %s", flushErr.Error()))
+			return true
+		}
+	}
+	return false
+}
+
+// deliverMessages writes messages published to subscribed channels until the message channel is closed,
+// messages are batched the same way as pipelined replies
+func deliverMessages(cmdCtx *command.CommandContext, writer *replyWriter, delivered chan<- struct{}) {
+	defer close(delivered)
+
+	messages := cmdCtx.Messages()
+	failed := false
+	for message := range messages {
+		if failed {
+			continue
+		}
+
+		writer.mu.Lock()
+		writer.encoder.SetProtocol(cmdCtx.Client.Protocol())
+		err := writer.encoder.Encode(message.ToResp())
+		if err == nil && len(messages) == 0 {
+			err = writer.encoder.Flush()
+		}
+		writer.mu.Unlock()
+
+		if err != nil {
+			// remark: the connection is broken, the remaining messages are dropped
+			utils.Log(fmt.Sprintf("(Connection handler) Error writing message: %s", err.Error()))
+			failed = true
+		}
+	}
+}
+
+// executeCommand runs the command on the event loop and waits for its result,
+// so commands of a single connection never overtake each other
+func executeCommand(cmd *command.Command, cmdCtx *command.CommandContext, eventLoop *eventloop.CommandEventLoop) command.CommandResponse {
+	result := make(chan command.CommandResponse, 1)
+
+	eventloop.Add(eventLoop, &eventloop.Task{
+		MainTask: func() {
+			result <- command.Execute(cmd, cmdCtx)
+		},
+		IsBlocking: true,
+		MayWait:    command.IsBlockingCommand(cmd),
+	})
+
+	return <-result
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/command"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/eventloop"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+func newTestEventLoop(t *testing.T) *eventloop.CommandEventLoop {
+	eventLoop := &eventloop.CommandEventLoop{
+		MainTask:     make(chan eventloop.Task, 10),
+		CommandQueue: make(chan eventloop.Task, 10),
+		Stop:         make(chan bool),
+	}
+	wg := eventloop.InitEventLoop(eventLoop, 4)
+	t.Cleanup(func() {
+		eventloop.StopEventLoop(eventLoop)
+		wg.Wait()
+	})
+	return eventLoop
+}
+
+func encodeCommand(args ...string) []byte {
+	items := make([]respparser.RespData, len(args))
+	for n, arg := range args {
+		items[n] = respparser.BulkString{Value: arg}
+	}
+	encoded, _ := respparser.Serialize(respparser.Array{Items: items})
+	return encoded
+}
+
+func TestPipelinedCommandsAreAnsweredInOrder(t *testing.T) {
+	eventLoop := newTestEventLoop(t)
+	serverConn, clientConn := net.Pipe()
+	defer clientConn.Close()
+	go HandleConnection(serverConn, eventLoop)
+
+	const numOfCommands = 3000
+
+	// every key is written and read back within the same pipeline
+	var pipeline bytes.Buffer
+	for i := range numOfCommands {
+		key := fmt.Sprintf("pipeline-key-%d", i)
+		pipeline.Write(encodeCommand("SET", key, fmt.Sprintf("value-%d", i)))
+		pipeline.Write(encodeCommand("GET", key))
+		pipeline.Write(encodeCommand("ECHO", fmt.Sprintf("echo-%d", i)))
+	}
+
+	writeErr := make(chan error, 1)
+	go func() {
+		_, err := clientConn.Write(pipeline.Bytes())
+		writeErr <- err
+	}()
+
+	replies := bufio.NewReader(clientConn)
+	for i := range numOfCommands {
+		want := []string{"OK", fmt.Sprintf("value-%d", i), fmt.Sprintf("echo-%d", i)}
+		for _, w := range want {
+			reply, err := respparser.Deserialize(replies)
+			if err != nil {
+				t.Fatalf("ERROR reply expected, but err got: %s", err.Error())
+			}
+			if reply.String() != w {
+				t.Fatalf("ERROR command %d: got %s, want %s", i, reply.String(), w)
+			}
+		}
+	}
+
+	if err := <-writeErr; err != nil {
+		t.Errorf("ERROR pipeline write failed: %s", err.Error())
+	}
+}
+
+func TestProtocolErrorClosesConnection(t *testing.T) {
+	s, exitStatus := startTestServer(t)
+	defer func() {
+		s.Shutdown(command.ShutdownOptions{})
+		<-exitStatus
+	}()
+
+	inputs := map[string]string{
+		"*2147483647\r\n":               "ERR Protocol error: invalid multibulk length",
+		"*1\r\n$2147483647\r\n":         "ERR Protocol error: invalid bulk length",
+		"*2\r\n$4\r\nECHO\r\n:1\r\n":    "ERR Protocol error: expected '$', got ':'",
+		"PING\r\nSET a \"b\r\nPING\r\n": "ERR Protocol error: unbalanced quotes in request",
+	}
+
+	for input, want := range inputs {
+		conn, replies := dialTestServer(t, s)
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+		conn.Write([]byte(input))
+
+		var reply respparser.RespData
+		var err error
+		for reply == nil || reply.Type() != respparser.TypeSimpleError {
+			if reply, err = readTestReply(replies); err != nil {
+				t.Fatalf("ERROR reply to %q expected, but err got: %s", input, err.Error())
+			}
+		}
+		if reply.String() != want {
+			t.Errorf("ERROR got %q, want %q", reply.String(), want)
+		}
+		if _, err := replies.ReadByte(); err == nil {
+			t.Errorf("ERROR connection should be closed after %q", input)
+		}
+		conn.Close()
+	}
+}
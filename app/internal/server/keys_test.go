@@ -0,0 +1,63 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/command"
+)
+
+func TestDeletedStreamWithBlockedReaders(t *testing.T) {
+	s, exitStatus := startTestServer(t)
+	defer func() {
+		s.Shutdown(command.ShutdownOptions{})
+		<-exitStatus
+	}()
+
+	conn, replies := dialTestServer(t, s)
+	blockedConn, blockedReplies := dialTestServer(t, s)
+
+	readBlocked := func() string {
+		blockedConn.SetDeadline(time.Now().Add(5 * time.Second))
+		reply, err := readTestReply(blockedReplies)
+		if err != nil {
+			t.Fatalf("ERROR reply expected, but err got: %s", err.Error())
+		}
+		return reply.String()
+	}
+
+	// remark: the reader keeps waiting when the stream is deleted, entries of the recreated stream
+	// with IDs not greater than the requested one aren't replied
+	sendTestCommand(t, conn, replies, "XADD", "keys:stream", "5-1", "field", "old")
+	blockedConn.Write(encodeCommand("XREAD", "BLOCK", "0", "STREAMS", "keys:stream", "5-1"))
+	time.Sleep(100 * time.Millisecond)
+
+	if reply := sendTestCommand(t, conn, replies, "DEL", "keys:stream"); reply.String() != "1" {
+		t.Errorf("ERROR got %v, want 1", reply)
+	}
+	sendTestCommand(t, conn, replies, "XADD", "keys:stream", "1-1", "field", "recreated")
+	sendTestCommand(t, conn, replies, "XADD", "keys:stream", "6-1", "field", "new")
+	if got, want := readBlocked(), "[[keys:stream,[[6-1,[field,new]]]]]"; got != want {
+		t.Errorf("ERROR got %q, want %q", got, want)
+	}
+
+	// remark: a stream renamed to the key wakes the reader up
+	blockedConn.Write(encodeCommand("XREAD", "BLOCK", "0", "STREAMS", "keys:target", "$"))
+	time.Sleep(100 * time.Millisecond)
+
+	sendTestCommand(t, conn, replies, "XADD", "keys:source", "7-1", "field", "moved")
+	if reply := sendTestCommand(t, conn, replies, "RENAME", "keys:source", "keys:target"); reply.String() != "OK" {
+		t.Errorf("ERROR got %v, want OK", reply)
+	}
+	if got, want := readBlocked(), "[[keys:target,[[7-1,[field,moved]]]]]"; got != want {
+		t.Errorf("ERROR got %q, want %q", got, want)
+	}
+
+	// remark: readers of other streams aren't woken up
+	blockedConn.Write(encodeCommand("XREAD", "BLOCK", "300", "STREAMS", "keys:waiting", "$"))
+	time.Sleep(100 * time.Millisecond)
+	sendTestCommand(t, conn, replies, "XADD", "keys:other", "*", "field", "value")
+	if got := readBlocked(); got != "[]" {
+		t.Errorf("ERROR got %q, want nil reply", got)
+	}
+}
@@ -0,0 +1,195 @@
+package server
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/command"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+func TestTransactions(t *testing.T) {
+	s, exitStatus := startTestServer(t)
+	defer func() {
+		s.Shutdown(command.ShutdownOptions{})
+		<-exitStatus
+	}()
+
+	conn, replies := dialTestServer(t, s)
+	var tests = []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "EXEC without MULTI", args: []string{"EXEC"}, want: "ERR EXEC without MULTI"},
+		{name: "DISCARD without MULTI", args: []string{"DISCARD"}, want: "ERR DISCARD without MULTI"},
+		{name: "MULTI", args: []string{"MULTI"}, want: "OK"},
+		{name: "Nested MULTI", args: []string{"MULTI"}, want: "ERR MULTI calls can not be nested"},
+		{name: "WATCH inside MULTI", args: []string{"WATCH", "multi:key"}, want: "ERR WATCH inside MULTI is not allowed"},
+		{name: "Commands should be queued", args: []string{"SET", "multi:key", "value"}, want: "QUEUED"},
+		{name: "Commands with runtime errors should be queued", args: []string{"XADD", "multi:stream", "0-0", "field", "value"}, want: "QUEUED"},
+		{name: "Blocking commands should be queued", args: []string{"XREAD", "BLOCK", "0", "STREAMS", "multi:stream", "$"}, want: "QUEUED"},
+		{name: "Read of a key written in the transaction", args: []string{"GET", "multi:key"}, want: "QUEUED"},
+		{name: "EXEC should reply all results", args: []string{"EXEC"}, want: "[OK,ERR The ID specified in XADD must be greater than 0-0,[],value]"},
+		{name: "MULTI after EXEC", args: []string{"MULTI"}, want: "OK"},
+		{name: "Commands should be queued again", args: []string{"SET", "multi:key", "discarded"}, want: "QUEUED"},
+		{name: "DISCARD", args: []string{"DISCARD"}, want: "OK"},
+		{name: "Discarded commands shouldn't run", args: []string{"GET", "multi:key"}, want: "value"},
+		{name: "MULTI before queue-time error", args: []string{"MULTI"}, want: "OK"},
+		{name: "Valid command", args: []string{"SET", "multi:key", "aborted"}, want: "QUEUED"},
+		{name: "Wrong number of arguments", args: []string{"GET"}, want: "ERR wrong number of arguments for 'get' command"},
+		{name: "Unknown command", args: []string{"NOSUCHCOMMAND"}, want: "ERR unknown command 'nosuchcommand', with args beginning with: "},
+		{name: "EXEC should abort", args: []string{"EXEC"}, want: "EXECABORT Transaction discarded because of previous errors."},
+		{name: "Aborted commands shouldn't run", args: []string{"GET", "multi:key"}, want: "value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if reply := sendTestCommand(t, conn, replies, tt.args...); reply.String() != tt.want {
+				t.Errorf("ERROR got %q, want %q", reply.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWatch(t *testing.T) {
+	s, exitStatus := startTestServer(t)
+	defer func() {
+		s.Shutdown(command.ShutdownOptions{})
+		<-exitStatus
+	}()
+
+	conn, replies := dialTestServer(t, s)
+	otherConn, otherReplies := dialTestServer(t, s)
+
+	var tests = []struct {
+		name    string
+		key     string
+		modify  []string // sent by the other connection after WATCH
+		unwatch bool
+		wantNil bool
+	}{
+		{name: "Unmodified key", key: "watch:string"},
+		{name: "Modified string", key: "watch:string", modify: []string{"SET", "watch:string", "other"}, wantNil: true},
+		{name: "Modified list", key: "watch:list", modify: []string{"RPUSH", "watch:list", "other"}, wantNil: true},
+		{name: "Modified stream", key: "watch:stream", modify: []string{"XADD", "watch:stream", "*", "field", "other"}, wantNil: true},
+		{name: "Read of the key", key: "watch:string", modify: []string{"GET", "watch:string"}},
+		{name: "Modified other key", key: "watch:string", modify: []string{"SET", "watch:other", "other"}},
+		// remark: write commands changing nothing don't modify the key, the same as in Redis
+		{name: "SET NX of an existing key", key: "watch:string", modify: []string{"SET", "watch:string", "nx", "NX"}},
+		{name: "DEL of a missing key", key: "watch:missing", modify: []string{"DEL", "watch:missing"}},
+		{name: "EXPIRE of a missing key", key: "watch:missing", modify: []string{"EXPIRE", "watch:missing", "100"}},
+		{name: "PERSIST of a key without expiry", key: "watch:string", modify: []string{"PERSIST", "watch:string"}},
+		{name: "RENAMENX to an existing key", key: "watch:string", modify: []string{"RENAMENX", "watch:other", "watch:string"}},
+		{name: "Expiry of the key", key: "watch:stream", modify: []string{"EXPIRE", "watch:stream", "100"}, wantNil: true},
+		{name: "Deleted key", key: "watch:list", modify: []string{"DEL", "watch:list"}, wantNil: true},
+		{name: "UNWATCH", key: "watch:string", modify: []string{"SET", "watch:string", "other"}, unwatch: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sendTestCommand(t, conn, replies, "WATCH", tt.key)
+			if tt.modify != nil {
+				sendTestCommand(t, otherConn, otherReplies, tt.modify...)
+			}
+			if tt.unwatch {
+				sendTestCommand(t, conn, replies, "UNWATCH")
+			}
+
+			sendTestCommand(t, conn, replies, "MULTI")
+			sendTestCommand(t, conn, replies, "SET", "watch:result", tt.name)
+			reply := sendTestCommand(t, conn, replies, "EXEC").(respparser.Array)
+			if reply.IsNull != tt.wantNil {
+				t.Errorf("ERROR got %v, want nil reply %t", reply, tt.wantNil)
+			}
+		})
+	}
+
+	// remark: keys are unwatched by EXEC, a later transaction isn't affected
+	sendTestCommand(t, otherConn, otherReplies, "SET", "watch:string", "again")
+	sendTestCommand(t, conn, replies, "MULTI")
+	if reply := sendTestCommand(t, conn, replies, "EXEC").(respparser.Array); reply.IsNull {
+		t.Errorf("ERROR got nil reply, want empty array")
+	}
+}
+
+func TestWatchedKeyExpiry(t *testing.T) {
+	s, exitStatus := startTestServer(t)
+	defer func() {
+		s.Shutdown(command.ShutdownOptions{})
+		<-exitStatus
+	}()
+
+	conn, replies := dialTestServer(t, s)
+
+	var tests = []struct {
+		name    string
+		expire  string // PX of the watched key
+		wantNil bool
+	}{
+		{name: "Key expired after WATCH", expire: "50", wantNil: true},
+		{name: "Key expiring after EXEC", expire: "100000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sendTestCommand(t, conn, replies, "SET", "watch:expiring", "value", "PX", tt.expire)
+			sendTestCommand(t, conn, replies, "WATCH", "watch:expiring", "watch:missing")
+			time.Sleep(100 * time.Millisecond)
+
+			sendTestCommand(t, conn, replies, "MULTI")
+			sendTestCommand(t, conn, replies, "SET", "watch:result", tt.name)
+			reply := sendTestCommand(t, conn, replies, "EXEC").(respparser.Array)
+			if reply.IsNull != tt.wantNil {
+				t.Errorf("ERROR got %v, want nil reply %t", reply, tt.wantNil)
+			}
+		})
+	}
+}
+
+func TestTransactionsAreAtomic(t *testing.T) {
+	s, exitStatus := startTestServer(t)
+	defer func() {
+		s.Shutdown(command.ShutdownOptions{})
+		<-exitStatus
+	}()
+
+	const clients, transactions, pushes = 8, 20, 10
+	wg := sync.WaitGroup{}
+	for n := range clients {
+		conn, replies := dialTestServer(t, s)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range transactions {
+				conn.Write(encodeCommand("MULTI"))
+				for range pushes {
+					conn.Write(encodeCommand("RPUSH", "atomic:list", strconv.Itoa(n)))
+				}
+				conn.Write(encodeCommand("EXEC"))
+				for range pushes + 2 {
+					if _, err := readTestReply(replies); err != nil {
+						t.Errorf("ERROR reply expected, but err got: %s", err.Error())
+						return
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	conn, replies := dialTestServer(t, s)
+	list := sendTestCommand(t, conn, replies, "LRANGE", "atomic:list", "0", "-1").(respparser.Array)
+	if len(list.Items) != pushes*clients*transactions {
+		t.Fatalf("ERROR got %d items, want %d", len(list.Items), pushes*clients*transactions)
+	}
+	for n := 0; n < len(list.Items); n += pushes {
+		for _, item := range list.Items[n : n+pushes] {
+			if item.String() != list.Items[n].String() {
+				t.Fatalf("ERROR items from %d were pushed by different transactions", n)
+			}
+		}
+	}
+}
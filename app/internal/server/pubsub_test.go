@@ -0,0 +1,147 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/command"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+func TestPubSub(t *testing.T) {
+	s, exitStatus := startTestServer(t)
+	defer func() {
+		s.Shutdown(command.ShutdownOptions{})
+		<-exitStatus
+	}()
+
+	subscriber, messages := dialTestServer(t, s)
+	publisher, replies := dialTestServer(t, s)
+
+	readMessage := func() string {
+		subscriber.SetDeadline(time.Now().Add(5 * time.Second))
+		message, err := readTestReply(messages)
+		if err != nil {
+			t.Fatalf("ERROR message expected, but err got: %s", err.Error())
+		}
+		return message.String()
+	}
+
+	var tests = []struct {
+		name      string
+		conn      string // sub or pub
+		args      []string
+		want      string
+		wantAfter []string // further replies or messages of the subscriber
+	}{
+		{name: "SUBSCRIBE should confirm each channel", conn: "sub", args: []string{"SUBSCRIBE", "pubsub:a", "pubsub:b"},
+			want: "[subscribe,pubsub:a,1]", wantAfter: []string{"[subscribe,pubsub:b,2]"}},
+		{name: "PSUBSCRIBE", conn: "sub", args: []string{"PSUBSCRIBE", "pubsub:*"}, want: "[psubscribe,pubsub:*,3]"},
+		{name: "Subscribed RESP2 client can't run other commands", conn: "sub", args: []string{"GET", "pubsub:key"},
+			want: "ERR Can't execute 'get': only (P|S)SUBSCRIBE / (P|S)UNSUBSCRIBE / PING / QUIT / RESET are allowed in this context"},
+		{name: "PING in subscribed mode", conn: "sub", args: []string{"PING"}, want: "[pong,]"},
+		{name: "PUBLISH should count channel and pattern receivers", conn: "pub", args: []string{"PUBLISH", "pubsub:a", "hello"},
+			want: "2", wantAfter: []string{"[message,pubsub:a,hello]", "[pmessage,pubsub:*,pubsub:a,hello]"}},
+		{name: "PUBLISH to pattern subscription only", conn: "pub", args: []string{"PUBLISH", "pubsub:c", "pattern"},
+			want: "1", wantAfter: []string{"[pmessage,pubsub:*,pubsub:c,pattern]"}},
+		{name: "PUBLISH without subscribers", conn: "pub", args: []string{"PUBLISH", "other", "nobody"}, want: "0"},
+		{name: "PUBSUB CHANNELS", conn: "pub", args: []string{"PUBSUB", "CHANNELS", "pubsub:*"}, want: "[pubsub:a,pubsub:b]"},
+		{name: "PUBSUB CHANNELS too many arguments", conn: "pub", args: []string{"PUBSUB", "CHANNELS", "a", "b"},
+			want: "ERR wrong number of arguments for 'pubsub|channels' command"},
+		{name: "PUBSUB NUMSUB", conn: "pub", args: []string{"PUBSUB", "NUMSUB", "pubsub:a", "pubsub:c"}, want: "[pubsub:a,1,pubsub:c,0]"},
+		{name: "PUBSUB NUMPAT", conn: "pub", args: []string{"PUBSUB", "NUMPAT"}, want: "1"},
+		{name: "UNSUBSCRIBE without channels unsubscribes all", conn: "sub", args: []string{"UNSUBSCRIBE"},
+			want: "[unsubscribe,pubsub:a,2]", wantAfter: []string{"[unsubscribe,pubsub:b,1]"}},
+		{name: "PUNSUBSCRIBE", conn: "sub", args: []string{"PUNSUBSCRIBE", "pubsub:*"}, want: "[punsubscribe,pubsub:*,0]"},
+		{name: "UNSUBSCRIBE without subscriptions", conn: "sub", args: []string{"UNSUBSCRIBE"}, want: "[unsubscribe,,0]"},
+		{name: "Commands are allowed after unsubscribing", conn: "sub", args: []string{"PING"}, want: "PONG"},
+		{name: "PUBLISH after unsubscribing", conn: "pub", args: []string{"PUBLISH", "pubsub:a", "hello"}, want: "0"},
+		{name: "SUBSCRIBE inside MULTI", conn: "sub", args: []string{"MULTI"}, want: "OK"},
+		{name: "SUBSCRIBE shouldn't be queued", conn: "sub", args: []string{"SUBSCRIBE", "pubsub:a"}, want: "ERR Command not allowed inside a transaction"},
+		{name: "RESET discards the transaction", conn: "sub", args: []string{"RESET"}, want: "RESET"},
+		{name: "SUBSCRIBE before RESET", conn: "sub", args: []string{"SUBSCRIBE", "pubsub:reset"}, want: "[subscribe,pubsub:reset,1]"},
+		{name: "RESET in subscribed mode", conn: "sub", args: []string{"RESET"}, want: "RESET"},
+		{name: "RESET unsubscribes", conn: "pub", args: []string{"PUBLISH", "pubsub:reset", "hello"}, want: "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reply respparser.RespData
+			if tt.conn == "sub" {
+				reply = sendTestCommand(t, subscriber, messages, tt.args...)
+			} else {
+				reply = sendTestCommand(t, publisher, replies, tt.args...)
+			}
+			if reply.String() != tt.want {
+				t.Errorf("ERROR got %q, want %q", reply.String(), tt.want)
+			}
+			for _, want := range tt.wantAfter {
+				if got := readMessage(); got != want {
+					t.Errorf("ERROR got %q, want %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPubSubResp3(t *testing.T) {
+	s, exitStatus := startTestServer(t)
+	defer func() {
+		s.Shutdown(command.ShutdownOptions{})
+		<-exitStatus
+	}()
+
+	subscriber, messages := dialTestServer(t, s)
+	publisher, replies := dialTestServer(t, s)
+
+	sendTestCommand(t, subscriber, messages, "HELLO", "3")
+	if reply := sendTestCommand(t, subscriber, messages, "SUBSCRIBE", "pubsub3:a"); reply.Type() != respparser.TypePush {
+		t.Errorf("ERROR got %v, want push confirmation", reply)
+	}
+	// remark: pushed messages are distinguishable from replies in RESP3, so any command is allowed
+	if reply := sendTestCommand(t, subscriber, messages, "GET", "pubsub3:key"); reply.Type() != respparser.TypeNull {
+		t.Errorf("ERROR got %v, want null", reply)
+	}
+	if info := sendTestCommand(t, publisher, replies, "CLIENT", "LIST", "TYPE", "pubsub").String(); !strings.Contains(info, "flags=P") || !strings.Contains(info, "sub=1 psub=0") {
+		t.Errorf("ERROR unexpected CLIENT LIST %q", info)
+	}
+
+	if reply := sendTestCommand(t, publisher, replies, "PUBLISH", "pubsub3:a", "hello"); reply.String() != "1" {
+		t.Errorf("ERROR got %v, want 1", reply)
+	}
+	subscriber.SetDeadline(time.Now().Add(5 * time.Second))
+	message, err := readTestReply(messages)
+	if err != nil {
+		t.Fatalf("ERROR message expected, but err got: %s", err.Error())
+	}
+	if message.Type() != respparser.TypePush || message.String() != "[message,pubsub3:a,hello]" {
+		t.Errorf("ERROR got %v, want pushed message", message)
+	}
+}
+
+func TestSlowSubscriberDoesntBlockPublisher(t *testing.T) {
+	s, exitStatus := startTestServer(t)
+	defer func() {
+		s.Shutdown(command.ShutdownOptions{})
+		<-exitStatus
+	}()
+
+	subscriber, messages := dialTestServer(t, s)
+	publisher, replies := dialTestServer(t, s)
+
+	sendTestCommand(t, subscriber, messages, "SUBSCRIBE", "pubsub:slow")
+
+	// remark: the subscriber doesn't read, messages fill socket buffers and then the subscriber's queue
+	payload := string(bytes.Repeat([]byte("x"), 16*1024))
+	for n := 0; n < 4000; n++ {
+		sendTestCommand(t, publisher, replies, "PUBLISH", "pubsub:slow", payload)
+	}
+
+	subscriber.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := io.Copy(io.Discard, messages); err != nil {
+		t.Errorf("ERROR subscriber should be disconnected, but err got: %s", err.Error())
+	}
+}
@@ -0,0 +1,272 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/command"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/config"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/eventloop"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/stats"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+)
+
+const (
+	ExitOk    = 0
+	ExitError = 1
+)
+
+type Server struct {
+	eventLoop   *eventloop.CommandEventLoop
+	eventLoopWg *sync.WaitGroup
+
+	listeners []net.Listener
+	acceptWg  sync.WaitGroup
+
+	mu          sync.Mutex
+	connections map[net.Conn]struct{}
+	connWg      sync.WaitGroup
+
+	ctx          context.Context // cancelled when the shutdown starts
+	cancel       context.CancelFunc
+	shutdownOnce sync.Once
+	done         chan int // receives exit status once the shutdown is finished
+}
+
+// New initializes the event loop using the live config
+func New() *Server {
+	queueSize := config.GetInt("eventloop-queue-size")
+	eventLoop := &eventloop.CommandEventLoop{
+		MainTask:     make(chan eventloop.Task, queueSize),
+		CommandQueue: make(chan eventloop.Task, queueSize),
+		Stop:         make(chan bool),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Server{
+		eventLoop:   eventLoop,
+		eventLoopWg: eventloop.InitEventLoop(eventLoop, config.GetInt("workers")),
+		connections: map[net.Conn]struct{}{},
+		ctx:         ctx,
+		cancel:      cancel,
+		done:        make(chan int, 1),
+	}
+
+	command.SetServerContext(ctx)
+	command.SetShutdownHandler(func(opts command.ShutdownOptions) error {
+		// remark: the command itself is in-flight, so the shutdown can't be awaited here
+		go s.Shutdown(opts)
+		return nil
+	})
+	return s
+}
+
+// Listen binds the TCP listener configured by bind and port params, the TLS listener
+// when tls-port is set and the unix socket listener when unixsocket param is set
+func (s *Server) Listen() error {
+	address := net.JoinHostPort(config.GetString("bind"), config.GetString("port"))
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("Failed to bind to %s: %s", address, err.Error())
+	}
+
+	utils.LogNotice(fmt.Sprintf("Ready to accept connections on %s", address))
+	s.listeners = append(s.listeners, l)
+
+	if tlsPort := config.GetString("tls-port"); tlsPort != "0" {
+		if err := s.listenTLS(tlsPort); err != nil {
+			s.closeListeners()
+			return err
+		}
+	}
+
+	if socketPath := config.GetString("unixsocket"); socketPath != "" {
+		if err := s.listenUnix(socketPath, config.GetFileMode("unixsocketperm")); err != nil {
+			s.closeListeners()
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) listenUnix(socketPath string, perm os.FileMode) error {
+	// remove a stale socket left by a previous run
+	if err := os.Remove(socketPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("Failed to remove stale unix socket %s: %s", socketPath, err.Error())
+	}
+
+	l, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("Failed to open unix socket %s: %s", socketPath, err.Error())
+	}
+
+	if perm != 0 {
+		if err := os.Chmod(socketPath, perm); err != nil {
+			l.Close()
+			return fmt.Errorf("Failed to set permissions of unix socket %s: %s", socketPath, err.Error())
+		}
+	}
+
+	utils.LogNotice(fmt.Sprintf("Ready to accept connections on unix socket %s", socketPath))
+	s.listeners = append(s.listeners, l)
+	return nil
+}
+
+// closeListeners closes all listeners, unix socket files are removed on close
+func (s *Server) closeListeners() bool {
+	closed := true
+	for _, l := range s.listeners {
+		if err := l.Close(); err != nil {
+			utils.LogWarning(fmt.Sprintf("Error closing listener %s: %s", l.Addr(), err.Error()))
+			closed = false
+		}
+	}
+	return closed
+}
+
+// Addrs returns addresses of all bound listeners
+func (s *Server) Addrs() []net.Addr {
+	addrs := make([]net.Addr, len(s.listeners))
+	for n, l := range s.listeners {
+		addrs[n] = l.Addr()
+	}
+	return addrs
+}
+
+// Serve accepts connections on all listeners until the server is shut down, it returns the exit status
+func (s *Server) Serve() int {
+	for _, l := range s.listeners {
+		s.acceptWg.Add(1)
+		go s.acceptConnections(l)
+	}
+	return <-s.done
+}
+
+func (s *Server) acceptConnections(l net.Listener) {
+	defer s.acceptWg.Done()
+
+	for {
+		con, err := l.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			utils.Log(fmt.Sprintf("Listener %s closed", l.Addr()))
+			return
+		} else if err != nil {
+			utils.Log(fmt.Sprintf("Error connection accept: %s", err.Error()))
+			continue
+		}
+
+		utils.Log("Opening new connection")
+		if !s.trackConnection(con) {
+			// remark: shutdown already started
+			con.Close()
+			continue
+		}
+		stats.TotalConnectionsReceived.Add(1)
+		// run connection handler in separate goroutine
+		go func() {
+			defer s.untrackConnection(con)
+			HandleConnection(con, s.eventLoop)
+		}()
+	}
+}
+
+func (s *Server) trackConnection(con net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ctx.Err() != nil {
+		return false
+	}
+	s.connections[con] = struct{}{}
+	s.connWg.Add(1)
+	return true
+}
+
+func (s *Server) untrackConnection(con net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.connections, con)
+	s.connWg.Done()
+}
+
+// eachConnection runs f on all open connections
+func (s *Server) eachConnection(f func(net.Conn)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for con := range s.connections {
+		f(con)
+	}
+}
+
+// Shutdown stops accepting connections, lets in-flight commands finish and reply,
+// drains the event loop and closes the stores. Only the first call has an effect.
+func (s *Server) Shutdown(opts command.ShutdownOptions) {
+	s.shutdownOnce.Do(func() {
+		s.done <- s.shutdown(opts)
+	})
+}
+
+func (s *Server) shutdown(opts command.ShutdownOptions) int {
+	utils.LogWarning("Received shutdown request, preparing to shut down")
+	failed := false
+
+	// stop accepting new connections
+	s.mu.Lock()
+	s.cancel() // remark: wakes up blocked clients as well
+	s.mu.Unlock()
+
+	if !s.closeListeners() {
+		failed = true
+	}
+	s.acceptWg.Wait()
+
+	if opts.Now {
+		s.eachConnection(func(con net.Conn) { con.Close() })
+	} else {
+		// stop reading further commands, running commands still reply
+		s.eachConnection(func(con net.Conn) { con.SetReadDeadline(time.Now()) })
+	}
+
+	if !s.waitForConnections(time.Duration(config.GetInt("shutdown-timeout")) * time.Second) {
+		utils.LogWarning("In-flight commands didn't finish in shutdown-timeout, closing connections")
+		s.eachConnection(func(con net.Conn) { con.Close() })
+		s.connWg.Wait()
+		failed = true
+	}
+
+	eventloop.StopEventLoop(s.eventLoop)
+	s.eventLoopWg.Wait()
+
+	if opts.Save {
+		utils.LogNotice("Persistence is not supported, there is nothing to save")
+	}
+
+	if failed && !opts.Force {
+		utils.LogWarning("Server shut down with errors")
+		return ExitError
+	}
+	utils.LogWarning("Server is now ready to exit, bye bye...")
+	return ExitOk
+}
+
+func (s *Server) waitForConnections(timeout time.Duration) bool {
+	closed := make(chan struct{})
+	go func() {
+		s.connWg.Wait()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/command"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/config"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+// startTestServer serves on a random local port, the exit status is sent to the returned channel
+func startTestServer(t *testing.T) (*Server, <-chan int) {
+	for _, nv := range [][2]string{{"bind", "127.0.0.1"}, {"port", "0"}} {
+		if err := config.Load(nv[0], nv[1]); err != nil {
+			t.Fatalf("ERROR can't configure server: %s", err.Error())
+		}
+	}
+
+	s := New()
+	if err := s.Listen(); err != nil {
+		t.Fatalf("ERROR can't start server: %s", err.Error())
+	}
+
+	exitStatus := make(chan int, 1)
+	go func() { exitStatus <- s.Serve() }()
+	return s, exitStatus
+}
+
+func dialTestServer(t *testing.T, s *Server) (net.Conn, *bufio.Reader) {
+	conn, err := net.Dial(s.Addrs()[0].Network(), s.Addrs()[0].String())
+	if err != nil {
+		t.Fatalf("ERROR can't connect to server: %s", err.Error())
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn, bufio.NewReader(conn)
+}
+
+func TestShutdownCommand(t *testing.T) {
+	s, exitStatus := startTestServer(t)
+
+	// blocked reader must be woken up with a nil reply
+	blockedConn, blockedReplies := dialTestServer(t, s)
+	blockedConn.Write(encodeCommand("XREAD", "BLOCK", "0", "STREAMS", "shutdown-stream", "$"))
+
+	conn, replies := dialTestServer(t, s)
+	conn.Write(encodeCommand("PING"))
+	if reply, err := respparser.Deserialize(replies); err != nil || reply.String() != "PONG" {
+		t.Fatalf("ERROR PONG expected, but got: %v, %v", reply, err)
+	}
+
+	// give the blocked reader time to reach the server
+	time.Sleep(100 * time.Millisecond)
+	conn.Write(encodeCommand("SHUTDOWN", "NOSAVE"))
+
+	select {
+	case status := <-exitStatus:
+		if status != ExitOk {
+			t.Errorf("ERROR got exit status %d, want %d", status, ExitOk)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("ERROR server didn't shut down")
+	}
+
+	blockedReply, err := blockedReplies.ReadString('\n')
+	if err != nil {
+		t.Fatalf("ERROR nil reply expected, but err got: %s", err.Error())
+	}
+	if blockedReply != "*-1\r\n" {
+		t.Errorf("ERROR got %q, want nil array", blockedReply)
+	}
+
+	// SHUTDOWN doesn't reply, the connection is just closed
+	if _, err := replies.ReadByte(); !errors.Is(err, io.EOF) {
+		t.Errorf("ERROR closed connection expected, but got: %v", err)
+	}
+
+	if _, err := net.Dial("tcp", s.Addrs()[0].String()); err == nil {
+		t.Errorf("ERROR server still accepts connections")
+	}
+}
+
+func TestUnixSocketSharesKeyspaceWithTcp(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "redis.sock")
+	for _, nv := range [][2]string{{"unixsocket", socketPath}, {"unixsocketperm", "700"}} {
+		if err := config.Load(nv[0], nv[1]); err != nil {
+			t.Fatalf("ERROR can't configure server: %s", err.Error())
+		}
+	}
+	t.Cleanup(func() {
+		config.Load("unixsocket", "")
+		config.Load("unixsocketperm", "0")
+	})
+
+	s, exitStatus := startTestServer(t)
+	defer func() {
+		s.Shutdown(command.ShutdownOptions{})
+		<-exitStatus
+		if _, err := os.Stat(socketPath); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("ERROR socket file should be removed on shutdown")
+		}
+	}()
+
+	info, err := os.Stat(socketPath)
+	if err != nil {
+		t.Fatalf("ERROR socket file expected, but err got: %s", err.Error())
+	}
+	if info.Mode().Perm() != 0700 {
+		t.Errorf("ERROR got socket permissions %o, want 700", info.Mode().Perm())
+	}
+
+	unixConn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		t.Fatalf("ERROR can't connect to unix socket: %s", err.Error())
+	}
+	defer unixConn.Close()
+	unixConn.Write(encodeCommand("SET", "unix-key", "unix-value"))
+	if reply, err := respparser.Deserialize(bufio.NewReader(unixConn)); err != nil || reply.String() != "OK" {
+		t.Fatalf("ERROR OK expected, but got: %v, %v", reply, err)
+	}
+
+	tcpConn, tcpReplies := dialTestServer(t, s)
+	tcpConn.Write(encodeCommand("GET", "unix-key"))
+	if reply, err := respparser.Deserialize(tcpReplies); err != nil || reply.String() != "unix-value" {
+		t.Errorf("ERROR unix-value expected, but got: %v, %v", reply, err)
+	}
+}
+
+func TestBinarySafeValues(t *testing.T) {
+	s, exitStatus := startTestServer(t)
+	defer func() {
+		s.Shutdown(command.ShutdownOptions{})
+		<-exitStatus
+	}()
+
+	conn, replies := dialTestServer(t, s)
+	key := "bin\x00key\r\n"
+	value := "\x89PNG\r\n\x1a\n\x00\xff$3\r\n*1\r\n"
+
+	sendTestCommand(t, conn, replies, "SET", key, value)
+	if reply := sendTestCommand(t, conn, replies, "GET", key); reply.String() != value {
+		t.Errorf("ERROR got %q, want %q", reply.String(), value)
+	}
+
+	sendTestCommand(t, conn, replies, "RPUSH", key+"list", value, "")
+	list := sendTestCommand(t, conn, replies, "LRANGE", key+"list", "0", "-1").(respparser.Array)
+	if len(list.Items) != 2 || list.Items[0].String() != value || list.Items[1].String() != "" {
+		t.Errorf("ERROR got %q, want [%q, \"\"]", list.Items, value)
+	}
+
+	sendTestCommand(t, conn, replies, "XADD", key+"stream", "1-1", "field\r\n", value)
+	entries := sendTestCommand(t, conn, replies, "XRANGE", key+"stream", "-", "+").(respparser.Array)
+	fields := entries.Items[0].(respparser.Array).Items[1].(respparser.Array)
+	if fields.Items[0].String() != "field\r\n" || fields.Items[1].String() != value {
+		t.Errorf("ERROR got %q, want [\"field\\r\\n\", %q]", fields.Items, value)
+	}
+}
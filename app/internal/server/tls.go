@@ -0,0 +1,80 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/config"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+)
+
+// newTLSConfig builds the server TLS config from tls-* params
+func newTLSConfig() (*tls.Config, error) {
+	certFile := config.GetString("tls-cert-file")
+	keyFile := config.GetString("tls-key-file")
+	if certFile == "" || keyFile == "" {
+		return nil, errors.New("tls-cert-file and tls-key-file must be set when tls-port is enabled")
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load TLS certificate %s: %s", certFile, err.Error())
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	authClients := strings.ToLower(config.GetString("tls-auth-clients"))
+	switch authClients {
+	case "yes":
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	case "optional":
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	default:
+		tlsConfig.ClientAuth = tls.NoClientCert
+	}
+
+	caFile := config.GetString("tls-ca-cert-file")
+	if caFile == "" {
+		if tlsConfig.ClientAuth != tls.NoClientCert {
+			return nil, errors.New("tls-ca-cert-file must be set to authenticate clients, or set tls-auth-clients no")
+		}
+		return tlsConfig, nil
+	}
+
+	caCerts, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load CA certificates %s: %s", caFile, err.Error())
+	}
+
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caCerts) {
+		return nil, fmt.Errorf("No valid CA certificate found in %s", caFile)
+	}
+	tlsConfig.ClientCAs = clientCAs
+	return tlsConfig, nil
+}
+
+func (s *Server) listenTLS(port string) error {
+	tlsConfig, err := newTLSConfig()
+	if err != nil {
+		return err
+	}
+
+	address := net.JoinHostPort(config.GetString("bind"), port)
+	l, err := tls.Listen("tcp", address, tlsConfig)
+	if err != nil {
+		return fmt.Errorf("Failed to bind TLS to %s: %s", address, err.Error())
+	}
+
+	utils.LogNotice(fmt.Sprintf("Ready to accept TLS connections on %s", address))
+	s.listeners = append(s.listeners, l)
+	return nil
+}
@@ -0,0 +1,180 @@
+package server
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/command"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/config"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
+)
+
+type testCertificate struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// newTestCertificate creates a certificate signed by parent, self-signed when parent is nil
+func newTestCertificate(t *testing.T, commonName string, parent *testCertificate) *testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ERROR can't generate key: %s", err.Error())
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	signerCert, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		signerCert, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signerCert, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("ERROR can't create certificate: %s", err.Error())
+	}
+	cert, _ := x509.ParseCertificate(der)
+	keyDer, _ := x509.MarshalECPrivateKey(key)
+
+	return &testCertificate{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}),
+	}
+}
+
+func freeTestPort(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ERROR can't find free port: %s", err.Error())
+	}
+	defer l.Close()
+	return strconv.Itoa(l.Addr().(*net.TCPAddr).Port)
+}
+
+func TestTLSConnections(t *testing.T) {
+	ca := newTestCertificate(t, "test-ca", nil)
+	serverCert := newTestCertificate(t, "server", ca)
+	clientCert := newTestCertificate(t, "client", ca)
+
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"ca.crt":     ca.certPEM,
+		"server.crt": serverCert.certPEM,
+		"server.key": serverCert.keyPEM,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0600); err != nil {
+			t.Fatalf("ERROR can't write %s: %s", name, err.Error())
+		}
+	}
+
+	clientKeyPair, err := tls.X509KeyPair(clientCert.certPEM, clientCert.keyPEM)
+	if err != nil {
+		t.Fatalf("ERROR can't load client certificate: %s", err.Error())
+	}
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(ca.cert)
+
+	var tests = []struct {
+		name        string
+		authClients string
+		clientCerts []tls.Certificate
+		wantPong    bool
+	}{
+		{
+			name:        "Client with certificate should be accepted",
+			authClients: "yes",
+			clientCerts: []tls.Certificate{clientKeyPair},
+			wantPong:    true,
+		},
+		{
+			name:        "Client without certificate should be rejected",
+			authClients: "yes",
+			wantPong:    false,
+		},
+		{
+			name:        "Client without certificate should be accepted when auth is optional",
+			authClients: "optional",
+			wantPong:    true,
+		},
+		{
+			name:        "Client without certificate should be accepted when auth is disabled",
+			authClients: "no",
+			wantPong:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tlsPort := freeTestPort(t)
+			params := [][2]string{
+				{"tls-port", tlsPort},
+				{"tls-cert-file", filepath.Join(dir, "server.crt")},
+				{"tls-key-file", filepath.Join(dir, "server.key")},
+				{"tls-ca-cert-file", filepath.Join(dir, "ca.crt")},
+				{"tls-auth-clients", tt.authClients},
+			}
+			for _, nv := range params {
+				if err := config.Load(nv[0], nv[1]); err != nil {
+					t.Fatalf("ERROR can't configure server: %s", err.Error())
+				}
+			}
+			t.Cleanup(func() { config.Load("tls-port", "0") })
+
+			s, exitStatus := startTestServer(t)
+			defer func() {
+				s.Shutdown(command.ShutdownOptions{})
+				<-exitStatus
+			}()
+
+			conn, err := tls.Dial("tcp", "127.0.0.1:"+tlsPort, &tls.Config{
+				RootCAs:      rootCAs,
+				Certificates: tt.clientCerts,
+			})
+			if err != nil {
+				if tt.wantPong {
+					t.Fatalf("ERROR can't connect: %s", err.Error())
+				}
+				return
+			}
+			defer conn.Close()
+
+			conn.SetDeadline(time.Now().Add(5 * time.Second))
+			conn.Write(encodeCommand("PING"))
+			reply, err := respparser.Deserialize(bufio.NewReader(conn))
+
+			if tt.wantPong && (err != nil || reply.String() != "PONG") {
+				t.Errorf("ERROR PONG expected, but got: %v, %v", reply, err)
+			} else if !tt.wantPong && err == nil {
+				t.Errorf("ERROR rejected connection expected, but got: %v", reply)
+			}
+		})
+	}
+}
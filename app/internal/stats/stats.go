@@ -0,0 +1,16 @@
+package stats
+
+import "sync/atomic"
+
+// Server wide counters, reset by CONFIG RESETSTAT
+var (
+	TotalConnectionsReceived atomic.Int64
+	TotalCommandsProcessed   atomic.Int64
+	TotalErrorReplies        atomic.Int64
+)
+
+func Reset() {
+	TotalConnectionsReceived.Store(0)
+	TotalCommandsProcessed.Store(0)
+	TotalErrorReplies.Store(0)
+}
@@ -0,0 +1,352 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"hash/maphash"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+)
+
+// Types of values as reported by TYPE
+const (
+	TypeNone   = "none"
+	TypeString = "string"
+	TypeList   = "list"
+	TypeStream = "stream"
+)
+
+var (
+	// ErrWrongType is returned when a key holds a value of other type than the command works with
+	ErrWrongType = errors.New("Operation against a key holding the wrong kind of value")
+	ErrNoSuchKey = errors.New("no such key")
+)
+
+// Value is a value held by a key, each data type implements it. Values are never modified
+// once stored, updates store a new value, so values looked up can be read without the lock.
+type Value interface {
+	Type() string
+	// Copy returns a value sharing nothing with the original, so both can be updated independently
+	Copy() Value
+}
+
+// entry is a key of the keyspace, expiry applies to values of every type
+type entry struct {
+	value  Value
+	expire *time.Time // nil if the key doesn't expire
+}
+
+func (e entry) expired(now time.Time) bool {
+	return e.expire != nil && now.After(*e.expire)
+}
+
+// keyspaceBuckets is the number of buckets keys are distributed to. SCAN iterates the keyspace bucket
+// by bucket, a key stays in the same bucket as long as it exists, so it can't be missed by SCAN.
+const keyspaceBuckets = 4096
+
+// Keyspace maps keys to typed values, a key holds a single value of any type
+type Keyspace struct {
+	mu      sync.RWMutex
+	buckets [keyspaceBuckets]map[string]entry
+}
+
+var keyspace = newKeyspace()
+
+// bucketSeed makes distribution of keys to buckets stable for the lifetime of the process
+var bucketSeed = maphash.MakeSeed()
+
+func newKeyspace() *Keyspace {
+	k := &Keyspace{}
+	for n := range k.buckets {
+		k.buckets[n] = map[string]entry{}
+	}
+	return k
+}
+
+func bucketOf(key string) int {
+	return int(maphash.String(bucketSeed, key) % keyspaceBuckets)
+}
+
+func (k *Keyspace) get(key string) (entry, bool) {
+	e, found := k.buckets[bucketOf(key)][key]
+	return e, found
+}
+
+func (k *Keyspace) put(key string, e entry) {
+	k.buckets[bucketOf(key)][key] = e
+}
+
+func (k *Keyspace) remove(key string) {
+	delete(k.buckets[bucketOf(key)], key)
+}
+
+// lookup returns the entry of the key, expired entries are deleted. The lock must be held exclusively.
+func lookup(key string, now time.Time) (entry, bool) {
+	e, found := keyspace.get(key)
+	if found && e.expired(now) {
+		utils.Log(fmt.Sprintf("(Keyspace) Key %q expired", key))
+		keyspace.remove(key)
+		return entry{}, false
+	}
+	return e, found
+}
+
+// lookupShared returns the entry of the key holding the lock shared, expired entries are deleted
+func lookupShared(key string) (entry, bool) {
+	keyspace.mu.RLock()
+	e, found := keyspace.get(key)
+	keyspace.mu.RUnlock()
+
+	if found && e.expired(time.Now()) {
+		keyspace.mu.Lock()
+		defer keyspace.mu.Unlock()
+		// remark: the key may have been set again since it was read
+		lookup(key, time.Now())
+		return entry{}, false
+	}
+	return e, found
+}
+
+// Lookup returns the value held by the key, expired keys don't exist
+func Lookup(key string) (Value, bool) {
+	e, found := lookupShared(key)
+	return e.value, found
+}
+
+// TypeOf returns the type of the value held by the key, TypeNone when the key doesn't exist
+func TypeOf(key string) string {
+	e, found := lookupShared(key)
+	if !found {
+		return TypeNone
+	}
+	return e.value.Type()
+}
+
+// Update replaces the value of the key by the result of update, which gets nil when the key doesn't exist.
+// Nil result deletes the key, expiry of the key is kept. Errors of update are returned and nothing is changed.
+func Update(key string, update func(current Value) (Value, error)) error {
+	keyspace.mu.Lock()
+	defer keyspace.mu.Unlock()
+
+	e, _ := lookup(key, time.Now())
+	value, err := update(e.value)
+	if err != nil {
+		return err
+	}
+
+	if value == nil {
+		keyspace.remove(key)
+		return nil
+	}
+	e.value = value
+	keyspace.put(key, e)
+	return nil
+}
+
+// Delete removes the keys and returns the removed ones, missing keys aren't returned
+func Delete(keys ...string) []string {
+	keyspace.mu.Lock()
+	defer keyspace.mu.Unlock()
+
+	deleted := []string{}
+	now := time.Now()
+	for _, key := range keys {
+		if _, found := lookup(key, now); found {
+			utils.Log(fmt.Sprintf("(Keyspace) Key %q deleted", key))
+			keyspace.remove(key)
+			deleted = append(deleted, key)
+		}
+	}
+	return deleted
+}
+
+// Exists returns the number of existing keys, a key given multiple times is counted multiple times
+func Exists(keys ...string) int {
+	count := 0
+	for _, key := range keys {
+		if _, found := lookupShared(key); found {
+			count++
+		}
+	}
+	return count
+}
+
+// Rename moves the value of the key together with its expiry to newKey, an existing value of newKey
+// is replaced unless nx is set. It reports whether the value was moved, ErrNoSuchKey when the key doesn't exist.
+func Rename(key string, newKey string, nx bool) (bool, error) {
+	keyspace.mu.Lock()
+	defer keyspace.mu.Unlock()
+
+	now := time.Now()
+	e, found := lookup(key, now)
+	if !found {
+		return false, ErrNoSuchKey
+	}
+	if _, exists := lookup(newKey, now); exists && nx {
+		return false, nil
+	}
+
+	utils.Log(fmt.Sprintf("(Keyspace) Key %q renamed to %q", key, newKey))
+	keyspace.remove(key)
+	keyspace.put(newKey, e)
+	return true, nil
+}
+
+// Copy copies the value of source together with its expiry to destination, an existing value of destination
+// is replaced only with replace set. It reports whether the value was copied.
+func Copy(source string, destination string, replace bool) bool {
+	keyspace.mu.Lock()
+	defer keyspace.mu.Unlock()
+
+	now := time.Now()
+	e, found := lookup(source, now)
+	if !found {
+		return false
+	}
+	if _, exists := lookup(destination, now); exists && !replace {
+		return false
+	}
+
+	utils.Log(fmt.Sprintf("(Keyspace) Key %q copied to %q", source, destination))
+	keyspace.put(destination, entry{value: e.value.Copy(), expire: e.expire})
+	return true
+}
+
+// RandomKey returns a random existing key, it reports false when the keyspace is empty
+func RandomKey() (string, bool) {
+	keyspace.mu.RLock()
+	defer keyspace.mu.RUnlock()
+
+	// remark: iteration starts at a random bucket and map iteration order is random, expired keys are skipped
+	now := time.Now()
+	start := rand.IntN(keyspaceBuckets)
+	for n := range keyspaceBuckets {
+		for key, e := range keyspace.buckets[(start+n)%keyspaceBuckets] {
+			if !e.expired(now) {
+				return key, true
+			}
+		}
+	}
+	return "", false
+}
+
+// ExpireCondition limits when SetExpire changes the expiry, conditions can be combined
+type ExpireCondition int
+
+const (
+	ExpireIfNone    ExpireCondition = 1 << iota // NX, only when the key doesn't expire
+	ExpireIfSet                                 // XX, only when the key already expires
+	ExpireIfGreater                             // GT, only when later than the current expiry
+	ExpireIfLess                                // LT, only when earlier than the current expiry
+)
+
+// SetExpire sets the expiry of the key when the condition holds, a key without expiry counts as expiring
+// never for GT and LT. Expiry not in the future deletes the key. It reports whether the key was changed.
+func SetExpire(key string, expire time.Time, condition ExpireCondition) bool {
+	keyspace.mu.Lock()
+	defer keyspace.mu.Unlock()
+
+	now := time.Now()
+	e, found := lookup(key, now)
+	if !found {
+		return false
+	}
+
+	switch {
+	case condition&ExpireIfNone != 0 && e.expire != nil,
+		condition&ExpireIfSet != 0 && e.expire == nil,
+		condition&ExpireIfGreater != 0 && (e.expire == nil || !expire.After(*e.expire)),
+		condition&ExpireIfLess != 0 && e.expire != nil && !expire.Before(*e.expire):
+		return false
+	}
+
+	if !expire.After(now) {
+		utils.Log(fmt.Sprintf("(Keyspace) Key %q deleted, expiry %v is in the past", key, expire))
+		keyspace.remove(key)
+		return true
+	}
+	utils.Log(fmt.Sprintf("(Keyspace) Key %q expires at %v", key, expire))
+	e.expire = &expire
+	keyspace.put(key, e)
+	return true
+}
+
+// Persist removes the expiry of the key, it reports false when the key doesn't exist or doesn't expire
+func Persist(key string) bool {
+	keyspace.mu.Lock()
+	defer keyspace.mu.Unlock()
+
+	e, found := lookup(key, time.Now())
+	if !found || e.expire == nil {
+		return false
+	}
+	e.expire = nil
+	keyspace.put(key, e)
+	return true
+}
+
+// ExpireOf returns the expiry of the key, nil when the key doesn't expire
+func ExpireOf(key string) (expire *time.Time, found bool) {
+	e, found := lookupShared(key)
+	return e.expire, found
+}
+
+// Keys returns existing keys matching the glob-style pattern
+func Keys(pattern string) []string {
+	keyspace.mu.RLock()
+	defer keyspace.mu.RUnlock()
+
+	keys := []string{}
+	now := time.Now()
+	for _, bucket := range keyspace.buckets {
+		for key, e := range bucket {
+			if !e.expired(now) && utils.GlobMatch(pattern, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+// ScanFilter limits keys returned by Scan, empty fields match every key
+type ScanFilter struct {
+	Pattern string
+	Type    string
+}
+
+func (f ScanFilter) matches(key string, e entry) bool {
+	if f.Pattern != "" && !utils.GlobMatch(f.Pattern, key) {
+		return false
+	}
+	return f.Type == "" || e.value.Type() == f.Type
+}
+
+// Scan returns keys of buckets starting at the cursor until at least count keys are visited and the cursor
+// to continue with, zero once the whole keyspace is iterated. Every key existing during the whole iteration
+// is returned, keys added or deleted meanwhile may or may not be returned. Keys not matching the filter
+// are visited but not returned, so fewer keys than count or no keys at all may be returned.
+func Scan(cursor uint64, count int, filter ScanFilter) ([]string, uint64) {
+	keyspace.mu.RLock()
+	defer keyspace.mu.RUnlock()
+
+	keys := []string{}
+	now := time.Now()
+	visited := 0
+	for cursor < keyspaceBuckets && visited < count {
+		for key, e := range keyspace.buckets[cursor] {
+			visited++
+			if !e.expired(now) && filter.matches(key, e) {
+				keys = append(keys, key)
+			}
+		}
+		cursor++
+	}
+
+	if cursor >= keyspaceBuckets {
+		return keys, 0
+	}
+	return keys, cursor
+}
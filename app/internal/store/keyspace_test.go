@@ -0,0 +1,68 @@
+package store
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestKeyspaceExpiry(t *testing.T) {
+	if _, err := AppendList(ListStoreValue{Key: "keyspace-test:list", Values: []string{"a"}}); err != nil {
+		t.Fatalf("ERROR result expected, but err got: %s", err)
+	}
+
+	expired := time.Now().Add(-time.Second)
+	keyspace.mu.Lock()
+	e, _ := keyspace.get("keyspace-test:list")
+	e.expire = &expired
+	keyspace.put("keyspace-test:list", e)
+	keyspace.mu.Unlock()
+
+	if keyType := TypeOf("keyspace-test:list"); keyType != TypeNone {
+		t.Errorf("ERROR got %q, want %q", keyType, TypeNone)
+	}
+	if _, found, err := GetList("keyspace-test:list"); found || err != nil {
+		t.Errorf("ERROR got found %t, err %v, want expired list", found, err)
+	}
+
+	// remark: an expired value of other type doesn't cause a type mismatch
+	if _, _, err := Get("keyspace-test:list"); err != nil {
+		t.Errorf("ERROR result expected, but err got: %s", err)
+	}
+	if length, err := AppendList(ListStoreValue{Key: "keyspace-test:list", Values: []string{"b"}}); err != nil || length != 1 {
+		t.Errorf("ERROR got %d (%v), want new list of 1 element", length, err)
+	}
+}
+
+func TestScanDuringModification(t *testing.T) {
+	stable := map[string]bool{}
+	for n := range 200 {
+		key := fmt.Sprintf("scan-test:stable:%d", n)
+		Append(KeyStoreValue{Key: key, Value: "value"})
+		stable[key] = false
+	}
+
+	// remark: keys are added and deleted between the calls, the stable keys must be returned anyway
+	cursor, calls := uint64(0), 0
+	for {
+		var keys []string
+		keys, cursor = Scan(cursor, 5, ScanFilter{Pattern: "scan-test:*"})
+		for _, key := range keys {
+			if _, ok := stable[key]; ok {
+				stable[key] = true
+			}
+		}
+		Append(KeyStoreValue{Key: fmt.Sprintf("scan-test:added:%d", calls), Value: "value"})
+		Delete(fmt.Sprintf("scan-test:added:%d", calls/2))
+		calls++
+		if cursor == 0 {
+			break
+		}
+	}
+
+	for key, returned := range stable {
+		if !returned {
+			t.Errorf("ERROR key %q not returned by SCAN", key)
+		}
+	}
+}
@@ -2,21 +2,11 @@ package store
 
 import (
 	"fmt"
-	"sync"
 	"time"
 
 	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
 )
 
-type KeyStore struct {
-	mu    sync.RWMutex
-	store map[string]KeyStoreValue
-}
-
-var keyStore = KeyStore{
-	store: map[string]KeyStoreValue{},
-}
-
 type KeyStoreValue struct {
 	Key              string
 	Value            string
@@ -24,27 +14,78 @@ type KeyStoreValue struct {
 	Expire           *time.Time // Optional: nil if not set
 }
 
+// SetCondition limits when Set stores the value
+type SetCondition int
+
+const (
+	SetAlways      SetCondition = iota
+	SetIfNotExists              // NX, only when the key doesn't exist
+	SetIfExists                 // XX, only when the key already exists
+)
+
+// stringValue is the value held by string keys
+type stringValue struct {
+	value    string
+	inserted time.Time
+}
+
+func (v stringValue) Type() string { return TypeString }
+func (v stringValue) Copy() Value  { return v }
+
+func toKeyStoreValue(key string, e entry) KeyStoreValue {
+	value := e.value.(stringValue)
+	return KeyStoreValue{Key: key, Value: value.value, InsertedDatetime: value.inserted, Expire: e.expire}
+}
+
+// Append stores the string value, the existing value of any type is replaced
 func Append(value KeyStoreValue) {
-	keyStore.mu.Lock()
-	defer keyStore.mu.Unlock()
+	keyspace.mu.Lock()
+	defer keyspace.mu.Unlock()
+
+	utils.Log(fmt.Sprintf("(KeyValueStore) Append: key = %q, value = %q", value.Key, value.Value))
+	keyspace.put(value.Key, entry{value: stringValue{value: value.Value, inserted: value.InsertedDatetime}, expire: value.Expire})
+}
+
+// Set stores the value when the condition holds, with keepTtl the expiry of the existing value is kept.
+// The existing value is returned together with whether the new value was stored. Values of other types
+// are replaced, unless getOld requests the existing value, then ErrWrongType is returned.
+func Set(value KeyStoreValue, condition SetCondition, keepTtl bool, getOld bool) (old KeyStoreValue, found bool, stored bool, err error) {
+	keyspace.mu.Lock()
+	defer keyspace.mu.Unlock()
+
+	e, found := lookup(value.Key, time.Now())
+	if found && e.value.Type() != TypeString {
+		if getOld {
+			return KeyStoreValue{}, false, false, ErrWrongType
+		}
+	} else if found {
+		old = toKeyStoreValue(value.Key, e)
+	}
 
-	utils.Log(fmt.Sprintf("(KeyValueStore) Append: key = %s, value = %s", value.Key, value.Value))
-	keyStore.store[value.Key] = value
+	if (condition == SetIfNotExists && found) || (condition == SetIfExists && !found) {
+		utils.Log(fmt.Sprintf("(KeyValueStore) Set: key = %q not stored, condition not met", value.Key))
+		return old, found, false, nil
+	}
+	if keepTtl && found {
+		value.Expire = e.expire
+	}
+
+	utils.Log(fmt.Sprintf("(KeyValueStore) Set: key = %q, value = %q", value.Key, value.Value))
+	keyspace.put(value.Key, entry{value: stringValue{value: value.Value, inserted: value.InsertedDatetime}, expire: value.Expire})
+	return old, found, true, nil
 }
 
-func Get(key string) (KeyStoreValue, bool) {
-	keyStore.mu.RLock()
-	get, found := keyStore.store[key]
-	keyStore.mu.RUnlock()
-
-	if get.Expire != nil && time.Now().After(*get.Expire) {
-		keyStore.mu.Lock()
-		utils.Log(fmt.Sprintf("(KeyValueStore) Get key = %s expired", key))
-		delete(keyStore.store, key)
-		keyStore.mu.Unlock()
-		return KeyStoreValue{}, false
+// Get returns the string value of the key, ErrWrongType when the key holds a value of other type
+func Get(key string) (KeyStoreValue, bool, error) {
+	e, found := lookupShared(key)
+	if !found {
+		return KeyStoreValue{}, false, nil
+	}
+	if e.value.Type() != TypeString {
+		return KeyStoreValue{}, false, ErrWrongType
 	}
 
-	utils.Log(fmt.Sprintf("(KeyValueStore) Get key = %s, value = %s", key, get.Value))
-	return get, found
+	value := toKeyStoreValue(key, e)
+	utils.Log(fmt.Sprintf("(KeyValueStore) Get key = %q, value = %q", key, value.Value))
+	return value, true, nil
 }
@@ -2,57 +2,56 @@ package store
 
 import (
 	"fmt"
-	"sync"
+	"slices"
 
 	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
 )
 
-type SimpleStore[T any] interface {
-	// Interface definition for simple store
-	Append(value T) int
-	Get(key string) (T, bool)
-}
-
-// TODO interface definition for blocking store
-
 type ListStoreValue struct {
 	Key    string
 	Values []string
 }
 
-type ListStore struct {
-	mu    sync.RWMutex
-	store map[string][]string
+// listValue is the value held by list keys
+type listValue struct {
+	values []string
 }
 
-var ListStoreLive = ListStore{
-	store: make(map[string][]string),
+func (v listValue) Type() string { return TypeList }
+func (v listValue) Copy() Value  { return listValue{values: slices.Clone(v.values)} }
+
+// AppendList appends values to the tail of the list, the list is created when the key doesn't exist.
+// The length of the list is returned, ErrWrongType when the key holds a value of other type.
+func AppendList(list ListStoreValue) (int, error) {
+	length := 0
+	err := Update(list.Key, func(current Value) (Value, error) {
+		existing, ok := current.(listValue)
+		if current != nil && !ok {
+			return nil, ErrWrongType
+		}
+
+		if current != nil {
+			utils.Log(fmt.Sprintf("(ListStore) List %s found, tailing value %s", list.Key, list.Values))
+		} else {
+			utils.Log(fmt.Sprintf("(ListStore) List %s not found, making new one and tailing value %s", list.Key, list.Values))
+		}
+		// remark: readers may hold the stored slice, elements are only added past its length
+		updated := listValue{values: append(existing.values, list.Values...)}
+		length = len(updated.values)
+		return updated, nil
+	})
+	return length, err
 }
 
-func (ls *ListStore) Append(list ListStoreValue) int {
-	ls.mu.Lock()
-	defer ls.mu.Unlock()
-	existingList, found := ls.store[list.Key]
-	if found {
-		utils.Log(fmt.Sprintf("(ListStore) List %s found, tailing value %s", list.Key, list.Values))
-		ls.store[list.Key] = append(existingList, list.Values...)
-	} else {
-		utils.Log(fmt.Sprintf("(ListStore) List %s not found, making new one and tailing value %s", list.Key, list.Values))
-		ls.store[list.Key] = list.Values
+// GetList returns elements of the list, ErrWrongType when the key holds a value of other type
+func GetList(key string) (ListStoreValue, bool, error) {
+	value, found := Lookup(key)
+	if !found {
+		return ListStoreValue{Key: key}, false, nil
 	}
-
-	numOfElements := len(ls.store[list.Key])
-	return numOfElements
-}
-
-func (ls *ListStore) Get(key string) (ListStoreValue, bool) {
-	ls.mu.Lock()
-	defer ls.mu.Unlock()
-
-	list, found := ls.store[key]
-	listStore := ListStoreValue{
-		Key:    key,
-		Values: list,
+	list, ok := value.(listValue)
+	if !ok {
+		return ListStoreValue{Key: key}, false, ErrWrongType
 	}
-	return listStore, found
+	return ListStoreValue{Key: key, Values: slices.Clip(list.values)}, true, nil
 }
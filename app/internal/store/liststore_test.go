@@ -1,6 +1,7 @@
 package store
 
 import (
+	"slices"
 	"testing"
 )
 
@@ -26,12 +27,21 @@ func TestListStore(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 
-			inserted := ListStoreLive.Append(tt.input)
+			inserted, err := AppendList(tt.input)
+			if err != nil {
+				t.Fatalf("ERROR result expected, but err got: %s", err)
+			}
 			if inserted != len(tt.input.Values) {
 				t.Errorf("ERROR Expected same number of elements after insert: %d but got: %d", len(tt.input.Values), inserted)
 			}
 
-			// TODO retrieve test
+			list, found, err := GetList(tt.input.Key)
+			if err != nil || !found {
+				t.Fatalf("ERROR list expected, but found %t, err got: %v", found, err)
+			}
+			if !slices.Equal(list.Values, tt.want.Values) {
+				t.Errorf("ERROR got %v, want %v", list.Values, tt.want.Values)
+			}
 		})
 	}
 }
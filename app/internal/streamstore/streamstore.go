@@ -3,80 +3,121 @@ package streamstore
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 	"sync"
 	"time"
 
 	"math"
 
-	"github.com/codecrafters-io/redis-starter-go/app/internal/respparser"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/store"
 	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+	"github.com/codecrafters-io/redis-starter-go/app/respparser"
 )
 
-type StreamStore struct {
-	mu    sync.RWMutex
-	store map[string][]RedisStream
+// Stream is the value held by stream keys, entries are ordered by their IDs
+type Stream struct {
+	entries []RedisStream
 }
 
-var streamStoreChannel chan RedisStream
-var notificationChannel chan RedisStream
-var streamStore StreamStore
+func (s Stream) Type() string { return store.TypeStream }
 
-func InitStreamStore() {
-	streamStoreChannel = make(chan RedisStream)
-	streamStore = StreamStore{
-		store: map[string][]RedisStream{},
-	}
-}
+func (s Stream) Copy() store.Value { return Stream{entries: slices.Clone(s.entries)} }
 
-func StreamStoreListener() {
-	defer close(streamStoreChannel)
-
-	utils.Log("(StreamStoreListener) Starting listener")
-	if streamStoreChannel != nil {
-		for {
-			value, chanOk := <-streamStoreChannel
+// waiters maps stream keys to channels of readers blocked by XREAD BLOCK
+var waiters = struct {
+	mu   sync.Mutex
+	keys map[string]map[chan struct{}]struct{}
+}{
+	keys: map[string]map[chan struct{}]struct{}{},
+}
 
-			select {
-			case notificationChannel <- value:
-			default:
-				// no listeners, skip notification
-			}
+// Wait registers a reader blocked on the stream, the returned channel is signalled when entries are added
+// to the stream, then the reader reads the stream again. The reader keeps waiting when the stream is deleted,
+// it's signalled once a stream of the same key is created again. The returned function must be called
+// once the reader stops waiting.
+func Wait(streamKey string) (<-chan struct{}, func()) {
+	added := make(chan struct{}, 1)
+
+	waiters.mu.Lock()
+	defer waiters.mu.Unlock()
+	if waiters.keys[streamKey] == nil {
+		waiters.keys[streamKey] = map[chan struct{}]struct{}{}
+	}
+	waiters.keys[streamKey][added] = struct{}{}
+
+	return added, func() {
+		waiters.mu.Lock()
+		defer waiters.mu.Unlock()
+		delete(waiters.keys[streamKey], added)
+		if len(waiters.keys[streamKey]) == 0 {
+			delete(waiters.keys, streamKey)
+		}
+	}
+}
 
-			if !chanOk {
-				utils.Log("(StreamStoreListener) Channel closed")
-				break
-			}
+// NotifyWaiters signals readers blocked on the stream, e.g. when a stream is moved to the key by RENAME
+func NotifyWaiters(streamKey string) {
+	waiters.mu.Lock()
+	defer waiters.mu.Unlock()
 
-			streamStore.mu.Lock()
-			stream, found := streamStore.store[value.StreamKey]
-
-			if found {
-				// update existing strem
-				utils.Log(fmt.Sprintf("(StreamStoreListener) Append: StreamKey = %s - appending to an existing stream", value.StreamKey))
-				appendStream := append(stream, value)
-				streamStore.store[value.StreamKey] = appendStream
-			} else {
-				// create new stream
-				utils.Log(fmt.Sprintf("(StreamStoreListener) Append: StreamKey = %s - creating a new stream", value.StreamKey))
-				streamStore.store[value.StreamKey] = []RedisStream{value}
-			}
-			streamStore.mu.Unlock()
+	for added := range waiters.keys[streamKey] {
+		select {
+		case added <- struct{}{}:
+		default:
+			// remark: the reader is signalled already and didn't read the stream yet
 		}
 	}
-	utils.Log("(StreamStoreListener) Closing listener")
 }
 
-func GetStreamStoreChannel() chan<- RedisStream {
-	return streamStoreChannel
+// getStream returns entries of the stream, ErrWrongType when the key holds a value of other type
+func getStream(streamKey string) ([]RedisStream, bool, error) {
+	value, found := store.Lookup(streamKey)
+	if !found {
+		return nil, false, nil
+	}
+	stream, ok := value.(Stream)
+	if !ok {
+		return nil, false, store.ErrWrongType
+	}
+	return stream.entries, true, nil
 }
 
-func GetStreamNotificationChannel() <-chan RedisStream {
-	// TODO refactor mutex?
-	if notificationChannel == nil {
-		notificationChannel = make(chan RedisStream)
+// AddEntry appends the entry created by build to the stream, the stream is created when the key doesn't exist.
+// Build gets the top item of the stream, nil for empty streams, so it can generate and validate the entry ID.
+func AddEntry(streamKey string, build func(topItem *RedisStream) (RedisStream, error)) (RedisStream, error) {
+	var added RedisStream
+	err := store.Update(streamKey, func(current store.Value) (store.Value, error) {
+		stream, ok := current.(Stream)
+		if current != nil && !ok {
+			return nil, store.ErrWrongType
+		}
+
+		var topItem *RedisStream
+		if len(stream.entries) > 0 {
+			topItem = &stream.entries[len(stream.entries)-1]
+		}
+		entry, err := build(topItem)
+		if err != nil {
+			return nil, err
+		}
+
+		if current != nil {
+			utils.Log(fmt.Sprintf("(StreamStore) Append: StreamKey = %s - appending to an existing stream", streamKey))
+		} else {
+			utils.Log(fmt.Sprintf("(StreamStore) Append: StreamKey = %s - creating a new stream", streamKey))
+		}
+		added = entry
+		// remark: readers may hold the stored slice, entries are only added past its length
+		return Stream{entries: append(stream.entries, entry)}, nil
+	})
+	if err != nil {
+		return RedisStream{}, err
 	}
-	return notificationChannel
+
+	NotifyWaiters(streamKey)
+	return added, nil
 }
 
 // streamKey ->
@@ -92,31 +133,19 @@ type RedisStream struct {
 	InsertedDatetime        time.Time
 }
 
+// ToRespArray returns the entry as [id, fields], fields are a map so RESP3 clients get them natively
 func (s RedisStream) ToRespArray() respparser.Array {
-	// create key - value arrays
-	keyValues := []respparser.RespData{}
-	for k, v := range s.StreamValues {
-		keyValue := respparser.Array{
-			Items: []respparser.RespData{
-				respparser.BulkString{
-					Value: k,
-				},
-				respparser.BulkString{
-					Value: v,
-				},
-			},
-		}
-		keyValues = append(keyValues, keyValue)
+	fields := respparser.Map{}
+	for _, k := range slices.Sorted(maps.Keys(s.StreamValues)) {
+		fields.Add(k, respparser.BulkString{Value: s.StreamValues[k]})
 	}
 
-	streamIdArray := respparser.Array{
-		Items: append([]respparser.RespData{
-			respparser.BulkString{
-				Value: s.StreamId(),
-			},
-		}, keyValues...),
+	return respparser.Array{
+		Items: []respparser.RespData{
+			respparser.BulkString{Value: s.StreamId()},
+			fields,
+		},
 	}
-	return streamIdArray
 }
 
 func (s RedisStream) StreamId() string {
@@ -125,28 +154,22 @@ func (s RedisStream) StreamId() string {
 	return fmt.Sprintf("%s-%s", millisStr, seqNumStr)
 }
 
-func GetTopItem(streamKey string) (RedisStream, bool) {
-	streamStore.mu.RLock()
-	defer streamStore.mu.RUnlock()
-
-	stream, found := streamStore.store[streamKey]
+func GetTopItem(streamKey string) (RedisStream, bool, error) {
+	stream, found, err := getStream(streamKey)
 	utils.Log(fmt.Sprintf("(StreamStoreValue) Get: StreamKey = %s, found = %t", streamKey, found))
-	if (!found) || len(stream) < 1 {
-		return RedisStream{}, false
+	if err != nil || len(stream) < 1 {
+		return RedisStream{}, false, err
 	}
 	last := stream[len(stream)-1]
-	return last, true
+	return last, true, nil
 }
 
-func GetItems(streamKey string, startMillis int64, endMillis int64, startSequenceNumber int, endSequenceNumber int) ([]RedisStream, bool) {
-	streamStore.mu.RLock()
-	defer streamStore.mu.RUnlock()
-
+func GetItems(streamKey string, startMillis int64, endMillis int64, startSequenceNumber int, endSequenceNumber int) ([]RedisStream, bool, error) {
 	var result []RedisStream
-	stream, found := streamStore.store[streamKey]
+	stream, found, err := getStream(streamKey)
 	utils.Log(fmt.Sprintf("(StreamStoreValue) GetItems: StreamKey = %s, found = %t", streamKey, found))
-	if (!found) || len(stream) < 1 {
-		return result, false
+	if err != nil || len(stream) < 1 {
+		return result, false, err
 	}
 
 	// use default end sequence number when not defined
@@ -179,25 +202,22 @@ func GetItems(streamKey string, startMillis int64, endMillis int64, startSequenc
 		}
 	}
 
-	return result, true
+	return result, true, nil
 }
 
-func GetItemsByFilter(streamKey string, filter func(i []RedisStream) []RedisStream) ([]RedisStream, bool) {
-	streamStore.mu.RLock()
-	defer streamStore.mu.RUnlock()
-
+func GetItemsByFilter(streamKey string, filter func(i []RedisStream) []RedisStream) ([]RedisStream, bool, error) {
 	var result []RedisStream
-	stream, found := streamStore.store[streamKey]
+	stream, found, err := getStream(streamKey)
 	utils.Log(fmt.Sprintf("(StreamStoreValue)(GetItemsByFilter) GetItems: StreamKey = %s, found = %t", streamKey, found))
-	if (!found) || len(stream) < 1 {
-		return result, false
+	if err != nil || len(stream) < 1 {
+		return result, false, err
 	}
 
 	filteredStream := filter(stream)
 	if len(filteredStream) > 0 {
-		return filteredStream, true
+		return filteredStream, true, nil
 	} else {
-		return nil, false
+		return nil, false, nil
 	}
 }
 
@@ -214,7 +234,10 @@ func GetItemsByFilterChan(streamKey string, filter func(i []RedisStream) []Redis
 				utils.Log(fmt.Sprintf("(StreamStoreValue)(GetItemsByFilterChan) GetItems: StreamKey = %s, timeout", streamKey))
 				return
 			default:
-				result, found := GetItemsByFilter(streamKey, filter)
+				result, found, err := GetItemsByFilter(streamKey, filter)
+				if err != nil {
+					return
+				}
 				if found {
 					utils.Log(fmt.Sprintf("(StreamStoreValue)(GetItemsByFilterChan) GetItems: StreamKey = %s, result size: %d", streamKey, len(result)))
 					resultChannel <- result
@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var ErrUnbalancedQuotes = errors.New("unbalanced quotes")
+
+// SplitArgs splits a line into arguments using the same rules as redis-cli and redis.conf:
+// arguments are separated by spaces, "double quoted" arguments support \n, \r, \t, \b, \a
+// and \xHH escapes, 'single quoted' arguments support only \' escape.
+func SplitArgs(line string) ([]string, error) {
+	args := []string{}
+	i := 0
+
+	for {
+		// skip blanks
+		for i < len(line) && isArgSpace(line[i]) {
+			i++
+		}
+		if i >= len(line) {
+			return args, nil
+		}
+
+		var current strings.Builder
+		inDoubleQuotes, inSingleQuotes := false, false
+		done := false
+
+		for !done {
+			if inDoubleQuotes {
+				if i >= len(line) {
+					return nil, ErrUnbalancedQuotes
+				}
+				c := line[i]
+				if c == '\\' && i+3 < len(line) && line[i+1] == 'x' && isHexDigit(line[i+2]) && isHexDigit(line[i+3]) {
+					b, _ := strconv.ParseUint(line[i+2:i+4], 16, 8)
+					current.WriteByte(byte(b))
+					i += 3
+				} else if c == '\\' && i+1 < len(line) {
+					i++
+					switch line[i] {
+					case 'n':
+						current.WriteByte('\n')
+					case 'r':
+						current.WriteByte('\r')
+					case 't':
+						current.WriteByte('\t')
+					case 'b':
+						current.WriteByte('\b')
+					case 'a':
+						current.WriteByte('\a')
+					default:
+						current.WriteByte(line[i])
+					}
+				} else if c == '"' {
+					// closing quote must be followed by a space or nothing at all
+					if i+1 < len(line) && !isArgSpace(line[i+1]) {
+						return nil, ErrUnbalancedQuotes
+					}
+					done = true
+				} else {
+					current.WriteByte(c)
+				}
+			} else if inSingleQuotes {
+				if i >= len(line) {
+					return nil, ErrUnbalancedQuotes
+				}
+				c := line[i]
+				if c == '\\' && i+1 < len(line) && line[i+1] == '\'' {
+					i++
+					current.WriteByte('\'')
+				} else if c == '\'' {
+					if i+1 < len(line) && !isArgSpace(line[i+1]) {
+						return nil, ErrUnbalancedQuotes
+					}
+					done = true
+				} else {
+					current.WriteByte(c)
+				}
+			} else {
+				if i >= len(line) {
+					break
+				}
+				switch c := line[i]; c {
+				case ' ', '\n', '\r', '\t', 0:
+					done = true
+				case '"':
+					inDoubleQuotes = true
+				case '\'':
+					inSingleQuotes = true
+				default:
+					current.WriteByte(c)
+				}
+			}
+			if i < len(line) {
+				i++
+			}
+		}
+		args = append(args, current.String())
+	}
+}
+
+// QuoteArg returns the argument unchanged when SplitArgs would read it back as is,
+// otherwise it's returned double quoted with non printable bytes escaped
+func QuoteArg(arg string) string {
+	needsQuotes := arg == ""
+	for i := 0; i < len(arg) && !needsQuotes; i++ {
+		c := arg[i]
+		needsQuotes = c <= ' ' || c >= 0x7f || c == '"' || c == '\'' || c == '\\'
+	}
+	if !needsQuotes {
+		return arg
+	}
+
+	var quoted strings.Builder
+	quoted.WriteByte('"')
+	for i := 0; i < len(arg); i++ {
+		switch c := arg[i]; c {
+		case '\\', '"':
+			quoted.WriteByte('\\')
+			quoted.WriteByte(c)
+		case '\n':
+			quoted.WriteString("\\n")
+		case '\r':
+			quoted.WriteString("\\r")
+		case '\t':
+			quoted.WriteString("\\t")
+		case '\a':
+			quoted.WriteString("\\a")
+		case '\b':
+			quoted.WriteString("\\b")
+		default:
+			if c < ' ' || c >= 0x7f {
+				quoted.WriteString("\\x")
+				quoted.WriteString(strconv.FormatUint(uint64(c)|0x100, 16)[1:])
+			} else {
+				quoted.WriteByte(c)
+			}
+		}
+	}
+	quoted.WriteByte('"')
+	return quoted.String()
+}
+
+func isArgSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\v' || c == '\f'
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestSplitArgs(t *testing.T) {
+	var tests = []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "Plain arguments should be split",
+			input: "SET  key value",
+			want:  []string{"SET", "key", "value"},
+		},
+		{
+			name:  "Double quoted argument with escapes should be parsed",
+			input: `SET key "hello \"world\"\r\n\x41"`,
+			want:  []string{"SET", "key", "hello \"world\"\r\nA"},
+		},
+		{
+			name:  "Single quoted argument should be parsed",
+			input: `SET key 'it\'s "raw" \n'`,
+			want:  []string{"SET", "key", `it's "raw" \n`},
+		},
+		{
+			name:  "Empty quoted argument should be kept",
+			input: `SET key ""`,
+			want:  []string{"SET", "key", ""},
+		},
+		{
+			name:  "Empty line has no arguments",
+			input: "   ",
+			want:  []string{},
+		},
+		{
+			name:    "Unterminated quotes should fail",
+			input:   `SET key "value`,
+			wantErr: true,
+		},
+		{
+			name:    "Closing quote followed by a char should fail",
+			input:   `SET key "value"x`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ans, err := SplitArgs(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ERROR error expected, but got %q", ans)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+			}
+			if len(ans) != len(tt.want) {
+				t.Fatalf("ERROR got %q, want %q", ans, tt.want)
+			}
+			for n := range ans {
+				if ans[n] != tt.want[n] {
+					t.Errorf("ERROR got %q, want %q", ans, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestQuoteArgRoundTrip(t *testing.T) {
+	inputs := []string{"plain", "", "with space", "quote\"s", "bin\x00\xff\r\n", `back\slash`}
+
+	for _, input := range inputs {
+		args, err := SplitArgs(QuoteArg(input))
+		if err != nil {
+			t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+		}
+		if len(args) != 1 || args[0] != input {
+			t.Errorf("ERROR got %q, want %q", args, input)
+		}
+	}
+}
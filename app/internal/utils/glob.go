@@ -0,0 +1,118 @@
+package utils
+
+// GlobMatch reports whether s matches the Redis style glob pattern.
+// Supported syntax: '*', '?', '[abc]', '[^abc]', '[a-z]' and '\' escaping.
+func GlobMatch(pattern string, s string) bool {
+	return globMatch(pattern, s, false)
+}
+
+// GlobMatchNoCase is the case insensitive variant of GlobMatch
+func GlobMatchNoCase(pattern string, s string) bool {
+	return globMatch(pattern, s, true)
+}
+
+func globMatch(pattern string, s string, nocase bool) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			// collapse consecutive stars
+			for len(pattern) > 1 && pattern[1] == '*' {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 1 {
+				return true
+			}
+			for i := 0; i <= len(s); i++ {
+				if globMatch(pattern[1:], s[i:], nocase) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(s) == 0 {
+				return false
+			}
+			s = s[1:]
+		case '[':
+			if len(s) == 0 {
+				return false
+			}
+			var matched bool
+			matched, pattern = matchGlobClass(pattern[1:], s[0], nocase)
+			if !matched {
+				return false
+			}
+			s = s[1:]
+			// remark: pattern already points at the closing bracket
+		case '\\':
+			if len(pattern) >= 2 {
+				pattern = pattern[1:]
+			}
+			fallthrough
+		default:
+			if len(s) == 0 || !sameByte(pattern[0], s[0], nocase) {
+				return false
+			}
+			s = s[1:]
+		}
+		pattern = pattern[1:]
+	}
+	return len(s) == 0
+}
+
+// matchGlobClass matches c against the character class starting right after '['.
+// It returns the rest of the pattern starting at the closing ']' (or the last byte of an unterminated class).
+func matchGlobClass(pattern string, c byte, nocase bool) (bool, string) {
+	negate := len(pattern) > 0 && pattern[0] == '^'
+	if negate {
+		pattern = pattern[1:]
+	}
+
+	matched := false
+	for {
+		if len(pattern) == 0 {
+			// unterminated class, keep the last byte so the caller can consume it
+			return matched != negate, "]"
+		}
+		if pattern[0] == ']' {
+			break
+		}
+		if pattern[0] == '\\' && len(pattern) >= 2 {
+			pattern = pattern[1:]
+			if sameByte(pattern[0], c, nocase) {
+				matched = true
+			}
+		} else if len(pattern) >= 3 && pattern[1] == '-' {
+			start, end := pattern[0], pattern[2]
+			if start > end {
+				start, end = end, start
+			}
+			lc := c
+			if nocase {
+				start, end, lc = toLower(start), toLower(end), toLower(c)
+			}
+			if lc >= start && lc <= end {
+				matched = true
+			}
+			pattern = pattern[2:]
+		} else if sameByte(pattern[0], c, nocase) {
+			matched = true
+		}
+		pattern = pattern[1:]
+	}
+	return matched != negate, pattern
+}
+
+func sameByte(a byte, b byte, nocase bool) bool {
+	if nocase {
+		return toLower(a) == toLower(b)
+	}
+	return a == b
+}
+
+func toLower(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + ('a' - 'A')
+	}
+	return c
+}
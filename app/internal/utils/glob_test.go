@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestGlobMatch(t *testing.T) {
+	var tests = []struct {
+		pattern string
+		input   string
+		want    bool
+	}{
+		{pattern: "*", input: "anything", want: true},
+		{pattern: "*", input: "", want: true},
+		{pattern: "h?llo", input: "hello", want: true},
+		{pattern: "h?llo", input: "hllo", want: false},
+		{pattern: "h*llo", input: "heeeello", want: true},
+		{pattern: "h[ae]llo", input: "hallo", want: true},
+		{pattern: "h[ae]llo", input: "hillo", want: false},
+		{pattern: "h[^e]llo", input: "hallo", want: true},
+		{pattern: "h[^e]llo", input: "hello", want: false},
+		{pattern: "h[a-b]llo", input: "hbllo", want: true},
+		{pattern: "h[b-a]llo", input: "hallo", want: true},
+		{pattern: "h\\*llo", input: "h*llo", want: true},
+		{pattern: "h\\*llo", input: "hello", want: false},
+		{pattern: "user:*:name", input: "user:42:name", want: true},
+		{pattern: "user:*:name", input: "user:42:mail", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.input, func(t *testing.T) {
+			if ans := GlobMatch(tt.pattern, tt.input); ans != tt.want {
+				t.Errorf("ERROR got %t, want %t", ans, tt.want)
+			}
+		})
+	}
+}
@@ -2,10 +2,64 @@ package utils
 
 import (
 	"fmt"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
+type LogLevel int32
+
+const (
+	LogLevelDebug LogLevel = iota
+	LogLevelVerbose
+	LogLevelNotice
+	LogLevelWarning
+	LogLevelNothing
+)
+
+var logLevelNames = map[string]LogLevel{
+	"debug":   LogLevelDebug,
+	"verbose": LogLevelVerbose,
+	"notice":  LogLevelNotice,
+	"warning": LogLevelWarning,
+	"nothing": LogLevelNothing,
+}
+
+var logLevel atomic.Int32
+
+func ParseLogLevel(level string) (LogLevel, error) {
+	parsed, found := logLevelNames[strings.ToLower(level)]
+	if !found {
+		return LogLevelDebug, fmt.Errorf("unknown log level %s", level)
+	}
+	return parsed, nil
+}
+
+func SetLogLevel(level LogLevel) {
+	logLevel.Store(int32(level))
+}
+
+// Log writes a debug message, the default level used across the server
 func Log(message string) {
+	logAt(LogLevelDebug, message)
+}
+
+func LogVerbose(message string) {
+	logAt(LogLevelVerbose, message)
+}
+
+func LogNotice(message string) {
+	logAt(LogLevelNotice, message)
+}
+
+func LogWarning(message string) {
+	logAt(LogLevelWarning, message)
+}
+
+func logAt(level LogLevel, message string) {
+	if level < LogLevel(logLevel.Load()) {
+		return
+	}
 	now := time.Now().Truncate(time.Second)
 	logMessage := fmt.Sprintf("[%s] %s", now.Format("2006-01-02 15:04:05"), message)
 	fmt.Println(logMessage)
@@ -1,15 +1,18 @@
 package main
 
 import (
-	"bufio"
+	"errors"
+	"flag"
 	"fmt"
-	"net"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
+	"github.com/codecrafters-io/redis-starter-go/app/internal/acl"
 	"github.com/codecrafters-io/redis-starter-go/app/internal/command"
-	"github.com/codecrafters-io/redis-starter-go/app/internal/eventloop"
-	"github.com/codecrafters-io/redis-starter-go/app/internal/respparser"
-	"github.com/codecrafters-io/redis-starter-go/app/internal/streamstore"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/config"
+	"github.com/codecrafters-io/redis-starter-go/app/internal/server"
 	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
 )
 
@@ -17,99 +20,76 @@ func main() {
 	// You can use print statements as follows for debugging, they'll be visible when running tests.
 	utils.Log("Logs from your program will appear here!")
 
-	// Init stream store
-	streamstore.InitStreamStore()
-	go streamstore.StreamStoreListener()
-
-	// Init event loop
-	eventLoop := eventloop.CommandEventLoop{
-		MainTask:     make(chan eventloop.Task, 10),
-		CommandQueue: make(chan eventloop.Task, 10),
-		Stop:         make(chan bool),
+	if err := loadConfig(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
 	}
-	wg := eventloop.InitEventLoop(&eventLoop, 10)
-
-	l, err := net.Listen("tcp", "0.0.0.0:6379")
-	if err != nil {
-		utils.Log("Failed to bind to port 6379")
+	if err := loadAclFile(); err != nil {
+		utils.LogWarning(err.Error())
 		os.Exit(1)
 	}
 
-	defer func() {
-		l.Close()
-		eventloop.StopEventLoop(&eventLoop)
-		wg.Wait()
-	}()
-
-	for {
-		con, err := l.Accept()
-		utils.Log("Opening new connection")
-		if err != nil {
-			utils.Log(fmt.Sprintf("Error connection accept: %s", err.Error()))
-		}
-		// run connection handler in separate goroutine
-		go handleConnection(con, &eventLoop)
+	s := server.New()
+	if err := s.Listen(); err != nil {
+		utils.LogWarning(err.Error())
+		os.Exit(1)
 	}
+
+	go handleSignals(s)
+	os.Exit(s.Serve())
 }
 
-func handleCommandRequest(r *bufio.Reader) command.CommandResponse {
-	cmd, err := command.ParseCommand(r) // TODO err handling
-	if err != nil {
-		return command.ErrorResponse(err)
-	}
+// handleSignals shuts the server down gracefully on SIGINT or SIGTERM,
+// a second signal exits immediately
+func handleSignals(s *server.Server) {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
 
-	utils.Log(fmt.Sprintf("(Request handler) Command: %s", cmd))
+	sig := <-signals
+	utils.LogWarning(fmt.Sprintf("Received %s scheduling shutdown...", sig))
+	go s.Shutdown(command.ShutdownOptions{})
 
-	commandHandler, err := command.GetCommandHandler(cmd)
-	if err != nil {
-		return command.ErrorResponse(err)
-	}
+	sig = <-signals
+	utils.LogWarning(fmt.Sprintf("Received %s during shutdown, exiting now", sig))
+	os.Exit(server.ExitError)
+}
 
-	cmdResponse, err := commandHandler.Process()
-	if err != nil {
-		return command.ErrorResponse(err)
+// loadConfig reads the optional config file passed as the first argument and applies
+// command line flags on top of it, every config param is exposed as a flag (e.g. --port 6380)
+func loadConfig(args []string) error {
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		if err := config.LoadFile(args[0]); err != nil {
+			return err
+		}
+		args = args[1:]
 	}
 
-	utils.Log(fmt.Sprintf("(Request handler) Command %s result: %s", cmd.CommandType, cmdResponse.String()))
-
-	response := command.CommandResponse{
-		Value: cmdResponse,
+	flags := flag.NewFlagSet("redis-server", flag.ContinueOnError)
+	for _, p := range config.Params() {
+		flags.String(p.Name, p.Default, p.Usage)
 	}
-	return response
-}
 
-func handleConnection(conn net.Conn, eventLoop *eventloop.CommandEventLoop) {
-	defer conn.Close()
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
 
-	for {
-		commandReader := bufio.NewReader(conn)
-		commandDataType, err := commandReader.Peek(1)
-		if err != nil {
-			utils.Log("(Connection handler) Can't read data from incoming connection")
-			break
+	var loadErr error
+	flags.Visit(func(f *flag.Flag) {
+		if loadErr == nil {
+			loadErr = config.Load(f.Name, f.Value.String())
 		}
+	})
+	return loadErr
+}
 
-		utils.Log(fmt.Sprintf("(Connection handler) Received new data with type: %v", commandDataType))
-
-		eventloop.Add(eventLoop, &eventloop.Task{
-			// TODO split command processing and client write
-			MainTask: func() {
-				cmdResult := handleCommandRequest(commandReader)
-				utils.Log(fmt.Sprintf("(Connection handler) Sending response: %v", cmdResult.Value))
-
-				encodedResp, serializationErr := respparser.Serialize(cmdResult.Value)
-				if serializationErr != nil {
-					utils.Log(fmt.Sprintf("(Connection handler) Error writing client: %s", serializationErr.Error()))
-					// TODO return error!
-				}
-
-				_, writeErr := conn.Write(encodedResp)
-				if writeErr != nil {
-					utils.Log(fmt.Sprintf("(Connection handler) Error writing client: %s", writeErr.Error()))
-					// TODO return error!
-				}
-			},
-			IsBlocking: true,
-		})
+// loadAclFile loads users from the aclfile, users can't be defined by both aclfile and requirepass
+func loadAclFile() error {
+	path := config.GetString("aclfile")
+	if path == "" {
+		return nil
+	}
+	if config.GetString("requirepass") != "" {
+		return errors.New("Configuring Redis with users defined in redis.conf and at the same time setting 'aclfile' is not supported")
 	}
+	return acl.LoadFile(path)
 }
@@ -0,0 +1,85 @@
+package respparser
+
+import (
+	"bufio"
+	"errors"
+	"io"
+)
+
+// Decoder reads RESP data from a buffered reader. Decode returns
+//   - io.EOF when the stream ends cleanly between two values
+//   - io.ErrUnexpectedEOF when the stream ends in the middle of a value
+//   - *ProtocolError (matching ErrProtocol) for malformed data
+//   - any other read error as is
+type Decoder struct {
+	r       *bufio.Reader
+	replies bool // replies aren't limited by the configured request limits
+}
+
+// NewDecoder creates a decoder of requests, a *bufio.Reader is used directly without another buffer
+func NewDecoder(r io.Reader) *Decoder {
+	br, ok := r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &Decoder{r: br}
+}
+
+// NewReplyDecoder creates a decoder of replies, which may be larger than requests accepted by the server
+func NewReplyDecoder(r io.Reader) *Decoder {
+	d := NewDecoder(r)
+	d.replies = true
+	return d
+}
+
+// limits are read on each value, so limits changed by CONFIG SET apply to the next request
+func (d *Decoder) limits() limits {
+	if d.replies {
+		return replyLimits
+	}
+	return requestLimits()
+}
+
+func (d *Decoder) Decode() (RespData, error) {
+	if _, err := d.r.Peek(1); err != nil {
+		return nil, err
+	}
+	data, err := deserialize(d.r, d.limits(), 0)
+	return data, unexpectedEOF(err)
+}
+
+// DecodeArray reads a value which must be an array
+func (d *Decoder) DecodeArray() (Array, error) {
+	if _, err := d.r.Peek(1); err != nil {
+		return Array{}, err
+	}
+	array, err := deserializeArray(d.r, d.limits(), 0)
+	return array, unexpectedEOF(err)
+}
+
+// PeekType returns type of the next value without consuming it
+func (d *Decoder) PeekType() (RespDataType, error) {
+	b, err := d.r.Peek(1)
+	if err != nil {
+		return 0, err
+	}
+	return RespDataType(b[0]), nil
+}
+
+// ReadLine reads a plain text line, e.g. an inline command
+func (d *Decoder) ReadLine(tooLongReason string) (string, error) {
+	line, err := ReadLine(d.r, tooLongReason)
+	return line, unexpectedEOF(err)
+}
+
+// Buffered returns number of bytes received but not decoded yet
+func (d *Decoder) Buffered() int {
+	return d.r.Buffered()
+}
+
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,66 @@
+package respparser
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDecoderErrors(t *testing.T) {
+	var tests = []struct {
+		name    string
+		input   string
+		want    []string // string values of decoded items
+		wantErr error
+	}{
+		{
+			name:    "Clean end of stream should be io.EOF",
+			input:   "+OK\r\n:1\r\n",
+			want:    []string{"OK", "1"},
+			wantErr: io.EOF,
+		},
+		{
+			name:    "End of stream inside a bulk string should be unexpected",
+			input:   "+OK\r\n$5\r\nab",
+			want:    []string{"OK"},
+			wantErr: io.ErrUnexpectedEOF,
+		},
+		{
+			name:    "End of stream inside an array should be unexpected",
+			input:   "*2\r\n:1\r\n",
+			wantErr: io.ErrUnexpectedEOF,
+		},
+		{
+			name:    "End of stream inside a header should be unexpected",
+			input:   "*2",
+			wantErr: io.ErrUnexpectedEOF,
+		},
+		{
+			name:    "Malformed data should be a protocol error",
+			input:   ":1\r\n?\r\n",
+			want:    []string{"1"},
+			wantErr: ErrProtocol,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDecoder(strings.NewReader(tt.input))
+			for _, want := range tt.want {
+				ans, err := d.Decode()
+				if err != nil {
+					t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+				}
+				if ans.String() != want {
+					t.Errorf("ERROR got %v, want %v", ans, want)
+				}
+			}
+
+			_, err := d.Decode()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ERROR got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
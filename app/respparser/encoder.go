@@ -0,0 +1,281 @@
+package respparser
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+var ErrUnsupportedType = errors.New("(RESP Serialize) Unsupported resp data type")
+
+// Encoder writes RESP data to a buffered writer. Nested items are written in place,
+// no intermediate buffers are built, so large replies are streamed item by item.
+type Encoder struct {
+	w        *bufio.Writer
+	protocol Protocol
+	scratch  []byte // reused for formatting numbers
+	err      error  // first write error, the encoder is unusable after it
+}
+
+// NewEncoder creates a RESP2 encoder, a *bufio.Writer is used directly without another buffer
+func NewEncoder(w io.Writer) *Encoder {
+	bw, ok := w.(*bufio.Writer)
+	if !ok {
+		bw = bufio.NewWriter(w)
+	}
+	return &Encoder{
+		w:        bw,
+		protocol: Resp2,
+		scratch:  make([]byte, 0, 32),
+	}
+}
+
+// SetProtocol switches the encoder to the protocol, RESP3 types are flattened for RESP2
+func (e *Encoder) SetProtocol(protocol Protocol) {
+	e.protocol = protocol
+}
+
+func (e *Encoder) Flush() error {
+	if e.err != nil {
+		return e.err
+	}
+	e.err = e.w.Flush()
+	return e.err
+}
+
+// Buffered returns number of bytes written but not flushed yet
+func (e *Encoder) Buffered() int {
+	return e.w.Buffered()
+}
+
+// Encode writes the data to the buffer, call Flush to send it
+func (e *Encoder) Encode(data RespData) error {
+	if e.err != nil {
+		return e.err
+	}
+
+	switch d := data.(type) {
+	case SimpleString:
+		e.writeLine(TypeSimpleString, d.Value)
+	case SimpleError:
+		e.writeLine(TypeSimpleError, d.Value)
+	case Integer:
+		e.writeInt(TypeInteger, int64(d.Value))
+	case BulkString:
+		if d.IsNull {
+			e.writeNull(TypeBulkString)
+		} else {
+			e.writeBulk(TypeBulkString, d.Value)
+		}
+	case Array:
+		if d.IsNull {
+			e.writeNull(TypeArray)
+			return e.err
+		}
+		return e.encodeItems(TypeArray, d.Items)
+	case ArrayStream:
+		e.WriteArrayHeader(d.Count)
+		for n := 0; n < d.Count && e.err == nil; n++ {
+			e.Encode(d.Item(n))
+		}
+	case Null:
+		e.writeNull(TypeBulkString)
+	case Boolean:
+		switch {
+		case e.protocol == Resp3 && d.Value:
+			e.writeLine(TypeBoolean, "t")
+		case e.protocol == Resp3:
+			e.writeLine(TypeBoolean, "f")
+		case d.Value:
+			e.writeInt(TypeInteger, 1)
+		default:
+			e.writeInt(TypeInteger, 0)
+		}
+	case Double:
+		if e.protocol == Resp3 {
+			e.writeLine(TypeDouble, d.String())
+		} else {
+			e.writeBulk(TypeBulkString, d.String())
+		}
+	case BigNumber:
+		if e.protocol == Resp3 {
+			e.writeLine(TypeBigNumber, d.Value)
+		} else {
+			e.writeBulk(TypeBulkString, d.Value)
+		}
+	case VerbatimString:
+		if e.protocol == Resp3 {
+			e.writeBulk(TypeVerbatimString, d.Format+":"+d.Value)
+		} else {
+			e.writeBulk(TypeBulkString, d.Value)
+		}
+	case Map:
+		return e.encodeMapItems(TypeMap, d.Items)
+	case Set:
+		return e.encodeItems(e.resp3Type(TypeSet), d.Items)
+	case Push:
+		return e.encodeItems(e.resp3Type(TypePush), d.Items)
+	case Attribute:
+		// remark: attributes are auxiliary data, RESP2 clients get the reply only
+		if e.protocol == Resp3 {
+			if err := e.encodeMapItems(TypeAttribute, d.Items); err != nil {
+				return err
+			}
+		}
+		return e.Encode(d.Value)
+	default:
+		return ErrUnsupportedType
+	}
+	return e.err
+}
+
+// WriteArrayHeader starts an array of count items, the items are written by following Encode calls
+func (e *Encoder) WriteArrayHeader(count int) error {
+	e.writeInt(TypeArray, int64(count))
+	return e.err
+}
+
+// WriteMapHeader starts a map of count pairs, keys and values are written by following Encode calls
+func (e *Encoder) WriteMapHeader(count int) error {
+	if e.protocol == Resp3 {
+		e.writeInt(TypeMap, int64(count))
+	} else {
+		e.writeInt(TypeArray, int64(2*count))
+	}
+	return e.err
+}
+
+// resp3Type returns the aggregate type for the protocol, RESP2 knows arrays only
+func (e *Encoder) resp3Type(dataType RespDataType) RespDataType {
+	if e.protocol == Resp3 {
+		return dataType
+	}
+	return TypeArray
+}
+
+func (e *Encoder) encodeItems(dataType RespDataType, items []RespData) error {
+	e.writeInt(dataType, int64(len(items)))
+	for _, item := range items {
+		if err := e.Encode(item); err != nil {
+			return err
+		}
+	}
+	return e.err
+}
+
+func (e *Encoder) encodeMapItems(dataType RespDataType, items []MapItem) error {
+	if dataType == TypeMap {
+		e.WriteMapHeader(len(items))
+	} else {
+		e.writeInt(dataType, int64(len(items)))
+	}
+	for _, item := range items {
+		if err := e.Encode(item.Key); err != nil {
+			return err
+		}
+		if err := e.Encode(item.Value); err != nil {
+			return err
+		}
+	}
+	return e.err
+}
+
+func (e *Encoder) writeNull(resp2Type RespDataType) {
+	if e.protocol == Resp3 {
+		e.writeLine(TypeNull, "")
+	} else {
+		e.writeLine(resp2Type, "-1")
+	}
+}
+
+func (e *Encoder) writeLine(dataType RespDataType, value string) {
+	if e.err != nil {
+		return
+	}
+	e.w.WriteByte(byte(dataType))
+	e.w.WriteString(value)
+	_, e.err = e.w.Write(respSeparator)
+}
+
+func (e *Encoder) writeInt(dataType RespDataType, value int64) {
+	if e.err != nil {
+		return
+	}
+	e.scratch = append(e.scratch[:0], byte(dataType))
+	e.scratch = strconv.AppendInt(e.scratch, value, 10)
+	e.scratch = append(e.scratch, respSeparator...)
+	_, e.err = e.w.Write(e.scratch)
+}
+
+func (e *Encoder) writeBulk(dataType RespDataType, value string) {
+	e.writeInt(dataType, int64(len(value)))
+	if e.err != nil {
+		return
+	}
+	e.w.WriteString(value)
+	_, e.err = e.w.Write(respSeparator)
+}
+
+// ArrayStream is an array whose items are created one by one while it is being encoded,
+// so large replies don't have to be materialized at once
+type ArrayStream struct {
+	Count int
+	Item  func(n int) RespData
+}
+
+func (a ArrayStream) Type() RespDataType { return TypeArray }
+func (a ArrayStream) String() string {
+	items := make([]RespData, a.Count)
+	for n := range a.Count {
+		items[n] = a.Item(n)
+	}
+	return Array{Items: items}.String()
+}
+func (a ArrayStream) DebugString() string { return fmt.Sprintf("Array stream of %d items", a.Count) }
+
+// Serialize encodes the data for RESP2 clients
+func Serialize(data RespData) ([]byte, error) {
+	return SerializeProtocol(data, Resp2)
+}
+
+// SerializeProtocol encodes the data for a client speaking the protocol.
+// RESP3 types are flattened to their RESP2 counterparts for RESP2 clients.
+func SerializeProtocol(data RespData, protocol Protocol) ([]byte, error) {
+	var buf bytes.Buffer
+	e := NewEncoder(&buf)
+	e.SetProtocol(protocol)
+	if err := e.Encode(data); err != nil {
+		return []byte{}, err
+	}
+	if err := e.Flush(); err != nil {
+		return []byte{}, err
+	}
+	return buf.Bytes(), nil
+}
+
+func SerializeArray(r Array) ([]byte, error) {
+	return Serialize(r)
+}
+
+func SerializeBulkString(r BulkString) []byte {
+	b, _ := Serialize(r)
+	return b
+}
+
+func SerializeInteger(r Integer) []byte {
+	b, _ := Serialize(r)
+	return b
+}
+
+func SerializeSimpleString(r SimpleString) []byte {
+	b, _ := Serialize(r)
+	return b
+}
+
+func SerializeSimpleError(r SimpleError) []byte {
+	b, _ := Serialize(r)
+	return b
+}
@@ -0,0 +1,93 @@
+package respparser
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestEncoderStreamsItems(t *testing.T) {
+	var buf bytes.Buffer
+	e := NewEncoder(&buf)
+
+	e.WriteArrayHeader(3)
+	for _, item := range []RespData{BulkString{Value: "a"}, Integer{Value: -7}, BulkString{IsNull: true}} {
+		if err := e.Encode(item); err != nil {
+			t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+		}
+	}
+	if buf.Len() != 0 {
+		t.Errorf("ERROR nothing should be written before flush, got %q", buf.String())
+	}
+	if err := e.Flush(); err != nil {
+		t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+	}
+
+	want := "*3\r\n$1\r\na\r\n:-7\r\n$-1\r\n"
+	if buf.String() != want {
+		t.Errorf("ERROR got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestEncoderArrayStream(t *testing.T) {
+	stream := ArrayStream{Count: 2, Item: func(n int) RespData {
+		m := Map{}
+		m.Add("n", Integer{Value: n})
+		return m
+	}}
+
+	for protocol, want := range map[Protocol]string{
+		Resp2: "*2\r\n*2\r\n$1\r\nn\r\n:0\r\n*2\r\n$1\r\nn\r\n:1\r\n",
+		Resp3: "*2\r\n%1\r\n$1\r\nn\r\n:0\r\n%1\r\n$1\r\nn\r\n:1\r\n",
+	} {
+		got, err := SerializeProtocol(stream, protocol)
+		if err != nil {
+			t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+		}
+		if string(got) != want {
+			t.Errorf("ERROR protocol %d got %q, want %q", protocol, got, want)
+		}
+	}
+}
+
+func TestEncoderDoesNotAllocate(t *testing.T) {
+	items := make([]RespData, 1000)
+	for n := range items {
+		items[n] = BulkString{Value: "value"}
+	}
+	reply := Array{Items: items}
+	e := NewEncoder(bufio.NewWriterSize(io.Discard, 64*1024))
+
+	allocs := testing.AllocsPerRun(10, func() {
+		e.Encode(reply)
+		e.Flush()
+	})
+	if allocs > 0 {
+		t.Errorf("ERROR got %v allocations per encoded array, want 0", allocs)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) { return 0, errors.New("broken pipe") }
+
+func TestEncoderKeepsWriteError(t *testing.T) {
+	e := NewEncoder(bufio.NewWriterSize(failingWriter{}, 16))
+
+	e.Encode(BulkString{Value: "longer than the buffer"})
+	if err := e.Encode(SimpleString{Value: "OK"}); err == nil {
+		t.Errorf("ERROR write error expected")
+	}
+	if err := e.Flush(); err == nil {
+		t.Errorf("ERROR write error expected on flush")
+	}
+}
+
+func TestEncoderUnsupportedType(t *testing.T) {
+	type unknown struct{ Null }
+	if _, err := Serialize(unknown{}); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("ERROR got %v, want %v", err, ErrUnsupportedType)
+	}
+}
@@ -0,0 +1,102 @@
+package respparser
+
+import (
+	"bufio"
+	"math"
+	"sync/atomic"
+)
+
+const (
+	DefaultMaxBulkLen      = 512 * 1024 * 1024
+	DefaultMaxMultibulkLen = 1024 * 1024
+
+	// MaxNestingDepth limits nesting of aggregate types, e.g. arrays of arrays
+	MaxNestingDepth = 32
+	// MaxLineLen limits lines of inline commands and type headers
+	MaxLineLen = 64 * 1024
+
+	// preallocLimit caps memory allocated ahead of data that really arrived,
+	// so a client can't make the server allocate by announcing huge lengths
+	preallocLimit = 64 * 1024
+)
+
+var (
+	maxBulkLen      atomic.Int64
+	maxMultibulkLen atomic.Int64
+)
+
+func init() {
+	maxBulkLen.Store(DefaultMaxBulkLen)
+	maxMultibulkLen.Store(DefaultMaxMultibulkLen)
+}
+
+// SetMaxBulkLen limits length of a single bulk string
+func SetMaxBulkLen(maxLen int64) {
+	maxBulkLen.Store(maxLen)
+}
+
+// SetMaxMultibulkLen limits number of elements of a single aggregate type
+func SetMaxMultibulkLen(maxLen int64) {
+	maxMultibulkLen.Store(maxLen)
+}
+
+// limits bounds values accepted by a decoder, zero fields don't limit
+type limits struct {
+	bulkLen      int64
+	multibulkLen int64
+	nestingDepth int
+}
+
+// requestLimits returns the configured limits of requests, so clients can't exhaust memory of the server
+func requestLimits() limits {
+	return limits{bulkLen: maxBulkLen.Load(), multibulkLen: maxMultibulkLen.Load(), nestingDepth: MaxNestingDepth}
+}
+
+// replyLimits don't limit replies, those come from a trusted server and may be as large as the keyspace
+var replyLimits = limits{}
+
+func (l limits) checkBulkLen(length int) error {
+	// remark: the length must leave room for the terminating CRLF
+	if length < 0 || length > math.MaxInt-2 || (l.bulkLen > 0 && int64(length) > l.bulkLen) {
+		return &ProtocolError{Reason: "invalid bulk length"}
+	}
+	return nil
+}
+
+func (l limits) checkMultibulkLen(count int) error {
+	if count < 0 || (l.multibulkLen > 0 && int64(count) > l.multibulkLen) {
+		return &ProtocolError{Reason: "invalid multibulk length"}
+	}
+	return nil
+}
+
+func (l limits) checkNestingDepth(depth int) error {
+	if l.nestingDepth > 0 && depth > l.nestingDepth {
+		return &ProtocolError{Reason: "too deep nesting of aggregate types"}
+	}
+	return nil
+}
+
+// ReadLine reads a line terminated by LF, the optional CR is removed.
+// Lines longer than MaxLineLen are rejected with ProtocolError of the reason.
+func ReadLine(r *bufio.Reader, tooLongReason string) (string, error) {
+	var line []byte
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if len(line)+len(chunk) > MaxLineLen {
+			return "", &ProtocolError{Reason: tooLongReason}
+		}
+		line = append(line, chunk...)
+		if err == bufio.ErrBufferFull {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+
+		line = line[:len(line)-1]
+		if len(line) > 0 && line[len(line)-1] == '\r' {
+			line = line[:len(line)-1]
+		}
+		return string(line), nil
+	}
+}
@@ -0,0 +1,116 @@
+package respparser
+
+import (
+	"bufio"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestProtocolLimits(t *testing.T) {
+	var tests = []struct {
+		name       string
+		input      string
+		wantReason string
+	}{
+		{
+			name:       "Huge array length should be rejected",
+			input:      "*2147483647\r\n",
+			wantReason: "invalid multibulk length",
+		},
+		{
+			name:       "Huge bulk length should be rejected",
+			input:      "*1\r\n$2147483647\r\n",
+			wantReason: "invalid bulk length",
+		},
+		{
+			name:       "Negative array length should be rejected",
+			input:      "*-2\r\n",
+			wantReason: "invalid multibulk length",
+		},
+		{
+			name:       "Array length below null should be rejected",
+			input:      "*-5\r\n",
+			wantReason: "invalid multibulk length",
+		},
+		{
+			name:       "Negative bulk length should be rejected",
+			input:      "*1\r\n$-5\r\n",
+			wantReason: "invalid bulk length",
+		},
+		{
+			name:       "Non numeric length should be rejected",
+			input:      "*x\r\n",
+			wantReason: "invalid multibulk length",
+		},
+		{
+			name:       "Huge map length should be rejected",
+			input:      "*1\r\n%1073741824\r\n",
+			wantReason: "invalid multibulk length",
+		},
+		{
+			name:       "Deep nesting should be rejected",
+			input:      strings.Repeat("*1\r\n", MaxNestingDepth+1) + ":1\r\n",
+			wantReason: "too deep nesting of aggregate types",
+		},
+		{
+			name:       "Bulk string without CRLF should be rejected",
+			input:      "*1\r\n$3\r\nabcde\r\n",
+			wantReason: "bulk string isn't terminated by CRLF",
+		},
+		{
+			name:       "Too long header line should be rejected",
+			input:      "*1\r\n+" + strings.Repeat("a", MaxLineLen) + "\r\n",
+			wantReason: "too big header line",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DeserializeArray(bufio.NewReader(strings.NewReader(tt.input)))
+			var protocolErr *ProtocolError
+			if !errors.As(err, &protocolErr) {
+				t.Fatalf("ERROR protocol error expected, but got: %v", err)
+			}
+			if protocolErr.Reason != tt.wantReason {
+				t.Errorf("ERROR got %s, want %s", protocolErr.Reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestConfiguredLimits(t *testing.T) {
+	SetMaxBulkLen(4)
+	SetMaxMultibulkLen(2)
+	t.Cleanup(func() {
+		SetMaxBulkLen(DefaultMaxBulkLen)
+		SetMaxMultibulkLen(DefaultMaxMultibulkLen)
+	})
+
+	if _, err := DeserializeArray(bufio.NewReader(strings.NewReader("*2\r\n$4\r\nabcd\r\n*-1\r\n"))); err != nil {
+		t.Errorf("ERROR result expected, but err got: %s", err.Error())
+	}
+	if _, err := DeserializeArray(bufio.NewReader(strings.NewReader("*1\r\n$5\r\nabcde\r\n"))); err == nil {
+		t.Errorf("ERROR error expected for bulk over the limit")
+	}
+	if _, err := DeserializeArray(bufio.NewReader(strings.NewReader("*3\r\n"))); err == nil {
+		t.Errorf("ERROR error expected for array over the limit")
+	}
+}
+
+func TestReplyDecoderLimits(t *testing.T) {
+	SetMaxMultibulkLen(2)
+	t.Cleanup(func() { SetMaxMultibulkLen(DefaultMaxMultibulkLen) })
+
+	input := "*3\r\n:1\r\n:2\r\n" + strings.Repeat("*1\r\n", MaxNestingDepth) + ":3\r\n"
+	if _, err := NewDecoder(strings.NewReader(input)).Decode(); !errors.Is(err, ErrProtocol) {
+		t.Errorf("ERROR protocol error expected for request over the limits, but got: %v", err)
+	}
+	reply, err := NewReplyDecoder(strings.NewReader(input)).Decode()
+	if err != nil {
+		t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+	}
+	if items := reply.(Array).Items; len(items) != 3 {
+		t.Errorf("ERROR got %d items, want 3", len(items))
+	}
+}
@@ -0,0 +1,192 @@
+package respparser
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+)
+
+type parserState int
+
+const (
+	stateIdle         parserState = iota // waiting for the first byte of a request
+	stateInline                          // reading an inline command line
+	stateMultibulkLen                    // reading *<count> header
+	stateBulkLen                         // reading $<len> header of the next argument
+	stateBulkBody                        // reading argument content followed by CRLF
+)
+
+// Parser is a resumable parser of client requests. It's fed by chunks of bytes as they
+// arrive from the network and returns requests completed so far, the partial request
+// is kept until the rest of it is fed. Requests are RESP arrays of bulk strings or inline
+// commands, each request is returned as a list of arguments.
+type Parser struct {
+	state     parserState
+	line      []byte   // incomplete header or inline line
+	args      []string // arguments of the current request
+	remaining int      // arguments of the current request still to be read
+	bulkLen   int
+	bulk      []byte // incomplete argument content
+	err       error  // protocol errors are final, nothing is parsed after them
+}
+
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+// Pending reports whether a partial request is waiting for more data
+func (p *Parser) Pending() bool {
+	return p.state != stateIdle
+}
+
+// Feed parses the chunk and returns requests completed by it. Requests completed before
+// a protocol error are returned together with the error.
+func (p *Parser) Feed(data []byte) ([][]string, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	requests := [][]string{}
+	for len(data) > 0 {
+		var request []string
+		var err error
+		request, data, err = p.step(data)
+		if err != nil {
+			utils.Log(fmt.Sprintf("(Parser) %s", err.Error()))
+			p.err = err
+			return requests, err
+		}
+		if request != nil {
+			requests = append(requests, request)
+		}
+	}
+	return requests, nil
+}
+
+// step consumes data of the current state and returns the rest, request is set once it's complete
+func (p *Parser) step(data []byte) ([]string, []byte, error) {
+	switch p.state {
+	case stateIdle:
+		if data[0] == byte(TypeArray) {
+			p.state = stateMultibulkLen
+		} else {
+			p.state = stateInline
+		}
+		return nil, data, nil
+
+	case stateInline:
+		line, rest, err := p.readLine(data, "too big inline request")
+		if line == nil || err != nil {
+			return nil, rest, err
+		}
+		p.state = stateIdle
+		args, err := utils.SplitArgs(string(line))
+		if err != nil {
+			return nil, rest, &ProtocolError{Reason: "unbalanced quotes in request"}
+		}
+		// remark: empty lines are ignored, the same as Redis does
+		if len(args) == 0 {
+			return nil, rest, nil
+		}
+		return args, rest, nil
+
+	case stateMultibulkLen:
+		line, rest, err := p.readLine(data, "too big header line")
+		if line == nil || err != nil {
+			return nil, rest, err
+		}
+		count, err := strconv.Atoi(string(line[1:]))
+		if err != nil {
+			return nil, rest, &ProtocolError{Reason: "invalid multibulk length"}
+		}
+		// remark: empty and null arrays are ignored, the same as Redis does
+		if count == 0 || count == -1 {
+			p.state = stateIdle
+			return nil, rest, nil
+		}
+		if err := requestLimits().checkMultibulkLen(count); err != nil {
+			return nil, rest, err
+		}
+		p.args = make([]string, 0, min(count, preallocLimit))
+		p.remaining = count
+		p.state = stateBulkLen
+		return nil, rest, nil
+
+	case stateBulkLen:
+		line, rest, err := p.readLine(data, "too big header line")
+		if line == nil || err != nil {
+			return nil, rest, err
+		}
+		if len(line) == 0 || line[0] != byte(TypeBulkString) {
+			got := byte('\n')
+			if len(line) > 0 {
+				got = line[0]
+			}
+			return nil, rest, &ProtocolError{Reason: fmt.Sprintf("expected '$', got '%c'", got)}
+		}
+		length, err := strconv.Atoi(string(line[1:]))
+		if err != nil {
+			return nil, rest, &ProtocolError{Reason: "invalid bulk length"}
+		}
+		if err := requestLimits().checkBulkLen(length); err != nil {
+			return nil, rest, err
+		}
+		p.bulkLen = length
+		p.bulk = make([]byte, 0, min(length, preallocLimit)+len(respSeparator))
+		p.state = stateBulkBody
+		return nil, rest, nil
+
+	case stateBulkBody:
+		missing := p.bulkLen + len(respSeparator) - len(p.bulk)
+		taken := min(missing, len(data))
+		p.bulk = append(p.bulk, data[:taken]...)
+		rest := data[taken:]
+		if taken < missing {
+			return nil, rest, nil
+		}
+
+		if !bytes.Equal(p.bulk[p.bulkLen:], respSeparator) {
+			return nil, rest, &ProtocolError{Reason: "bulk string isn't terminated by CRLF"}
+		}
+		p.args = append(p.args, string(p.bulk[:p.bulkLen]))
+		p.bulk = nil
+		p.remaining--
+		if p.remaining > 0 {
+			p.state = stateBulkLen
+			return nil, rest, nil
+		}
+
+		request := p.args
+		p.args = nil
+		p.state = stateIdle
+		return request, rest, nil
+	}
+	return nil, data, fmt.Errorf("(Parser) unknown state %d", p.state)
+}
+
+// readLine returns a complete line without its line ending and the rest of data.
+// The line is nil while its end wasn't fed yet, its beginning is kept in the parser.
+func (p *Parser) readLine(data []byte, tooLongReason string) ([]byte, []byte, error) {
+	end := bytes.IndexByte(data, '\n')
+	if end < 0 {
+		if len(p.line)+len(data) > MaxLineLen {
+			return nil, nil, &ProtocolError{Reason: tooLongReason}
+		}
+		p.line = append(p.line, data...)
+		return nil, nil, nil
+	}
+	if len(p.line)+end > MaxLineLen {
+		return nil, nil, &ProtocolError{Reason: tooLongReason}
+	}
+
+	line := append(p.line, data[:end]...)
+	p.line = nil
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	// remark: a complete line is never nil, so it can be told apart from an incomplete one
+	if line == nil {
+		line = []byte{}
+	}
+	return line, data[end+1:], nil
+}
@@ -0,0 +1,184 @@
+package respparser
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// feedInChunks feeds the input split at random positions and collects all requests
+func feedInChunks(p *Parser, input []byte, random *rand.Rand) ([][]string, error) {
+	requests := [][]string{}
+	for len(input) > 0 {
+		size := 1 + random.IntN(len(input))
+		completed, err := p.Feed(input[:size])
+		requests = append(requests, completed...)
+		if err != nil {
+			return requests, err
+		}
+		input = input[size:]
+	}
+	return requests, nil
+}
+
+func TestParserFeed(t *testing.T) {
+	var tests = []struct {
+		name       string
+		input      string
+		want       [][]string
+		wantReason string
+	}{
+		{
+			name:  "RESP requests should be parsed",
+			input: "*2\r\n$4\r\nECHO\r\n$12\r\nhello\r\nworld\r\n*1\r\n$4\r\nPING\r\n",
+			want:  [][]string{{"ECHO", "hello\r\nworld"}, {"PING"}},
+		},
+		{
+			name:  "Inline requests should be parsed",
+			input: "SET a \"b c\"\r\n\r\nGET a\n",
+			want:  [][]string{{"SET", "a", "b c"}, {"GET", "a"}},
+		},
+		{
+			name:  "Empty and null arrays should be skipped",
+			input: "*0\r\n*-1\r\n*1\r\n$0\r\n\r\n",
+			want:  [][]string{{""}},
+		},
+		{
+			name:       "Requests before protocol error should be returned",
+			input:      "PING\r\n*1\r\n:1\r\n",
+			want:       [][]string{{"PING"}},
+			wantReason: "expected '$', got ':'",
+		},
+		{
+			name:       "Huge multibulk length should be rejected",
+			input:      "*2147483647\r\n",
+			want:       [][]string{},
+			wantReason: "invalid multibulk length",
+		},
+		{
+			name:       "Multibulk length below null should be rejected",
+			input:      "*-5\r\n",
+			want:       [][]string{},
+			wantReason: "invalid multibulk length",
+		},
+		{
+			name:       "Negative multibulk length should be rejected",
+			input:      "*-2\r\n",
+			want:       [][]string{},
+			wantReason: "invalid multibulk length",
+		},
+		{
+			name:       "Negative bulk length should be rejected",
+			input:      "*1\r\n$-1\r\n",
+			want:       [][]string{},
+			wantReason: "invalid bulk length",
+		},
+		{
+			name:       "Bulk without CRLF should be rejected",
+			input:      "*1\r\n$2\r\nabc\r\n",
+			want:       [][]string{},
+			wantReason: "bulk string isn't terminated by CRLF",
+		},
+		{
+			name:       "Too long inline request should be rejected",
+			input:      strings.Repeat("a", MaxLineLen+1),
+			want:       [][]string{},
+			wantReason: "too big inline request",
+		},
+	}
+
+	random := rand.New(rand.NewPCG(3, 4))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 20 {
+				p := NewParser()
+				requests, err := feedInChunks(p, []byte(tt.input), random)
+
+				var protocolErr *ProtocolError
+				if tt.wantReason != "" {
+					if !errors.As(err, &protocolErr) || protocolErr.Reason != tt.wantReason {
+						t.Fatalf("ERROR got %v, want protocol error %s", err, tt.wantReason)
+					}
+				} else if err != nil {
+					t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+				} else if p.Pending() {
+					t.Errorf("ERROR no partial request expected")
+				}
+
+				if !slices.EqualFunc(requests, tt.want, slices.Equal) {
+					t.Fatalf("ERROR got %q, want %q", requests, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestParserKeepsPartialBulk(t *testing.T) {
+	p := NewParser()
+	requests, err := p.Feed([]byte("*2\r\n$3\r\nGET\r\n$11\r\nhello"))
+	if err != nil || len(requests) != 0 || !p.Pending() {
+		t.Fatalf("ERROR got %q, %v, want pending request", requests, err)
+	}
+
+	requests, err = p.Feed([]byte(" wor"))
+	if err != nil || len(requests) != 0 {
+		t.Fatalf("ERROR got %q, %v, want pending request", requests, err)
+	}
+
+	requests, err = p.Feed([]byte("ld\r\n"))
+	if err != nil || len(requests) != 1 || requests[0][1] != "hello world" {
+		t.Errorf("ERROR got %q, %v", requests, err)
+	}
+}
+
+func TestParserRandomSplits(t *testing.T) {
+	random := rand.New(rand.NewPCG(5, 6))
+	for range 200 {
+		want := make([][]string, 1+random.IntN(5))
+		input := []byte{}
+		for n := range want {
+			want[n] = make([]string, 1+random.IntN(4))
+			request := Array{Items: make([]RespData, len(want[n]))}
+			for m := range want[n] {
+				value := make([]byte, random.IntN(300))
+				for i := range value {
+					value[i] = byte(random.IntN(256))
+				}
+				want[n][m] = string(value)
+				request.Items[m] = BulkString{Value: want[n][m]}
+			}
+			encoded, _ := SerializeArray(request)
+			input = append(input, encoded...)
+		}
+
+		requests, err := feedInChunks(NewParser(), input, random)
+		if err != nil {
+			t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+		}
+		if !slices.EqualFunc(requests, want, slices.Equal) {
+			t.Fatalf("ERROR got %q, want %q", requests, want)
+		}
+	}
+}
+
+// FuzzParserSplits checks that splitting the input into chunks never changes the result
+func FuzzParserSplits(f *testing.F) {
+	f.Add([]byte("*2\r\n$4\r\nECHO\r\n$5\r\nhello\r\nPING\r\n"), uint64(1))
+	f.Add([]byte("*3\r\n$3\r\nSET\r\n$1\r\nk\r\n$4\r\n\x00\r\n\xff\r\n"), uint64(2))
+	f.Add([]byte("SET a \"b\\x41\"\n*0\r\n*1\r\n$-1\r\n"), uint64(3))
+	f.Add([]byte("*1\r\n$3\r\nab"), uint64(4))
+
+	f.Fuzz(func(t *testing.T, input []byte, seed uint64) {
+		whole, wholeErr := NewParser().Feed(input)
+
+		chunked, chunkedErr := feedInChunks(NewParser(), input, rand.New(rand.NewPCG(seed, seed)))
+		if (wholeErr == nil) != (chunkedErr == nil) {
+			t.Fatalf("ERROR got error %v, want %v", chunkedErr, wholeErr)
+		}
+		if !slices.EqualFunc(whole, chunked, slices.Equal) {
+			t.Fatalf("ERROR got %q, want %q", chunked, whole)
+		}
+	})
+}
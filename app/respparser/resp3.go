@@ -0,0 +1,306 @@
+package respparser
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Protocol is the RESP version spoken on a connection
+type Protocol int
+
+const (
+	Resp2 Protocol = 2
+	Resp3 Protocol = 3
+)
+
+const (
+	TypeNull           RespDataType = '_'
+	TypeBoolean        RespDataType = '#'
+	TypeDouble         RespDataType = ','
+	TypeBigNumber      RespDataType = '('
+	TypeMap            RespDataType = '%'
+	TypeSet            RespDataType = '~'
+	TypeVerbatimString RespDataType = '='
+	TypePush           RespDataType = '>'
+	TypeAttribute      RespDataType = '|'
+)
+
+type Null struct{}
+
+func (n Null) Type() RespDataType  { return TypeNull }
+func (n Null) String() string      { return "" }
+func (n Null) DebugString() string { return "Null" }
+
+type Boolean struct {
+	Value bool
+}
+
+func (b Boolean) Type() RespDataType  { return TypeBoolean }
+func (b Boolean) String() string      { return strconv.FormatBool(b.Value) }
+func (b Boolean) DebugString() string { return fmt.Sprintf("Boolean: %t", b.Value) }
+
+type Double struct {
+	Value float64
+}
+
+func (d Double) Type() RespDataType { return TypeDouble }
+func (d Double) String() string {
+	switch {
+	case math.IsInf(d.Value, 1):
+		return "inf"
+	case math.IsInf(d.Value, -1):
+		return "-inf"
+	case math.IsNaN(d.Value):
+		return "nan"
+	}
+	return strconv.FormatFloat(d.Value, 'g', -1, 64)
+}
+func (d Double) DebugString() string { return fmt.Sprintf("Double: %s", d.String()) }
+
+// BigNumber holds a signed integer of arbitrary size in its decimal form
+type BigNumber struct {
+	Value string
+}
+
+func (b BigNumber) Type() RespDataType  { return TypeBigNumber }
+func (b BigNumber) String() string      { return b.Value }
+func (b BigNumber) DebugString() string { return fmt.Sprintf("Big number: %s", b.Value) }
+
+type MapItem struct {
+	Key   RespData
+	Value RespData
+}
+
+// Map keeps items in the order they were added, RESP2 clients get a flat array of keys and values
+type Map struct {
+	Items []MapItem
+}
+
+func (m Map) Type() RespDataType { return TypeMap }
+func (m Map) String() string {
+	itemsString := make([]string, len(m.Items))
+	for n, item := range m.Items {
+		itemsString[n] = fmt.Sprintf("%s:%s", item.Key.String(), item.Value.String())
+	}
+	return fmt.Sprintf("{%s}", strings.Join(itemsString, ","))
+}
+func (m Map) DebugString() string { return fmt.Sprintf("Map: %s", m.String()) }
+
+// Add appends a bulk string key with the value
+func (m *Map) Add(key string, value RespData) {
+	m.Items = append(m.Items, MapItem{Key: BulkString{Value: key}, Value: value})
+}
+
+type Set struct {
+	Items []RespData
+}
+
+func (s Set) Type() RespDataType  { return TypeSet }
+func (s Set) String() string      { return Array{Items: s.Items}.String() }
+func (s Set) DebugString() string { return fmt.Sprintf("Set: %s", s.String()) }
+
+// VerbatimString is a string with a three letters format, e.g. txt or mkd
+type VerbatimString struct {
+	Format string
+	Value  string
+}
+
+func (v VerbatimString) Type() RespDataType { return TypeVerbatimString }
+func (v VerbatimString) String() string     { return v.Value }
+func (v VerbatimString) DebugString() string {
+	return fmt.Sprintf("Verbatim string (%s): %s", v.Format, v.Value)
+}
+
+// Push is an out of band message, e.g. a pub/sub message
+type Push struct {
+	Items []RespData
+}
+
+func (p Push) Type() RespDataType  { return TypePush }
+func (p Push) String() string      { return Array{Items: p.Items}.String() }
+func (p Push) DebugString() string { return fmt.Sprintf("Push: %s", p.String()) }
+
+// Attribute carries auxiliary data of the reply in Value, RESP2 clients get the Value only
+type Attribute struct {
+	Items []MapItem
+	Value RespData
+}
+
+func (a Attribute) Type() RespDataType { return TypeAttribute }
+func (a Attribute) String() string     { return a.Value.String() }
+func (a Attribute) DebugString() string {
+	return fmt.Sprintf("Attribute: %s %s", Map{Items: a.Items}.String(), a.Value.DebugString())
+}
+
+func readItems(r *bufio.Reader, lim limits, count int, depth int) ([]RespData, error) {
+	items := make([]RespData, 0, min(count, preallocLimit))
+	for range count {
+		item, err := deserialize(r, lim, depth)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func readMapItems(r *bufio.Reader, lim limits, count int, depth int) ([]MapItem, error) {
+	if count > math.MaxInt/2 {
+		return nil, &ProtocolError{Reason: "invalid multibulk length"}
+	}
+	if err := lim.checkMultibulkLen(2 * count); err != nil {
+		return nil, err
+	}
+	items, err := readItems(r, lim, 2*count, depth)
+	if err != nil {
+		return nil, err
+	}
+	mapItems := make([]MapItem, count)
+	for n := range count {
+		mapItems[n] = MapItem{Key: items[2*n], Value: items[2*n+1]}
+	}
+	return mapItems, nil
+}
+
+func DeserializeNull(r *bufio.Reader) (Null, error) {
+	line, err := readTypedLine(r, TypeNull)
+	if err == nil && line != "" {
+		err = &ProtocolError{Reason: fmt.Sprintf("invalid null %q", line)}
+	}
+	return Null{}, err
+}
+
+func DeserializeBoolean(r *bufio.Reader) (Boolean, error) {
+	line, err := readTypedLine(r, TypeBoolean)
+	if err != nil {
+		return Boolean{}, err
+	}
+	switch line {
+	case "t":
+		return Boolean{Value: true}, nil
+	case "f":
+		return Boolean{Value: false}, nil
+	default:
+		return Boolean{}, &ProtocolError{Reason: fmt.Sprintf("invalid boolean %q", line)}
+	}
+}
+
+func DeserializeDouble(r *bufio.Reader) (Double, error) {
+	line, err := readTypedLine(r, TypeDouble)
+	if err != nil {
+		return Double{}, err
+	}
+	// remark: ParseFloat accepts inf, -inf and nan as well
+	value, err := strconv.ParseFloat(line, 64)
+	if err != nil {
+		return Double{}, &ProtocolError{Reason: fmt.Sprintf("invalid double %q", line)}
+	}
+	return Double{Value: value}, nil
+}
+
+func DeserializeBigNumber(r *bufio.Reader) (BigNumber, error) {
+	line, err := readTypedLine(r, TypeBigNumber)
+	if err != nil {
+		return BigNumber{}, err
+	}
+	digits := strings.TrimPrefix(strings.TrimPrefix(line, "-"), "+")
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return BigNumber{}, &ProtocolError{Reason: fmt.Sprintf("invalid big number %q", line)}
+	}
+	return BigNumber{Value: line}, nil
+}
+
+func DeserializeVerbatimString(r *bufio.Reader) (VerbatimString, error) {
+	return deserializeVerbatimString(r, requestLimits())
+}
+
+func deserializeVerbatimString(r *bufio.Reader, lim limits) (VerbatimString, error) {
+	line, err := readTypedLine(r, TypeVerbatimString)
+	if err != nil {
+		return VerbatimString{}, err
+	}
+	length, err := strconv.Atoi(line)
+	if err != nil {
+		return VerbatimString{}, &ProtocolError{Reason: "invalid bulk length"}
+	}
+	content, err := readBulkContent(r, lim, length)
+	if err != nil {
+		return VerbatimString{}, err
+	}
+	if length < 4 || content[3] != ':' {
+		return VerbatimString{}, &ProtocolError{Reason: "verbatim string must start with a format followed by ':'"}
+	}
+	return VerbatimString{Format: string(content[:3]), Value: string(content[4:])}, nil
+}
+
+func DeserializeMap(r *bufio.Reader) (Map, error) {
+	return deserializeMap(r, requestLimits(), 0)
+}
+
+func deserializeMap(r *bufio.Reader, lim limits, depth int) (Map, error) {
+	if err := lim.checkNestingDepth(depth + 1); err != nil {
+		return Map{}, err
+	}
+	count, err := readLength(r, lim, TypeMap)
+	if err != nil {
+		return Map{}, err
+	}
+	items, err := readMapItems(r, lim, count, depth+1)
+	return Map{Items: items}, err
+}
+
+func DeserializeSet(r *bufio.Reader) (Set, error) {
+	return deserializeSet(r, requestLimits(), 0)
+}
+
+func deserializeSet(r *bufio.Reader, lim limits, depth int) (Set, error) {
+	if err := lim.checkNestingDepth(depth + 1); err != nil {
+		return Set{}, err
+	}
+	count, err := readLength(r, lim, TypeSet)
+	if err != nil {
+		return Set{}, err
+	}
+	items, err := readItems(r, lim, count, depth+1)
+	return Set{Items: items}, err
+}
+
+func DeserializePush(r *bufio.Reader) (Push, error) {
+	return deserializePush(r, requestLimits(), 0)
+}
+
+func deserializePush(r *bufio.Reader, lim limits, depth int) (Push, error) {
+	if err := lim.checkNestingDepth(depth + 1); err != nil {
+		return Push{}, err
+	}
+	count, err := readLength(r, lim, TypePush)
+	if err != nil {
+		return Push{}, err
+	}
+	items, err := readItems(r, lim, count, depth+1)
+	return Push{Items: items}, err
+}
+
+// DeserializeAttribute reads the attribute together with the reply it belongs to
+func DeserializeAttribute(r *bufio.Reader) (Attribute, error) {
+	return deserializeAttribute(r, requestLimits(), 0)
+}
+
+func deserializeAttribute(r *bufio.Reader, lim limits, depth int) (Attribute, error) {
+	if err := lim.checkNestingDepth(depth + 1); err != nil {
+		return Attribute{}, err
+	}
+	count, err := readLength(r, lim, TypeAttribute)
+	if err != nil {
+		return Attribute{}, err
+	}
+	items, err := readMapItems(r, lim, count, depth+1)
+	if err != nil {
+		return Attribute{}, err
+	}
+	value, err := deserialize(r, lim, depth)
+	return Attribute{Items: items, Value: value}, err
+}
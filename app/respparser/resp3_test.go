@@ -0,0 +1,124 @@
+package respparser
+
+import (
+	"bufio"
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestSerializeProtocol(t *testing.T) {
+	fields := Map{}
+	fields.Add("temperature", BulkString{Value: "36"})
+	fields.Add("humidity", Double{Value: 95.5})
+
+	var tests = []struct {
+		name      string
+		input     RespData
+		wantResp2 string
+		wantResp3 string
+	}{
+		{
+			name:      "Map should be flattened for RESP2",
+			input:     fields,
+			wantResp2: "*4\r\n$11\r\ntemperature\r\n$2\r\n36\r\n$8\r\nhumidity\r\n$4\r\n95.5\r\n",
+			wantResp3: "%2\r\n$11\r\ntemperature\r\n$2\r\n36\r\n$8\r\nhumidity\r\n,95.5\r\n",
+		},
+		{
+			name:      "Nulls should be RESP3 null",
+			input:     Array{Items: []RespData{Null{}, BulkString{IsNull: true}, Array{IsNull: true}}},
+			wantResp2: "*3\r\n$-1\r\n$-1\r\n*-1\r\n",
+			wantResp3: "*3\r\n_\r\n_\r\n_\r\n",
+		},
+		{
+			name:      "Boolean should be integer for RESP2",
+			input:     Set{Items: []RespData{Boolean{Value: true}, Boolean{Value: false}}},
+			wantResp2: "*2\r\n:1\r\n:0\r\n",
+			wantResp3: "~2\r\n#t\r\n#f\r\n",
+		},
+		{
+			name:      "Big number and infinity should be bulk strings for RESP2",
+			input:     Push{Items: []RespData{BigNumber{Value: "-3492890328409238509324850943850943825024385"}, Double{Value: math.Inf(-1)}}},
+			wantResp2: "*2\r\n$44\r\n-3492890328409238509324850943850943825024385\r\n$4\r\n-inf\r\n",
+			wantResp3: ">2\r\n(-3492890328409238509324850943850943825024385\r\n,-inf\r\n",
+		},
+		{
+			name:      "Verbatim string should be bulk string for RESP2",
+			input:     VerbatimString{Format: "txt", Value: "Some string"},
+			wantResp2: "$11\r\nSome string\r\n",
+			wantResp3: "=15\r\ntxt:Some string\r\n",
+		},
+		{
+			name:      "Attribute should be dropped for RESP2",
+			input:     Attribute{Items: []MapItem{{Key: SimpleString{Value: "ttl"}, Value: Integer{Value: 3600}}}, Value: Integer{Value: 1}},
+			wantResp2: ":1\r\n",
+			wantResp3: "|1\r\n+ttl\r\n:3600\r\n:1\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp2, err := SerializeProtocol(tt.input, Resp2)
+			if err != nil {
+				t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+			}
+			if string(resp2) != tt.wantResp2 {
+				t.Errorf("ERROR got %q, want %q", resp2, tt.wantResp2)
+			}
+
+			resp3, err := SerializeProtocol(tt.input, Resp3)
+			if err != nil {
+				t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+			}
+			if string(resp3) != tt.wantResp3 {
+				t.Errorf("ERROR got %q, want %q", resp3, tt.wantResp3)
+			}
+		})
+	}
+}
+
+func TestDeserializeResp3(t *testing.T) {
+	var tests = []struct {
+		name  string
+		input string
+		want  string // debug string of the parsed value
+	}{
+		{
+			name:  "Map should be parsed",
+			input: "%2\r\n+first\r\n:1\r\n+second\r\n#t\r\n",
+			want:  "Map: {first:1,second:true}",
+		},
+		{
+			name:  "Set with double and null should be parsed",
+			input: "~3\r\n,1.5\r\n,inf\r\n_\r\n",
+			want:  "Set: [1.5,inf,]",
+		},
+		{
+			name:  "Verbatim string should be parsed",
+			input: "=15\r\ntxt:Some string\r\n",
+			want:  "Verbatim string (txt): Some string",
+		},
+		{
+			name:  "Attribute should be parsed together with the reply",
+			input: "|1\r\n+ttl\r\n:3600\r\n(12345678901234567890\r\n",
+			want:  "Attribute: {ttl:3600} Big number: 12345678901234567890",
+		},
+		{
+			name:  "Push should be parsed",
+			input: ">2\r\n+message\r\n+hello\r\n",
+			want:  "Push: [message,hello]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ans, err := Deserialize(bufio.NewReader(bytes.NewReader([]byte(tt.input))))
+			if err != nil {
+				t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+			}
+			if ans.DebugString() != tt.want {
+				t.Errorf("ERROR got %s, want %s", ans.DebugString(), tt.want)
+			}
+		})
+	}
+}
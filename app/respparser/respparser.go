@@ -0,0 +1,298 @@
+// Package respparser encodes and decodes RESP2 and RESP3 data, it's shared by the server and the client package
+// so users of the client can inspect replies and push messages.
+package respparser
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
+)
+
+type RespDataType byte
+
+const (
+	TypeArray        RespDataType = '*'
+	TypeBulkString   RespDataType = '$'
+	TypeInteger      RespDataType = ':'
+	TypeSimpleError  RespDataType = '-'
+	TypeSimpleString RespDataType = '+'
+)
+
+type RespData interface {
+	Type() RespDataType
+	String() string
+	DebugString() string
+}
+
+type SimpleString struct {
+	Value string
+}
+
+func (s SimpleString) Type() RespDataType  { return TypeSimpleString }
+func (s SimpleString) String() string      { return s.Value }
+func (s SimpleString) DebugString() string { return fmt.Sprintf("Simple string: %s", s.Value) }
+
+type Integer struct {
+	Value int
+}
+
+func (s Integer) Type() RespDataType { return TypeInteger }
+func (s Integer) String() string     { return strconv.Itoa(s.Value) }
+func (s Integer) DebugString() string {
+	numStr := strconv.Itoa(s.Value)
+	return fmt.Sprintf("Integer: %s", numStr)
+}
+
+type SimpleError struct {
+	Value string
+}
+
+func (s SimpleError) Type() RespDataType { return TypeSimpleError }
+func (s SimpleError) String() string     { return s.Value }
+func (s SimpleError) DebugString() string {
+	return fmt.Sprintf("Simple error: %s", s.Value)
+}
+
+// Code returns the first word of the error, e.g. ERR or WRONGTYPE
+func (s SimpleError) Code() string {
+	code, _, _ := strings.Cut(s.Value, " ")
+	return code
+}
+
+type BulkString struct {
+	Value  string
+	IsNull bool
+}
+
+func (s BulkString) Type() RespDataType { return TypeBulkString }
+func (s BulkString) String() string     { return s.Value }
+func (s BulkString) DebugString() string {
+	return fmt.Sprintf("Bulk string: %s", s.Value)
+}
+
+type Array struct {
+	Items  []RespData
+	IsNull bool
+}
+
+func (a Array) Type() RespDataType { return TypeArray }
+func (a Array) String() string {
+	itemsString := make([]string, len(a.Items))
+	for n, respData := range a.Items {
+		itemsString[n] = respData.String()
+	}
+	return fmt.Sprintf("[%s]", strings.Join(itemsString, ","))
+}
+func (a Array) DebugString() string {
+	itemsString := make([]string, len(a.Items))
+	for n, respData := range a.Items {
+		itemsString[n] = respData.String()
+	}
+	return fmt.Sprintf("Array: [%s]", strings.Join(itemsString, ","))
+}
+
+var respSeparator = []byte("\r\n")
+
+// ErrProtocol matches every ProtocolError with errors.Is
+var ErrProtocol = errors.New("Protocol error")
+
+// ProtocolError is returned for malformed data, the stream can't be read any further
+type ProtocolError struct {
+	Reason string
+}
+
+func (e *ProtocolError) Error() string {
+	return "Protocol error: " + e.Reason
+}
+
+func (e *ProtocolError) Is(target error) bool {
+	return target == ErrProtocol
+}
+
+// readTypedLine reads a line of the expected type and returns it without the type byte
+func readTypedLine(r *bufio.Reader, dataType RespDataType) (string, error) {
+	line, err := ReadLine(r, "too big header line")
+	if err != nil {
+		return "", err
+	}
+	if len(line) == 0 || line[0] != byte(dataType) {
+		return "", &ProtocolError{Reason: fmt.Sprintf("expected '%c', got %q", dataType, line)}
+	}
+	return line[1:], nil
+}
+
+// readLength reads header of an aggregate type, nulls aren't allowed
+func readLength(r *bufio.Reader, lim limits, dataType RespDataType) (int, error) {
+	line, err := readTypedLine(r, dataType)
+	if err != nil {
+		return 0, err
+	}
+	length, err := strconv.Atoi(line)
+	if err != nil {
+		return 0, &ProtocolError{Reason: "invalid multibulk length"}
+	}
+	return length, lim.checkMultibulkLen(length)
+}
+
+// Deserialize reads a single value, see Decoder.Decode for returned errors
+func Deserialize(r *bufio.Reader) (RespData, error) {
+	return NewDecoder(r).Decode()
+}
+
+// deserialize reads any type within the limits, depth is the number of enclosing aggregate types
+func deserialize(r *bufio.Reader, lim limits, depth int) (RespData, error) {
+	dataType, err := r.Peek(1)
+	if err != nil {
+		utils.Log(fmt.Sprintf("(Deserialize) Read buffer peek error %s", err.Error()))
+		return SimpleError{}, err
+	}
+
+	switch dataType[0] {
+	case byte(TypeSimpleString):
+		return DeserializeSimpleString(r)
+	case byte(TypeInteger):
+		return DeserializeInteger(r)
+	case byte(TypeSimpleError):
+		return DeserializeSimpleError(r)
+	case byte(TypeBulkString):
+		return deserializeBulkString(r, lim)
+	case byte(TypeArray):
+		return deserializeArray(r, lim, depth)
+	case byte(TypeNull):
+		return DeserializeNull(r)
+	case byte(TypeBoolean):
+		return DeserializeBoolean(r)
+	case byte(TypeDouble):
+		return DeserializeDouble(r)
+	case byte(TypeBigNumber):
+		return DeserializeBigNumber(r)
+	case byte(TypeVerbatimString):
+		return deserializeVerbatimString(r, lim)
+	case byte(TypeMap):
+		return deserializeMap(r, lim, depth)
+	case byte(TypeSet):
+		return deserializeSet(r, lim, depth)
+	case byte(TypePush):
+		return deserializePush(r, lim, depth)
+	case byte(TypeAttribute):
+		return deserializeAttribute(r, lim, depth)
+	default:
+		return SimpleError{}, &ProtocolError{Reason: fmt.Sprintf("unexpected type byte %q", dataType[0])}
+	}
+}
+
+func DeserializeArray(r *bufio.Reader) (Array, error) {
+	return deserializeArray(r, requestLimits(), 0)
+}
+
+func deserializeArray(r *bufio.Reader, lim limits, depth int) (Array, error) {
+	if err := lim.checkNestingDepth(depth + 1); err != nil {
+		return Array{}, err
+	}
+
+	line, err := readTypedLine(r, TypeArray)
+	if err != nil {
+		utils.Log(fmt.Sprintf("(DeserializeArray) Next line read error %s", err.Error()))
+		return Array{}, err
+	}
+
+	numOfElements, err := strconv.Atoi(line)
+	if err != nil {
+		return Array{}, &ProtocolError{Reason: "invalid multibulk length"}
+	} else if numOfElements == -1 {
+		return Array{IsNull: true}, nil
+	} else if err := lim.checkMultibulkLen(numOfElements); err != nil {
+		return Array{}, err
+	}
+
+	items, err := readItems(r, lim, numOfElements, depth+1)
+	if err != nil {
+		utils.Log(fmt.Sprintf("(DeserializeArray) Deserialization ends with error error %s", err.Error()))
+		return Array{}, err
+	}
+	return Array{Items: items}, nil
+}
+
+// DeserializeBulkString reads exactly the announced number of bytes, so the content may hold any bytes including CRLF
+func DeserializeBulkString(r *bufio.Reader) (BulkString, error) {
+	return deserializeBulkString(r, requestLimits())
+}
+
+func deserializeBulkString(r *bufio.Reader, lim limits) (BulkString, error) {
+	line, err := readTypedLine(r, TypeBulkString)
+	if err != nil {
+		utils.Log(fmt.Sprintf("(DeserializeBulkString) Next line read error %s", err.Error()))
+		return BulkString{}, err
+	}
+
+	bulkStringLength, err := strconv.Atoi(line)
+	if err != nil {
+		return BulkString{}, &ProtocolError{Reason: "invalid bulk length"}
+	} else if bulkStringLength == -1 {
+		return BulkString{IsNull: true}, nil
+	}
+
+	content, err := readBulkContent(r, lim, bulkStringLength)
+	if err != nil {
+		return BulkString{}, err
+	}
+	return BulkString{Value: string(content)}, nil
+}
+
+// readBulkContent reads the content followed by CRLF. Memory grows with data really received.
+func readBulkContent(r *bufio.Reader, lim limits, length int) ([]byte, error) {
+	if err := lim.checkBulkLen(length); err != nil {
+		return nil, err
+	}
+
+	var content bytes.Buffer
+	content.Grow(min(length, preallocLimit) + len(respSeparator))
+	if _, err := io.CopyN(&content, r, int64(length+len(respSeparator))); err != nil {
+		utils.Log(fmt.Sprintf("(readBulkContent) Content read error %s", err.Error()))
+		return nil, err
+	}
+
+	if !bytes.Equal(content.Bytes()[length:], respSeparator) {
+		return nil, &ProtocolError{Reason: "bulk string isn't terminated by CRLF"}
+	}
+	return content.Bytes()[:length], nil
+}
+
+func DeserializeInteger(r *bufio.Reader) (Integer, error) {
+	line, err := readTypedLine(r, TypeInteger)
+	if err != nil {
+		utils.Log(fmt.Sprintf("(DeserializeInteger) Next line read error %s", err.Error()))
+		return Integer{}, err
+	}
+
+	intValue, err := strconv.Atoi(line)
+	if err != nil {
+		return Integer{}, &ProtocolError{Reason: fmt.Sprintf("invalid integer %q", line)}
+	}
+
+	return Integer{Value: intValue}, nil
+}
+
+func DeserializeSimpleString(r *bufio.Reader) (SimpleString, error) {
+	line, err := readTypedLine(r, TypeSimpleString)
+	if err != nil {
+		utils.Log(fmt.Sprintf("(DeserializeSimpleString) Next line read error %s", err.Error()))
+		return SimpleString{}, err
+	}
+	return SimpleString{Value: line}, nil
+}
+
+func DeserializeSimpleError(r *bufio.Reader) (SimpleError, error) {
+	line, err := readTypedLine(r, TypeSimpleError)
+	if err != nil {
+		utils.Log(fmt.Sprintf("(DeserializeSimpleError) Next line read error %s", err.Error()))
+		return SimpleError{}, err
+	}
+	return SimpleError{Value: line}, nil
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"math/rand/v2"
 	"testing"
 
 	"github.com/codecrafters-io/redis-starter-go/app/internal/utils"
@@ -30,6 +31,16 @@ func TestDecodeBulkStrings(t *testing.T) {
 			input: []byte("$1\r\n*\r\n"),
 			want:  BulkString{Value: "*"},
 		},
+		{
+			name:  "Bulk string containing CRLF should be parsed",
+			input: []byte("$12\r\nhello\r\nworld\r\n"),
+			want:  BulkString{Value: "hello\r\nworld"},
+		},
+		{
+			name:  "Null bulk string should be parsed",
+			input: []byte("$-1\r\n"),
+			want:  BulkString{IsNull: true},
+		},
 	}
 
 	for _, tt := range tests {
@@ -41,13 +52,40 @@ func TestDecodeBulkStrings(t *testing.T) {
 				t.Errorf("ERROR result expected, but err got: %s", err.Error())
 			}
 
-			if ans.Value != tt.want.Value {
+			if ans != tt.want {
 				t.Errorf("ERROR got %v, want %v", ans, tt.want)
 			}
 		})
 	}
 }
 
+func TestBulkStringBinaryRoundTrip(t *testing.T) {
+	random := rand.New(rand.NewPCG(1, 2))
+	inputs := [][]byte{{}, []byte("\r\n"), []byte("\x00\xff\r\n$-1\r\n*2\r\n")}
+	for range 100 {
+		input := make([]byte, random.IntN(512))
+		for n := range input {
+			input[n] = byte(random.UintN(256))
+		}
+		inputs = append(inputs, input)
+	}
+
+	for _, input := range inputs {
+		encoded, err := Serialize(Array{Items: []RespData{BulkString{Value: string(input)}, BulkString{Value: "tail"}}})
+		if err != nil {
+			t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+		}
+
+		ans, err := DeserializeArray(bufio.NewReader(bytes.NewReader(encoded)))
+		if err != nil {
+			t.Fatalf("ERROR result expected, but err got: %s", err.Error())
+		}
+		if len(ans.Items) != 2 || ans.Items[0].String() != string(input) || ans.Items[1].String() != "tail" {
+			t.Errorf("ERROR got %q, want %q", ans.Items, input)
+		}
+	}
+}
+
 func TestEncodeBulkStrings(t *testing.T) {
 	var tests = []struct {
 		name  string